@@ -0,0 +1,324 @@
+package main
+
+import (
+	"arabella-api/internal/app/models"
+	"arabella-api/internal/app/repositories"
+	"arabella-api/internal/app/services"
+	"arabella-api/internal/database"
+	"arabella-api/internal/platform/config"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+)
+
+// stressTotals accumulates the balance changes a worker expects from the operations that succeeded
+type stressTotals struct {
+	checking decimal.Decimal
+	savings  decimal.Decimal
+	expenses decimal.Decimal
+	income   decimal.Decimal
+	posted   int
+	reversed int
+	failed   int
+}
+
+func (t *stressTotals) add(other stressTotals) {
+	t.checking = t.checking.Add(other.checking)
+	t.savings = t.savings.Add(other.savings)
+	t.expenses = t.expenses.Add(other.expenses)
+	t.income = t.income.Add(other.income)
+	t.posted += other.posted
+	t.reversed += other.reversed
+	t.failed += other.failed
+}
+
+// runLedgerStress hammers the accounting engine from parallel workers on the same two accounts
+// of a throwaway user, then checks the cached balances against the expected totals and the journal.
+// The run writes to the configured database: a successful run deletes the throwaway user and all
+// its data, a failed one keeps them for inspection and exits with status 1.
+func runLedgerStress(cmd *cobra.Command, args []string) {
+	// Load configuration
+	cfg := config.Load()
+
+	// Initialize database
+	db, err := database.InitDB(cfg)
+	if err != nil {
+		log.Fatalf("❌ Error connecting to database: %v", err)
+	}
+	defer database.CloseDB()
+
+	workers, _ := cmd.Flags().GetInt("workers")
+	perWorker, _ := cmd.Flags().GetInt("transactions")
+	if workers < 1 || perWorker < 1 {
+		log.Fatalf("❌ --workers and --transactions must be positive")
+	}
+
+	stress, err := newLedgerStress(db)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	log.Printf("🔄 Stress user %d: %d workers x %d transactions on the same accounts...", stress.userID, workers, perWorker)
+
+	started := time.Now()
+	expected := stress.run(workers, perWorker)
+
+	log.Printf("   Elapsed:                   %s", time.Since(started).Round(time.Millisecond))
+	log.Printf("   Transactions posted:       %d", expected.posted)
+	log.Printf("   Transactions reversed:     %d", expected.reversed)
+	log.Printf("   Operations failed:         %d", expected.failed)
+
+	problems, err := stress.check(expected)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	for _, problem := range problems {
+		log.Printf("⚠️  %s", problem)
+	}
+
+	if len(problems) > 0 {
+		log.Printf("❌ Stress run failed (user %d kept for inspection)", stress.userID)
+		os.Exit(1)
+	}
+
+	if err := purgeStressUser(db, stress.userID); err != nil {
+		log.Fatalf("❌ Balances are consistent, but the stress user %d could not be deleted: %v", stress.userID, err)
+	}
+
+	log.Printf("✅ Balances are consistent after the concurrent run (stress user %d deleted)", stress.userID)
+}
+
+// ledgerStress is the throwaway user of a stress run with the accounts and categories the workers post to
+type ledgerStress struct {
+	db             *gorm.DB
+	engine         services.AccountingEngineService
+	accountRepo    repositories.AccountRepository
+	userID         uint
+	openingBalance decimal.Decimal
+	checking       *models.Account
+	savings        *models.Account
+	expense        *models.Category
+	income         *models.Category
+}
+
+// newLedgerStress creates a dedicated user, so the run only ever writes (and later deletes) its own data,
+// with a funded checking account, an empty savings account and an expense and an income category.
+// If the setup fails once the user exists, the partial run is returned with the error so it can be purged.
+func newLedgerStress(db *gorm.DB) (*ledgerStress, error) {
+	accountRepo := repositories.NewAccountRepository(db)
+	stress := &ledgerStress{
+		db: db,
+		engine: services.NewAccountingEngineService(
+			db,
+			repositories.NewJournalEntryRepository(db),
+			accountRepo,
+			repositories.NewTransactionRepository(db),
+		),
+		accountRepo:    accountRepo,
+		openingBalance: decimal.NewFromInt(1000000),
+	}
+
+	currency, err := repositories.NewCurrencyRepository(db).FindByCode("USD")
+	if err != nil {
+		return nil, fmt.Errorf("USD currency not found (run the seeders first): %w", err)
+	}
+
+	stamp := time.Now().UnixNano()
+	user := &models.User{
+		UserName:        fmt.Sprintf("ledger-stress-%d", stamp),
+		Email:           fmt.Sprintf("ledger-stress-%d@example.invalid", stamp),
+		PasswordHash:    "!", // Not a bcrypt hash: the user cannot log in
+		DefaultCurrency: "USD",
+	}
+	if err := repositories.NewUserRepository(db).Create(user); err != nil {
+		return nil, fmt.Errorf("failed to create stress user: %w", err)
+	}
+	stress.userID = user.ID
+
+	stress.checking = &models.Account{UserID: user.ID, Name: "Stress checking", AccountType: "BANK", CurrencyID: &currency.ID}
+	stress.savings = &models.Account{UserID: user.ID, Name: "Stress savings", AccountType: "SAVINGS", CurrencyID: &currency.ID}
+	for _, account := range []*models.Account{stress.checking, stress.savings} {
+		balance := decimal.Zero
+		if account == stress.checking {
+			balance = stress.openingBalance
+		}
+		if err := stress.engine.OpenAccount(account, balance, time.Now()); err != nil {
+			return stress, fmt.Errorf("failed to open %s: %w", account.Name, err)
+		}
+	}
+
+	categoryRepo := repositories.NewCategoryRepository(db)
+	stress.expense = &models.Category{UserID: user.ID, Name: "Stress expense", Type: "EXPENSE", IsActive: true}
+	stress.income = &models.Category{UserID: user.ID, Name: "Stress income", Type: "INCOME", IsActive: true}
+	for _, category := range []*models.Category{stress.expense, stress.income} {
+		if err := categoryRepo.Create(category); err != nil {
+			return stress, fmt.Errorf("failed to create category %s: %w", category.Name, err)
+		}
+	}
+
+	return stress, nil
+}
+
+// run posts perWorker transactions from each of workers parallel workers and returns the balance
+// changes the successful operations add up to. Every worker mixes expenses, transfers and incomes
+// on the same accounts, and voids every fifth expense it posted, so postings and reversals contend
+// for the same rows.
+func (s *ledgerStress) run(workers, perWorker int) stressTotals {
+	results := make([]stressTotals, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			totals := &results[w]
+
+			for i := 0; i < perWorker; i++ {
+				amount := decimal.NewFromInt(int64(1 + (w+i)%7)).Add(decimal.New(25, -2))
+				tx := &models.Transaction{
+					UserID:          s.userID,
+					Amount:          amount,
+					TransactionDate: time.Now(),
+				}
+
+				switch i % 3 {
+				case 0:
+					tx.Type = "EXPENSE"
+					tx.Description = fmt.Sprintf("Stress expense w%d #%d", w, i)
+					tx.AccountFromID = s.checking.ID
+					tx.CategoryID = &s.expense.ID
+				case 1:
+					tx.Type = "TRANSFER"
+					tx.Description = fmt.Sprintf("Stress transfer w%d #%d", w, i)
+					tx.AccountFromID = s.checking.ID
+					tx.AccountToID = &s.savings.ID
+				default:
+					tx.Type = "INCOME"
+					tx.Description = fmt.Sprintf("Stress income w%d #%d", w, i)
+					tx.AccountFromID = s.savings.ID
+					tx.CategoryID = &s.income.ID
+				}
+
+				if err := s.engine.ProcessTransaction(tx); err != nil {
+					log.Printf("⚠️  Worker %d: %s failed: %v", w, tx.Description, err)
+					totals.failed++
+					continue
+				}
+				totals.posted++
+
+				reverse := tx.Type == "EXPENSE" && i%5 == 0
+				if reverse {
+					if err := s.engine.ReverseTransaction(tx.ID); err != nil {
+						log.Printf("⚠️  Worker %d: reversal of transaction %d failed: %v", w, tx.ID, err)
+						totals.failed++
+						reverse = false
+					} else {
+						totals.reversed++
+					}
+				}
+				if reverse {
+					continue
+				}
+
+				switch tx.Type {
+				case "EXPENSE":
+					totals.checking = totals.checking.Sub(amount)
+					totals.expenses = totals.expenses.Add(amount)
+				case "TRANSFER":
+					totals.checking = totals.checking.Sub(amount)
+					totals.savings = totals.savings.Add(amount)
+				case "INCOME":
+					totals.savings = totals.savings.Add(amount)
+					totals.income = totals.income.Add(amount)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	var expected stressTotals
+	for _, totals := range results {
+		expected.add(totals)
+	}
+
+	return expected
+}
+
+// check compares the cached balances with the expected totals and verifies the user's journal.
+// It returns one message per problem found; none means the run left the ledger consistent.
+func (s *ledgerStress) check(expected stressTotals) ([]string, error) {
+	var problems []string
+	if expected.failed > 0 {
+		problems = append(problems, fmt.Sprintf("%d operation(s) failed", expected.failed))
+	}
+
+	checks := []struct {
+		accountID uint
+		label     string
+		expected  decimal.Decimal
+	}{
+		{s.checking.ID, s.checking.Name, s.openingBalance.Add(expected.checking)},
+		{s.savings.ID, s.savings.Name, expected.savings},
+		{*s.expense.LedgerAccountID, s.expense.Name, expected.expenses},
+		{*s.income.LedgerAccountID, s.income.Name, expected.income},
+	}
+
+	for _, check := range checks {
+		account, err := s.accountRepo.FindByID(check.accountID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reload account %d: %w", check.accountID, err)
+		}
+
+		if account.Balance.Equal(check.expected) {
+			log.Printf("   %-26s %s (version %d)", check.label+":", account.Balance.String(), account.Version)
+			continue
+		}
+
+		problems = append(problems, fmt.Sprintf("%s: cached=%s expected=%s (lost %s)",
+			check.label, account.Balance.String(), check.expected.String(), check.expected.Sub(account.Balance).String()))
+	}
+
+	// The journal must agree with the cache and every transaction must balance
+	report, err := newLedgerService(s.db).Verify(&s.userID, false)
+	if err != nil {
+		return nil, fmt.Errorf("ledger verification error: %w", err)
+	}
+	if !report.IsClean() {
+		problems = append(problems, fmt.Sprintf("ledger verification found %d drifted account(s) and %d unbalanced transaction(s)",
+			len(report.AccountDrifts), len(report.UnbalancedTransactions)))
+	}
+
+	return problems, nil
+}
+
+// purgeStressUser permanently deletes the throwaway user of a stress run and everything it created
+// (journal entries, transactions, categories and accounts), in a single database transaction
+func purgeStressUser(db *gorm.DB, userID uint) error {
+	return db.Transaction(func(dbTx *gorm.DB) error {
+		transactionIDs := dbTx.Unscoped().Model(&models.Transaction{}).Select("id").Where("user_id = ?", userID)
+
+		steps := []struct {
+			model interface{}
+			query *gorm.DB
+		}{
+			{&models.JournalEntry{}, dbTx.Unscoped().Where("user_id = ?", userID)},
+			{&models.TransactionSplit{}, dbTx.Unscoped().Where("transaction_id IN (?)", transactionIDs)},
+			{&models.Transaction{}, dbTx.Unscoped().Where("user_id = ?", userID)},
+			{&models.Category{}, dbTx.Unscoped().Where("user_id = ?", userID)},
+			{&models.Account{}, dbTx.Unscoped().Where("user_id = ?", userID)},
+			{&models.User{}, dbTx.Unscoped().Where("id = ?", userID)},
+		}
+
+		for _, step := range steps {
+			if err := step.query.Delete(step.model).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
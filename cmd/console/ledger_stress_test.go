@@ -0,0 +1,68 @@
+package main
+
+import (
+	"arabella-api/internal/database"
+	"arabella-api/internal/database/seeders"
+	"os"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testDatabaseURLEnv names the variable holding the DSN of a disposable Postgres database for
+// integration tests. The database is migrated and seeded with currencies; without it the test is skipped.
+const testDatabaseURLEnv = "ARABELLA_TEST_DATABASE_URL"
+
+// TestLedgerStress drives the ledger stress scenario (parallel postings and reversals on the same
+// accounts) and checks that the cached balances match the expected totals and the journal
+func TestLedgerStress(t *testing.T) {
+	dsn := os.Getenv(testDatabaseURLEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDatabaseURLEnv)
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to connect to database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get database instance: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := database.AutoMigrate(db); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	if err := (&seeders.CurrencySeeder{}).Run(db); err != nil {
+		t.Fatalf("failed to seed currencies: %v", err)
+	}
+
+	stress, err := newLedgerStress(db)
+	if stress != nil {
+		t.Cleanup(func() {
+			if err := purgeStressUser(db, stress.userID); err != nil {
+				t.Errorf("failed to delete stress user %d: %v", stress.userID, err)
+			}
+		})
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const workers, perWorker = 8, 30
+	expected := stress.run(workers, perWorker)
+	if expected.posted != workers*perWorker {
+		t.Errorf("posted %d transactions, want %d", expected.posted, workers*perWorker)
+	}
+
+	problems, err := stress.check(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, problem := range problems {
+		t.Error(problem)
+	}
+}
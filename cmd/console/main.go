@@ -1,10 +1,14 @@
 package main
 
 import (
+	"arabella-api/internal/app/repositories"
+	"arabella-api/internal/app/services"
 	"arabella-api/internal/database"
 	"arabella-api/internal/database/seeders"
 	"arabella-api/internal/platform/config"
 	"log"
+	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/cobra"
@@ -39,7 +43,102 @@ func main() {
 		Run:   runSeed,
 	}
 
-	rootCmd.AddCommand(migrateCmd, seedCmd)
+	// ledger commands (maintenance of journal entries and cached balances)
+	ledgerCmd := &cobra.Command{
+		Use:   "ledger",
+		Short: "General ledger maintenance tools",
+		Long:  `Maintenance commands for journal entries and cached account balances`,
+	}
+
+	migrateCategoriesCmd := &cobra.Command{
+		Use:   "migrate-categories",
+		Short: "Move category journal entries to nominal ledger accounts",
+		Long:  `Create a nominal CATEGORY account for every category and rewrite the journal entries that still use the CategoryID as AccountID. Also negates the CREDIT_CARD balances stored before liabilities held the amount owed as a positive balance. Run it once after upgrading, before serving traffic and before backfill-opening-balances`,
+		Run:   runLedgerMigrateCategories,
+	}
+
+	backfillOpeningBalancesCmd := &cobra.Command{
+		Use:   "backfill-opening-balances",
+		Short: "Journal account balances that were stored without journal entries",
+		Long:  `Post an OPENING_BALANCE transaction against equity for every account whose stored balance is not backed by journal entries`,
+		Run:   runLedgerBackfillOpeningBalances,
+	}
+
+	migrateReversalsCmd := &cobra.Command{
+		Use:   "migrate-reversals",
+		Short: "Move legacy reversing entries to their own reversal records",
+		Long:  `Create a reversal record for every transaction reversed before the status lifecycle existed, move its reversing journal entries there and set the original's status`,
+		Run:   runLedgerMigrateReversals,
+	}
+
+	verifyCmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Detect drift between cached balances and the journal",
+		Long:  `Recompute every account balance from journal entries, report drift per user and account, and check that every transaction balances`,
+		Run:   runLedgerVerify,
+	}
+	verifyCmd.Flags().Bool("fix", false, "Rebuild the drifted cached balances from the journal")
+	verifyCmd.Flags().Uint("user", 0, "Limit the check to one user ID")
+
+	stressCmd := &cobra.Command{
+		Use:   "stress",
+		Short: "Post transactions concurrently and check the final balances",
+		Long:  `Create a throwaway user, hammer the accounting engine from parallel workers on the same accounts, and check that the cached balances match the expected totals and the journal. Writes to the configured database; the throwaway user and its data are deleted when the run succeeds and kept for inspection when it fails`,
+		Run:   runLedgerStress,
+	}
+	stressCmd.Flags().Int("workers", 8, "Number of concurrent workers")
+	stressCmd.Flags().Int("transactions", 50, "Transactions posted by each worker")
+
+	ledgerCmd.AddCommand(migrateCategoriesCmd, backfillOpeningBalancesCmd, migrateReversalsCmd, verifyCmd, stressCmd)
+
+	// idempotency commands (housekeeping of stored Idempotency-Key responses)
+	idempotencyCmd := &cobra.Command{
+		Use:   "idempotency",
+		Short: "Idempotency-Key maintenance tools",
+	}
+
+	purgeIdempotencyKeysCmd := &cobra.Command{
+		Use:   "purge",
+		Short: "Delete expired Idempotency-Key records",
+		Long:  `Delete the stored Idempotency-Key responses whose retention window has passed`,
+		Run:   runIdempotencyPurge,
+	}
+
+	idempotencyCmd.AddCommand(purgeIdempotencyKeysCmd)
+
+	// recurring commands (recurring transaction templates)
+	recurringCmd := &cobra.Command{
+		Use:   "recurring",
+		Short: "Recurring transaction tools",
+	}
+
+	recurringRunCmd := &cobra.Command{
+		Use:   "run",
+		Short: "Post the recurring transaction occurrences that are due",
+		Long:  `Materialize every due occurrence of the active recurring transactions through the accounting engine. Occurrences already posted (by the server scheduler or an earlier run) are never posted again`,
+		Run:   runRecurringRun,
+	}
+	recurringRunCmd.Flags().Uint("user", 0, "Limit the run to one user ID")
+
+	recurringCmd.AddCommand(recurringRunCmd)
+
+	// pending commands (future-dated transactions)
+	pendingCmd := &cobra.Command{
+		Use:   "pending",
+		Short: "Pending transaction tools",
+	}
+
+	pendingPostCmd := &cobra.Command{
+		Use:   "post",
+		Short: "Post the pending transactions that are due",
+		Long:  `Post through the accounting engine every pending transaction whose date has arrived. Transactions that wait for a confirmation are left pending`,
+		Run:   runPendingPost,
+	}
+	pendingPostCmd.Flags().Uint("user", 0, "Limit the run to one user ID")
+
+	pendingCmd.AddCommand(pendingPostCmd)
+
+	rootCmd.AddCommand(migrateCmd, seedCmd, ledgerCmd, idempotencyCmd, recurringCmd, pendingCmd)
 
 	if err := rootCmd.Execute(); err != nil {
 		log.Fatal(err)
@@ -71,6 +170,9 @@ func runMigrate(cmd *cobra.Command, args []string) {
 	if err := db.AutoMigrate(database.AllModels...); err != nil {
 		log.Fatalf("❌ Migration error: %v", err)
 	}
+	if err := database.RefreshConstraints(db); err != nil {
+		log.Fatalf("❌ Constraint refresh error: %v", err)
+	}
 	log.Println("✅ Migrations completed successfully")
 
 	// Check for seed flag
@@ -100,3 +202,260 @@ func runSeedLogic(db *gorm.DB) {
 		log.Fatalf("❌ Seeder error: %v", err)
 	}
 }
+
+func runLedgerMigrateCategories(cmd *cobra.Command, args []string) {
+	// Load configuration
+	cfg := config.Load()
+
+	// Initialize database
+	db, err := database.InitDB(cfg)
+	if err != nil {
+		log.Fatalf("❌ Error connecting to database: %v", err)
+	}
+	defer database.CloseDB()
+
+	log.Println("🔄 Migrating category journal entries to ledger accounts...")
+	report, err := newLedgerService(db).MigrateCategoryLedgers()
+	if err != nil {
+		log.Fatalf("❌ Ledger migration error: %v", err)
+	}
+
+	log.Printf("   Credit cards re-signed:    %d", report.CreditCardsResigned)
+	log.Printf("   Accounts classified:       %d", report.AccountsClassified)
+	log.Printf("   Ledger accounts created:   %d", report.LedgerAccountsCreated)
+	log.Printf("   Entries rewritten:         %d", report.EntriesRewritten)
+	log.Printf("   Entries already migrated:  %d", report.EntriesAlreadyMigrated)
+	if len(report.UnresolvedEntryIDs) > 0 {
+		log.Printf("⚠️  Unresolved entries (review manually): %v", report.UnresolvedEntryIDs)
+	}
+	log.Println("✅ Ledger migration completed successfully")
+}
+
+func runLedgerBackfillOpeningBalances(cmd *cobra.Command, args []string) {
+	// Load configuration
+	cfg := config.Load()
+
+	// Initialize database
+	db, err := database.InitDB(cfg)
+	if err != nil {
+		log.Fatalf("❌ Error connecting to database: %v", err)
+	}
+	defer database.CloseDB()
+
+	log.Println("🔄 Journaling stored account balances...")
+	report, err := newLedgerService(db).BackfillOpeningBalances()
+	if err != nil {
+		log.Fatalf("❌ Opening balance backfill error: %v", err)
+	}
+
+	log.Printf("   Accounts checked:          %d", report.AccountsChecked)
+	log.Printf("   Opening balances posted:   %d", len(report.TransactionIDs))
+	log.Println("✅ Opening balance backfill completed successfully")
+}
+
+func runLedgerMigrateReversals(cmd *cobra.Command, args []string) {
+	// Load configuration
+	cfg := config.Load()
+
+	// Initialize database
+	db, err := database.InitDB(cfg)
+	if err != nil {
+		log.Fatalf("❌ Error connecting to database: %v", err)
+	}
+	defer database.CloseDB()
+
+	log.Println("🔄 Migrating legacy reversals...")
+	report, err := newLedgerService(db).MigrateReversals()
+	if err != nil {
+		log.Fatalf("❌ Reversal migration error: %v", err)
+	}
+
+	log.Printf("   Transactions migrated:     %d", report.TransactionsMigrated)
+	log.Printf("   Reversing entries moved:   %d", report.EntriesMoved)
+	log.Println("✅ Reversal migration completed successfully")
+}
+
+func runLedgerVerify(cmd *cobra.Command, args []string) {
+	// Load configuration
+	cfg := config.Load()
+
+	// Initialize database
+	db, err := database.InitDB(cfg)
+	if err != nil {
+		log.Fatalf("❌ Error connecting to database: %v", err)
+	}
+	defer database.CloseDB()
+
+	fix, _ := cmd.Flags().GetBool("fix")
+	var userID *uint
+	if id, _ := cmd.Flags().GetUint("user"); id > 0 {
+		userID = &id
+	}
+
+	log.Println("🔄 Verifying ledger...")
+	report, err := newLedgerService(db).Verify(userID, fix)
+	if err != nil {
+		log.Fatalf("❌ Ledger verification error: %v", err)
+	}
+
+	log.Printf("   Accounts checked:          %d", report.AccountsChecked)
+	log.Printf("   Transactions checked:      %d", report.TransactionsChecked)
+
+	for _, drift := range report.AccountDrifts {
+		log.Printf("⚠️  Drift user=%d account=%d (%s, %s): cached=%s ledger=%s difference=%s",
+			drift.UserID, drift.AccountID, drift.AccountName, drift.AccountType,
+			drift.CachedBalance.String(), drift.LedgerBalance.String(), drift.Difference.String())
+	}
+	for _, tx := range report.UnbalancedTransactions {
+		log.Printf("⚠️  Unbalanced user=%d transaction=%d (%s): debits=%s credits=%s",
+			tx.UserID, tx.TransactionID, tx.Type, tx.TotalDebits.String(), tx.TotalCredits.String())
+	}
+
+	switch {
+	case report.IsClean():
+		log.Println("✅ Ledger verified: cached balances match the journal")
+	case report.Fixed && len(report.UnbalancedTransactions) == 0:
+		log.Printf("✅ Rebuilt %d cached balance(s) from the journal", len(report.AccountDrifts))
+	default:
+		if report.Fixed {
+			log.Printf("🔧 Rebuilt %d cached balance(s) from the journal", len(report.AccountDrifts))
+		}
+		log.Println("❌ Ledger verification found problems")
+		os.Exit(1)
+	}
+}
+
+func runIdempotencyPurge(cmd *cobra.Command, args []string) {
+	// Load configuration
+	cfg := config.Load()
+
+	// Initialize database
+	db, err := database.InitDB(cfg)
+	if err != nil {
+		log.Fatalf("❌ Error connecting to database: %v", err)
+	}
+	defer database.CloseDB()
+
+	log.Println("🔄 Purging expired Idempotency-Key records...")
+	deleted, err := repositories.NewIdempotencyKeyRepository(db).DeleteExpired(time.Now())
+	if err != nil {
+		log.Fatalf("❌ Idempotency-Key purge error: %v", err)
+	}
+
+	log.Printf("   Records deleted:           %d", deleted)
+	log.Println("✅ Idempotency-Key purge completed successfully")
+}
+
+func runRecurringRun(cmd *cobra.Command, args []string) {
+	// Load configuration
+	cfg := config.Load()
+
+	// Initialize database
+	db, err := database.InitDB(cfg)
+	if err != nil {
+		log.Fatalf("❌ Error connecting to database: %v", err)
+	}
+	defer database.CloseDB()
+
+	var userID *uint
+	if id, _ := cmd.Flags().GetUint("user"); id > 0 {
+		userID = &id
+	}
+
+	accountRepo := repositories.NewAccountRepository(db)
+	recurringService := services.NewRecurringTransactionService(
+		repositories.NewRecurringTransactionRepository(db),
+		accountRepo,
+		repositories.NewCategoryRepository(db),
+		services.NewAccountingEngineService(
+			db,
+			repositories.NewJournalEntryRepository(db),
+			accountRepo,
+			repositories.NewTransactionRepository(db),
+		),
+	)
+
+	log.Println("🔄 Posting due recurring transactions...")
+	report, err := recurringService.RunDue(time.Now(), userID)
+	if err != nil {
+		log.Fatalf("❌ Recurring run error: %v", err)
+	}
+
+	log.Printf("   Templates processed:       %d", report.TemplatesProcessed)
+	log.Printf("   Occurrences posted:        %d", report.Posted)
+	log.Printf("   Occurrences skipped:       %d", report.Skipped)
+	log.Printf("   Already posted elsewhere:  %d", report.AlreadyPosted)
+
+	for _, failure := range report.Failures {
+		log.Printf("⚠️  Failed user=%d recurring=%d occurrence=%s: %s",
+			failure.UserID, failure.RecurringTransactionID, failure.OccurrenceDate.Format("2006-01-02"), failure.Error)
+	}
+
+	if len(report.Failures) > 0 {
+		log.Println("❌ Some occurrences could not be posted; they will be retried on the next run")
+		os.Exit(1)
+	}
+
+	log.Println("✅ Recurring run completed successfully")
+}
+
+func runPendingPost(cmd *cobra.Command, args []string) {
+	// Load configuration
+	cfg := config.Load()
+
+	// Initialize database
+	db, err := database.InitDB(cfg)
+	if err != nil {
+		log.Fatalf("❌ Error connecting to database: %v", err)
+	}
+	defer database.CloseDB()
+
+	var userID *uint
+	if id, _ := cmd.Flags().GetUint("user"); id > 0 {
+		userID = &id
+	}
+
+	transactionRepo := repositories.NewTransactionRepository(db)
+	transactionService := services.NewTransactionService(
+		transactionRepo,
+		services.NewAccountingEngineService(
+			db,
+			repositories.NewJournalEntryRepository(db),
+			repositories.NewAccountRepository(db),
+			transactionRepo,
+		),
+	)
+
+	log.Println("⏳ Posting due pending transactions...")
+	report, err := transactionService.PostDuePending(time.Now(), userID)
+	if err != nil {
+		log.Fatalf("❌ Pending run error: %v", err)
+	}
+
+	log.Printf("   Transactions due:          %d", report.Due)
+	log.Printf("   Transactions posted:       %d", report.Posted)
+	log.Printf("   Already posted elsewhere:  %d", report.AlreadyPosted)
+
+	for _, failure := range report.Failures {
+		log.Printf("⚠️  Failed user=%d transaction=%d: %s", failure.UserID, failure.TransactionID, failure.Error)
+	}
+
+	if len(report.Failures) > 0 {
+		log.Println("❌ Some transactions could not be posted; they will be retried on the next run")
+		os.Exit(1)
+	}
+
+	log.Println("✅ Pending run completed successfully")
+}
+
+// newLedgerService wires the ledger service with its accounting engine for console commands
+func newLedgerService(db *gorm.DB) services.LedgerService {
+	journalEntryRepo := repositories.NewJournalEntryRepository(db)
+	accountingEngine := services.NewAccountingEngineService(
+		db,
+		journalEntryRepo,
+		repositories.NewAccountRepository(db),
+		repositories.NewTransactionRepository(db),
+	)
+	return services.NewLedgerService(db, journalEntryRepo, accountingEngine)
+}
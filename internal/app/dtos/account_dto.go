@@ -15,44 +15,67 @@ type AccountFiltreDTO struct {
 }
 
 type AccountResponseDTO struct {
-	ID          uint             `json:"id"`
-	CreatedAt   time.Time        `json:"created_at"`
-	UpdatedAt   time.Time        `json:"updated_at"`
-	Name        string           `json:"name"`
-	AccountType string           `json:"account_type"`
-	CurrencyID  *uint            `json:"currency_id"`
-	Currency    *CurrencySummary `json:"currency,omitempty"`
-	Balance     decimal.Decimal  `json:"balance"` // Changed to decimal.Decimal to match model
-	IsActive    bool             `json:"is_active"`
+	ID             uint                `json:"id"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+	Name           string              `json:"name"`
+	AccountType    string              `json:"account_type"`
+	Classification string              `json:"classification"`
+	CurrencyID     *uint               `json:"currency_id"`
+	Currency       *CurrencySummary    `json:"currency,omitempty"`
+	Balance        decimal.Decimal     `json:"balance"` // Changed to decimal.Decimal to match model
+	IsActive       bool                `json:"is_active"`
+	Version        uint                `json:"version"`               // Send it back on update to detect concurrent changes
+	Loan           *LoanResponse       `json:"loan,omitempty"`        // LOAN and MORTGAGE accounts only
+	CreditCard     *CreditCardResponse `json:"credit_card,omitempty"` // CREDIT_CARD accounts with statement terms only
 }
 
 type CreateAccountDTO struct {
-	UserID      uint            `json:"-"` // Set from JWT context, not from request body
-	Name        string          `json:"name" binding:"required"`
-	AccountType string          `json:"account_type" binding:"required"`
-	CurrencyID  *uint           `json:"currency_id" binding:"required"`
-	Balance     decimal.Decimal `json:"balance"`
-	IsActive    *bool           `json:"is_active"`
+	UserID      uint                `json:"-"` // Set from JWT context, not from request body
+	Name        string              `json:"name" binding:"required"`
+	AccountType string              `json:"account_type" binding:"required"`
+	CurrencyID  *uint               `json:"currency_id" binding:"required"`
+	Balance     decimal.Decimal     `json:"balance"`      // Opening balance, posted as an OPENING_BALANCE transaction against equity
+	OpeningDate string              `json:"opening_date"` // ISO 8601 format, defaults to now
+	IsActive    *bool               `json:"is_active"`
+	Loan        *CreateLoanDTO      `json:"loan"`        // Required for LOAN and MORTGAGE accounts; the opening balance defaults to the principal
+	CreditCard  *CreditCardTermsDTO `json:"credit_card"` // Optional statement terms of CREDIT_CARD accounts
 }
 
 type UpdateAccountDTO struct {
 	Name        *string          `json:"name"`
 	AccountType *string          `json:"account_type"`
 	CurrencyID  *uint            `json:"currency_id"`
-	Balance     *decimal.Decimal `json:"balance"` // Changed to *decimal.Decimal for optional updates
+	Balance     *decimal.Decimal `json:"balance"` // Rejected: balances change only through journal entries (see BalanceAdjustmentDTO)
 	IsActive    *bool            `json:"is_active"`
+	Version     *uint            `json:"version"` // Optional: rejected with 409 if the account changed since this version was read
+}
+
+// BalanceAdjustmentDTO sets an account to a new balance through an ADJUSTMENT transaction against equity
+type BalanceAdjustmentDTO struct {
+	Balance decimal.Decimal `json:"balance"` // Target balance
+	Date    string          `json:"date"`    // ISO 8601 format, defaults to now
+	Notes   string          `json:"notes" binding:"omitempty,max=1000"`
 }
 
 func ToAccountResponse(account *models.Account) *AccountResponseDTO {
 	dto := &AccountResponseDTO{
-		ID:          account.ID,
-		CreatedAt:   account.CreatedAt,
-		UpdatedAt:   account.UpdatedAt,
-		Name:        account.Name,
-		AccountType: account.AccountType,
-		CurrencyID:  account.CurrencyID,
-		Balance:     account.Balance,
-		IsActive:    account.IsActive,
+		ID:             account.ID,
+		CreatedAt:      account.CreatedAt,
+		UpdatedAt:      account.UpdatedAt,
+		Name:           account.Name,
+		AccountType:    account.AccountType,
+		Classification: account.GetClassification(),
+		CurrencyID:     account.CurrencyID,
+		Balance:        account.Balance,
+		IsActive:       account.IsActive,
+		Version:        account.Version,
+	}
+	if account.Loan != nil {
+		dto.Loan = ToLoanResponse(account.Loan)
+	}
+	if account.CreditCard != nil {
+		dto.CreditCard = ToCreditCardResponse(account.CreditCard)
 	}
 	if account.Currency != nil {
 		dto.Currency = &CurrencySummary{
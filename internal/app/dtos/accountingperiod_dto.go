@@ -0,0 +1,59 @@
+package dtos
+
+import (
+	"arabella-api/internal/app/models"
+	"time"
+)
+
+// AccountingPeriodActionRequest represents the request payload for closing or reopening a period
+type AccountingPeriodActionRequest struct {
+	Year   int    `json:"year" binding:"required,gte=1900"`
+	Month  int    `json:"month" binding:"required,gte=1,lte=12"`
+	Reason string `json:"reason" binding:"omitempty,max=255"`
+}
+
+// AccountingPeriodResponse represents an accounting period
+type AccountingPeriodResponse struct {
+	ID         uint       `json:"id"`
+	Year       int        `json:"year"`
+	Month      int        `json:"month"`
+	Status     string     `json:"status"`
+	StartDate  time.Time  `json:"start_date"`
+	EndDate    time.Time  `json:"end_date"` // Exclusive
+	ClosedAt   *time.Time `json:"closed_at"`
+	ReopenedAt *time.Time `json:"reopened_at"`
+}
+
+// AccountingPeriodEventResponse represents one entry of a period's audit trail
+type AccountingPeriodEventResponse struct {
+	ID        uint      `json:"id"`
+	UserID    uint      `json:"user_id"`
+	Action    string    `json:"action"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// FromModelToAccountingPeriodResponse converts models.AccountingPeriod to AccountingPeriodResponse
+func FromModelToAccountingPeriodResponse(p *models.AccountingPeriod) AccountingPeriodResponse {
+	return AccountingPeriodResponse{
+		ID:         p.ID,
+		Year:       p.Year,
+		Month:      p.Month,
+		Status:     p.Status,
+		StartDate:  p.StartDate(),
+		EndDate:    p.EndDate(),
+		ClosedAt:   p.ClosedAt,
+		ReopenedAt: p.ReopenedAt,
+	}
+}
+
+// FromModelToAccountingPeriodEventResponse converts models.AccountingPeriodEvent to AccountingPeriodEventResponse
+func FromModelToAccountingPeriodEventResponse(e *models.AccountingPeriodEvent) AccountingPeriodEventResponse {
+	return AccountingPeriodEventResponse{
+		ID:        e.ID,
+		UserID:    e.UserID,
+		Action:    e.Action,
+		Reason:    e.Reason,
+		CreatedAt: e.CreatedAt,
+	}
+}
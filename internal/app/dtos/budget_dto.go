@@ -0,0 +1,98 @@
+package dtos
+
+import (
+	"arabella-api/internal/app/models"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// CreateBudgetRequest represents the request payload for creating a budget on an expense category.
+// Examples: 400/month for groceries = MONTHLY; 150 every two weeks = CUSTOM + period_days 14.
+type CreateBudgetRequest struct {
+	CategoryID uint            `json:"category_id" binding:"required,gt=0"`
+	Amount     decimal.Decimal `json:"amount"` // Budgeted per period
+	Period     string          `json:"period" binding:"required,oneof=MONTHLY WEEKLY CUSTOM"`
+	PeriodDays int             `json:"period_days" binding:"omitempty,gte=1,lte=366"`       // CUSTOM only
+	StartDate  string          `json:"start_date"`                                          // ISO 8601 format (YYYY-MM-DD or RFC3339); defaults to today (MONTHLY: its month)
+	EndDate    *string         `json:"end_date"`                                            // ISO 8601 format: the period containing it is the last one
+	Rollover   string          `json:"rollover" binding:"omitempty,oneof=NONE UNSPENT ALL"` // Defaults to NONE
+	Notes      string          `json:"notes" binding:"omitempty,max=1000"`
+}
+
+// UpdateBudgetRequest represents the request payload for updating a budget.
+// The amount applies to every period, past ones included (they feed the rollover);
+// to change it from a given period on, end the budget and create a new one.
+type UpdateBudgetRequest struct {
+	Amount   *decimal.Decimal `json:"amount"`
+	EndDate  *string          `json:"end_date"` // Empty string removes the end date
+	Rollover *string          `json:"rollover" binding:"omitempty,oneof=NONE UNSPENT ALL"`
+	Notes    *string          `json:"notes" binding:"omitempty,max=1000"`
+}
+
+// BudgetResponse represents a budget with the figures of the period containing the requested date
+type BudgetResponse struct {
+	ID           uint            `json:"id"`
+	CategoryID   uint            `json:"category_id"`
+	CategoryName string          `json:"category_name"`
+	Amount       decimal.Decimal `json:"amount"`
+	Period       string          `json:"period"`
+	PeriodDays   int             `json:"period_days,omitempty"`
+	StartDate    time.Time       `json:"start_date"`
+	EndDate      *time.Time      `json:"end_date,omitempty"`
+	Rollover     string          `json:"rollover"`
+	Notes        string          `json:"notes"`
+	CreatedAt    time.Time       `json:"created_at"`
+
+	Current *BudgetPeriodResponse `json:"current"` // Nil before the first period or after the last one
+}
+
+// BudgetPeriodResponse reports one period of a budget, computed from its EXPENSE transactions.
+// Status is ON_TRACK, WARNING (80% used or more) or OVER (spent more than available).
+type BudgetPeriodResponse struct {
+	Index       int             `json:"index"`
+	StartDate   time.Time       `json:"start_date"`
+	EndDate     time.Time       `json:"end_date"`
+	Budgeted    decimal.Decimal `json:"budgeted"`    // Amount of the budget
+	RolledOver  decimal.Decimal `json:"rolled_over"` // Carried from the previous period (negative when overspending carries over)
+	Available   decimal.Decimal `json:"available"`   // Budgeted + RolledOver
+	Spent       decimal.Decimal `json:"spent"`
+	Remaining   decimal.Decimal `json:"remaining"` // Available - Spent
+	PercentUsed float64         `json:"percent_used"`
+	Status      string          `json:"status"`
+}
+
+// BudgetSummary aggregates the current period of every budget (dashboard section)
+type BudgetSummary struct {
+	AsOf           time.Time        `json:"as_of"`
+	TotalAvailable decimal.Decimal  `json:"total_available"`
+	TotalSpent     decimal.Decimal  `json:"total_spent"`
+	TotalRemaining decimal.Decimal  `json:"total_remaining"`
+	PercentUsed    float64          `json:"percent_used"`
+	OnTrack        int              `json:"on_track"`
+	Warning        int              `json:"warning"`
+	Over           int              `json:"over"`
+	Budgets        []BudgetResponse `json:"budgets"` // Budgets with a period in progress
+}
+
+// FromModelToBudgetResponse converts models.Budget to BudgetResponse (without period figures)
+func FromModelToBudgetResponse(budget *models.Budget) BudgetResponse {
+	resp := BudgetResponse{
+		ID:         budget.ID,
+		CategoryID: budget.CategoryID,
+		Amount:     budget.Amount,
+		Period:     budget.Period,
+		PeriodDays: budget.PeriodDays,
+		StartDate:  budget.StartDate,
+		EndDate:    budget.EndDate,
+		Rollover:   budget.Rollover,
+		Notes:      budget.Notes,
+		CreatedAt:  budget.CreatedAt,
+	}
+
+	if budget.Category != nil {
+		resp.CategoryName = budget.Category.Name
+	}
+
+	return resp
+}
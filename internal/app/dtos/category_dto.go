@@ -18,11 +18,12 @@ type UpdateCategoryRequest struct {
 
 // CategoryResponse represents the full category response
 type CategoryResponse struct {
-	ID       uint   `json:"id"`
-	UserID   uint   `json:"user_id"`
-	Name     string `json:"name"`
-	Type     string `json:"type"`
-	IsActive bool   `json:"is_active"`
+	ID              uint   `json:"id"`
+	UserID          uint   `json:"user_id"`
+	Name            string `json:"name"`
+	Type            string `json:"type"`
+	IsActive        bool   `json:"is_active"`
+	LedgerAccountID *uint  `json:"ledger_account_id"` // Nominal account that receives this category's journal entries
 }
 
 // CategorySummary represents a lightweight category for use in other DTOs
@@ -51,11 +52,12 @@ func (r *CreateCategoryRequest) ToModel(userID uint) *models.Category {
 // FromModelToCategoryResponse converts models.Category to CategoryResponse
 func FromModelToCategoryResponse(c *models.Category) CategoryResponse {
 	return CategoryResponse{
-		ID:       c.ID,
-		UserID:   c.UserID,
-		Name:     c.Name,
-		Type:     c.Type,
-		IsActive: c.IsActive,
+		ID:              c.ID,
+		UserID:          c.UserID,
+		Name:            c.Name,
+		Type:            c.Type,
+		IsActive:        c.IsActive,
+		LedgerAccountID: c.LedgerAccountID,
 	}
 }
 
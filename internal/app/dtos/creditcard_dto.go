@@ -0,0 +1,66 @@
+package dtos
+
+import (
+	"arabella-api/internal/app/models"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// CreditCardTermsDTO carries the statement terms of a CREDIT_CARD account.
+// Example: closes on the 25th, due on the 10th of the following month, minimum 5% but at least 25.
+type CreditCardTermsDTO struct {
+	CreditLimit           decimal.Decimal `json:"credit_limit"`
+	StatementClosingDay   int             `json:"statement_closing_day" binding:"required,gte=1,lte=31"`
+	PaymentDueDay         int             `json:"payment_due_day" binding:"required,gte=1,lte=31"` // The first one after the closing
+	MinimumPaymentPercent decimal.Decimal `json:"minimum_payment_percent"`                         // Of the statement balance
+	MinimumPaymentAmount  decimal.Decimal `json:"minimum_payment_amount"`                          // Floor of the minimum payment
+	InterestRate          decimal.Decimal `json:"interest_rate"`                                   // Annual rate, in percent; used by the payoff simulator
+}
+
+// CreditCardResponse represents the statement terms of a CREDIT_CARD account
+type CreditCardResponse struct {
+	CreditLimit           decimal.Decimal `json:"credit_limit"`
+	StatementClosingDay   int             `json:"statement_closing_day"`
+	PaymentDueDay         int             `json:"payment_due_day"`
+	MinimumPaymentPercent decimal.Decimal `json:"minimum_payment_percent"`
+	MinimumPaymentAmount  decimal.Decimal `json:"minimum_payment_amount"`
+	InterestRate          decimal.Decimal `json:"interest_rate"`
+}
+
+// CreditCardStatementResponse reports the current statement cycle of a credit card, computed from its journal entries.
+// StatementBalance is what the last statement billed; payments and refunds posted since then go to it first
+// (StatementDue is what is left of it) and the rest of the balance is still unbilled.
+// Status is PAID (nothing left to pay), DUE or OVERDUE (the minimum was not paid by the due date).
+type CreditCardStatementResponse struct {
+	AccountID       uint               `json:"account_id"`
+	AccountName     string             `json:"account_name"`
+	CreditCard      CreditCardResponse `json:"credit_card"`
+	Balance         decimal.Decimal    `json:"balance"` // Owed today: StatementDue + UnbilledBalance
+	AvailableCredit decimal.Decimal    `json:"available_credit"`
+	OverLimit       bool               `json:"over_limit"`
+
+	StatementDate          time.Time       `json:"statement_date"` // Last closing date
+	StatementBalance       decimal.Decimal `json:"statement_balance"`
+	PaymentsSinceStatement decimal.Decimal `json:"payments_since_statement"`
+	StatementDue           decimal.Decimal `json:"statement_due"`
+	MinimumPaymentDue      decimal.Decimal `json:"minimum_payment_due"`
+	PaymentDueDate         time.Time       `json:"payment_due_date"` // Of the last statement
+	Status                 string          `json:"status"`
+
+	UnbilledBalance   decimal.Decimal `json:"unbilled_balance"`
+	NextStatementDate time.Time       `json:"next_statement_date"`
+	NextDueDate       time.Time       `json:"next_due_date"` // First due date from today on
+}
+
+// ToCreditCardResponse converts models.CreditCard to CreditCardResponse
+func ToCreditCardResponse(card *models.CreditCard) *CreditCardResponse {
+	return &CreditCardResponse{
+		CreditLimit:           card.CreditLimit,
+		StatementClosingDay:   card.StatementClosingDay,
+		PaymentDueDay:         card.PaymentDueDay,
+		MinimumPaymentPercent: card.MinimumPaymentPercent,
+		MinimumPaymentAmount:  card.MinimumPaymentAmount,
+		InterestRate:          card.InterestRate,
+	}
+}
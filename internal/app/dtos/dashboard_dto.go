@@ -10,14 +10,15 @@ import (
 type DashboardResponse struct {
 	// Financial Overview
 	TotalAssets      decimal.Decimal `json:"total_assets"`      // Sum of all BANK + CASH + SAVINGS + INVESTMENT accounts
-	TotalLiabilities decimal.Decimal `json:"total_liabilities"` // Sum of all CREDIT_CARD accounts
+	TotalLiabilities decimal.Decimal `json:"total_liabilities"` // Sum of all CREDIT_CARD + LOAN + MORTGAGE accounts
 	NetWorth         decimal.Decimal `json:"net_worth"`         // Assets - Liabilities
 	LiquidAssets     decimal.Decimal `json:"liquid_assets"`     // BANK + CASH only
 
 	// Monthly Stats (current month)
-	MonthlyIncome      decimal.Decimal `json:"monthly_income"`        // Total INCOME transactions this month
-	MonthlyExpenses    decimal.Decimal `json:"monthly_expenses"`      // Total EXPENSE transactions this month
-	MonthlyNetCashFlow decimal.Decimal `json:"monthly_net_cash_flow"` // Income - Expenses
+	MonthlyIncome       decimal.Decimal `json:"monthly_income"`        // Total INCOME transactions this month
+	MonthlyExpenses     decimal.Decimal `json:"monthly_expenses"`      // Total EXPENSE transactions this month
+	MonthlyNetCashFlow  decimal.Decimal `json:"monthly_net_cash_flow"` // Income - Expenses
+	MonthlyDebtPayments decimal.Decimal `json:"monthly_debt_payments"` // Total DEBT_PAYMENT transactions this month (not an expense)
 
 	// Runway Calculation (Critical feature from DOC.md)
 	Runway                 float64         `json:"runway"`                   // Months of expenses covered by liquid assets
@@ -27,6 +28,12 @@ type DashboardResponse struct {
 	// Account Breakdown
 	AccountBalances []AccountBalanceDTO `json:"account_balances"` // Current balance of each account
 
+	// Budgets
+	Budgets *BudgetSummary `json:"budgets"` // Current period of every budget in progress
+
+	// Savings Goals
+	SavingsGoals *SavingsGoalSummary `json:"savings_goals"` // Progress of every savings goal
+
 	// Metadata
 	AsOf         time.Time `json:"as_of"`         // When this data was calculated
 	BaseCurrency string    `json:"base_currency"` // User's base currency (default: USD)
@@ -50,6 +57,7 @@ type MonthlyStats struct {
 	Income           decimal.Decimal `json:"income"`
 	Expenses         decimal.Decimal `json:"expenses"`
 	NetCashFlow      decimal.Decimal `json:"net_cash_flow"` // Income - Expenses
+	DebtPayments     decimal.Decimal `json:"debt_payments"` // DEBT_PAYMENT total, reported apart from Expenses
 	TransactionCount int             `json:"transaction_count"`
 }
 
@@ -100,6 +108,31 @@ type CategoryExpenseBreakdownResponse struct {
 	Year          int                        `json:"year"`
 }
 
+// BalanceProjection projects the user's balances to a date by applying the pending transactions
+// dated up to then (future-dated and unconfirmed ones), which do not affect the current balances
+type BalanceProjection struct {
+	AsOf  time.Time `json:"as_of"`
+	Until time.Time `json:"until"`
+
+	LiquidAssets          decimal.Decimal `json:"liquid_assets"`           // BANK + CASH today
+	ProjectedLiquidAssets decimal.Decimal `json:"projected_liquid_assets"` // BANK + CASH once the pending transactions are posted
+	NetWorth              decimal.Decimal `json:"net_worth"`
+	ProjectedNetWorth     decimal.Decimal `json:"projected_net_worth"`
+
+	Accounts            []AccountProjection   `json:"accounts"`             // Accounts touched by a pending transaction
+	PendingTransactions []TransactionResponse `json:"pending_transactions"` // Oldest first
+}
+
+// AccountProjection is the projected balance of one account
+type AccountProjection struct {
+	ID               uint            `json:"id"`
+	Name             string          `json:"name"`
+	Type             string          `json:"type"`
+	CurrentBalance   decimal.Decimal `json:"current_balance"`
+	PendingChange    decimal.Decimal `json:"pending_change"`
+	ProjectedBalance decimal.Decimal `json:"projected_balance"`
+}
+
 // DashboardFilters represents filters for dashboard data queries
 type DashboardFilters struct {
 	StartDate    *time.Time `json:"start_date" validate:"omitempty"`
@@ -0,0 +1,105 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// DebtPayoffSimulationRequest represents the request payload for simulating the payoff of the user's debts.
+// Every active liability account with a balance is included. LOAN and MORTGAGE accounts default to the
+// rate and installment of their terms and credit cards to their rate and minimum payment rule; cards
+// without terms need their rate and minimum payment in Debts.
+type DebtPayoffSimulationRequest struct {
+	ExtraMonthlyPayment decimal.Decimal  `json:"extra_monthly_payment"` // Paid on top of the minimum payments every month
+	Debts               []DebtTermsInput `json:"debts" binding:"omitempty,dive"`
+	CustomOrder         []uint           `json:"custom_order" binding:"omitempty,dive,gt=0"` // Account IDs; adds a CUSTOM strategy (unlisted debts follow in avalanche order)
+	StartDate           string           `json:"start_date"`                                 // ISO 8601 format; month of the first payment, defaults to the current month
+}
+
+// DebtTermsInput sets (or overrides) the interest rate and minimum payment of one debt
+type DebtTermsInput struct {
+	AccountID      uint             `json:"account_id" binding:"required,gt=0"`
+	InterestRate   *decimal.Decimal `json:"interest_rate"`   // Annual rate, in percent
+	MinimumPayment *decimal.Decimal `json:"minimum_payment"` // Per month
+}
+
+// DebtPayoffSimulationResponse compares the payoff strategies of the same debts and budget.
+// Recommended is the strategy paying the least interest (AVALANCHE on ties, as it is never worse).
+type DebtPayoffSimulationResponse struct {
+	StartDate           time.Time            `json:"start_date"`
+	ExtraMonthlyPayment decimal.Decimal      `json:"extra_monthly_payment"`
+	MonthlyBudget       decimal.Decimal      `json:"monthly_budget"` // Minimum payments + extra, kept constant as debts are paid off
+	Debts               []DebtSummary        `json:"debts"`
+	Strategies          []DebtPayoffStrategy `json:"strategies"`
+	Recommended         string               `json:"recommended"`
+}
+
+// DebtSummary is one debt as it enters the simulation
+type DebtSummary struct {
+	AccountID      uint            `json:"account_id"`
+	AccountName    string          `json:"account_name"`
+	AccountType    string          `json:"account_type"`
+	Balance        decimal.Decimal `json:"balance"`
+	InterestRate   decimal.Decimal `json:"interest_rate"`
+	MinimumPayment decimal.Decimal `json:"minimum_payment"`
+}
+
+// DebtPayoffStrategy is the outcome of one strategy.
+// Strategy is AVALANCHE (highest rate first), SNOWBALL (smallest balance first) or CUSTOM.
+// PaidOff is false when the budget does not clear the debts within the simulation horizon.
+type DebtPayoffStrategy struct {
+	Strategy      string               `json:"strategy"`
+	Order         []uint               `json:"order"` // Account IDs in the order extra payments go to
+	PaidOff       bool                 `json:"paid_off"`
+	Months        int                  `json:"months"`
+	PayoffDate    *time.Time           `json:"payoff_date,omitempty"`
+	TotalInterest decimal.Decimal      `json:"total_interest"`
+	TotalPaid     decimal.Decimal      `json:"total_paid"`
+	Debts         []DebtPayoffSchedule `json:"debts"`
+}
+
+// DebtPayoffSchedule is the month-by-month payoff of one debt under a strategy
+type DebtPayoffSchedule struct {
+	AccountID     uint              `json:"account_id"`
+	AccountName   string            `json:"account_name"`
+	PaidOff       bool              `json:"paid_off"`
+	Months        int               `json:"months"`
+	PayoffDate    *time.Time        `json:"payoff_date,omitempty"`
+	TotalInterest decimal.Decimal   `json:"total_interest"`
+	TotalPaid     decimal.Decimal   `json:"total_paid"`
+	Schedule      []DebtPayoffMonth `json:"schedule"`
+}
+
+// DebtPayoffMonth is one month of a debt's payoff schedule
+type DebtPayoffMonth struct {
+	Month    int             `json:"month"` // 1-based
+	Date     time.Time       `json:"date"`
+	Payment  decimal.Decimal `json:"payment"`
+	Interest decimal.Decimal `json:"interest"`
+	Balance  decimal.Decimal `json:"balance"` // After the payment
+}
+
+// UpcomingDebtsResponse lists the next payment of every credit card and loan, by due date
+type UpcomingDebtsResponse struct {
+	AsOf         time.Time       `json:"as_of"`
+	Until        time.Time       `json:"until"`
+	TotalDue     decimal.Decimal `json:"total_due"`
+	TotalMinimum decimal.Decimal `json:"total_minimum"`
+	Debts        []UpcomingDebt  `json:"debts"`
+}
+
+// UpcomingDebt is the next payment of one debt.
+// Credit cards report what is left of their last statement while it is due (or overdue) and what is unbilled
+// so far otherwise; loans report their next installment. Status is OVERDUE, DUE_SOON (within a week) or UPCOMING.
+type UpcomingDebt struct {
+	AccountID      uint            `json:"account_id"`
+	AccountName    string          `json:"account_name"`
+	AccountType    string          `json:"account_type"`
+	DueDate        time.Time       `json:"due_date"`
+	DaysUntilDue   int             `json:"days_until_due"` // Negative when overdue
+	AmountDue      decimal.Decimal `json:"amount_due"`
+	MinimumPayment decimal.Decimal `json:"minimum_payment"`
+	Balance        decimal.Decimal `json:"balance"`
+	Status         string          `json:"status"`
+}
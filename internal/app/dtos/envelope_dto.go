@@ -0,0 +1,74 @@
+package dtos
+
+import (
+	"arabella-api/internal/app/models"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// CreateEnvelopeRequest represents the request payload for creating the envelope of an expense category.
+// Amount, when set, is assigned to it from the unassigned funds right away.
+type CreateEnvelopeRequest struct {
+	CategoryID uint             `json:"category_id" binding:"required,gt=0"`
+	StartDate  string           `json:"start_date"` // ISO 8601 format (YYYY-MM-DD or RFC3339); defaults to today
+	Amount     *decimal.Decimal `json:"amount"`
+	Notes      string           `json:"notes" binding:"omitempty,max=1000"`
+}
+
+// MoveEnvelopeFundsRequest represents the request payload for moving funds.
+// Without from_envelope_id the amount is assigned from the unassigned funds; without to_envelope_id
+// it goes back to them.
+type MoveEnvelopeFundsRequest struct {
+	FromEnvelopeID *uint           `json:"from_envelope_id" binding:"omitempty,gt=0"`
+	ToEnvelopeID   *uint           `json:"to_envelope_id" binding:"omitempty,gt=0"`
+	Amount         decimal.Decimal `json:"amount"`
+	Notes          string          `json:"notes" binding:"omitempty,max=1000"`
+}
+
+// EnvelopeResponse represents an envelope with its balance
+type EnvelopeResponse struct {
+	ID           uint            `json:"id"`
+	CategoryID   uint            `json:"category_id"`
+	CategoryName string          `json:"category_name"`
+	StartDate    time.Time       `json:"start_date"`
+	Notes        string          `json:"notes"`
+	CreatedAt    time.Time       `json:"created_at"`
+	Assigned     decimal.Decimal `json:"assigned"` // Moved in - moved out
+	Spent        decimal.Decimal `json:"spent"`    // EXPENSE transactions since the start date
+	Balance      decimal.Decimal `json:"balance"`  // Assigned - Spent (negative when overspent)
+}
+
+// EnvelopeMoveResponse represents one move of funds
+type EnvelopeMoveResponse struct {
+	ID             uint            `json:"id"`
+	FromEnvelopeID *uint           `json:"from_envelope_id"` // Nil: from the unassigned funds
+	ToEnvelopeID   *uint           `json:"to_envelope_id"`   // Nil: back to the unassigned funds
+	Amount         decimal.Decimal `json:"amount"`
+	MoveDate       time.Time       `json:"move_date"`
+	Notes          string          `json:"notes"`
+}
+
+// EnvelopeSummary reports how the liquid funds are allocated.
+// Unassigned = LiquidAssets - the positive envelope balances; overspent envelopes are reported in
+// Overspent and must be covered with funds from other envelopes or the unassigned ones.
+type EnvelopeSummary struct {
+	AsOf         time.Time          `json:"as_of"`
+	LiquidAssets decimal.Decimal    `json:"liquid_assets"` // BANK + CASH
+	Allocated    decimal.Decimal    `json:"allocated"`     // Sum of the positive envelope balances
+	Unassigned   decimal.Decimal    `json:"unassigned"`    // Negative when more is allocated than available
+	Overspent    decimal.Decimal    `json:"overspent"`     // Sum of the negative envelope balances, as a positive amount
+	Envelopes    []EnvelopeResponse `json:"envelopes"`
+}
+
+// FromModelToEnvelopeMoveResponse converts models.EnvelopeMove to EnvelopeMoveResponse
+func FromModelToEnvelopeMoveResponse(move *models.EnvelopeMove) EnvelopeMoveResponse {
+	return EnvelopeMoveResponse{
+		ID:             move.ID,
+		FromEnvelopeID: move.FromEnvelopeID,
+		ToEnvelopeID:   move.ToEnvelopeID,
+		Amount:         move.Amount,
+		MoveDate:       move.MoveDate,
+		Notes:          move.Notes,
+	}
+}
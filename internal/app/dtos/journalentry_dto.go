@@ -63,6 +63,36 @@ type BalanceSheetResponse struct {
 	IsBalanced   bool                     `json:"is_balanced"`
 }
 
+// AccountLedgerResponse is the statement view of one account: its journal entries in date order,
+// each with the running balance, starting from the balance at the beginning of the range.
+// Balances follow the account's normal side (positive = money held for assets, owed for liabilities).
+type AccountLedgerResponse struct {
+	AccountID      uint                `json:"account_id"`
+	AccountName    string              `json:"account_name"`
+	AccountType    string              `json:"account_type"`
+	CurrencyCode   string              `json:"currency_code"`
+	StartDate      *time.Time          `json:"start_date,omitempty"`
+	EndDate        *time.Time          `json:"end_date,omitempty"`
+	OpeningBalance decimal.Decimal     `json:"opening_balance"` // Balance just before StartDate
+	ClosingBalance decimal.Decimal     `json:"closing_balance"` // Balance at EndDate (end of the whole range, not of the page)
+	Entries        []AccountLedgerLine `json:"entries"`
+	Total          int64               `json:"total"`
+	Page           int                 `json:"page"`
+	PageSize       int                 `json:"page_size"`
+	TotalPages     int                 `json:"total_pages"`
+}
+
+// AccountLedgerLine is one journal entry of an account ledger
+type AccountLedgerLine struct {
+	EntryID        uint            `json:"entry_id"`
+	TransactionID  uint            `json:"transaction_id"`
+	EntryDate      time.Time       `json:"entry_date"`
+	Description    string          `json:"description"`
+	Debit          decimal.Decimal `json:"debit"`
+	Credit         decimal.Decimal `json:"credit"`
+	RunningBalance decimal.Decimal `json:"running_balance"`
+}
+
 // JournalEntryFilters represents query parameters for filtering journal entries
 type JournalEntryFilters struct {
 	TransactionID *uint      `json:"transaction_id" validate:"omitempty,gt=0"`
@@ -72,6 +102,7 @@ type JournalEntryFilters struct {
 	EndDate       *time.Time `json:"end_date" validate:"omitempty"`
 	Page          int        `json:"page" validate:"omitempty,gte=1"`
 	PageSize      int        `json:"page_size" validate:"omitempty,gte=1,lte=100"`
+	Ascending     bool       `json:"ascending"` // Oldest first (statement order) instead of newest first
 }
 
 // FromModelToJournalEntryResponse converts models.JournalEntry to JournalEntryResponse
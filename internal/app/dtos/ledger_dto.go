@@ -0,0 +1,63 @@
+package dtos
+
+import "github.com/shopspring/decimal"
+
+// CategoryLedgerMigrationReport summarizes the migration of category journal entries
+// from the legacy CategoryID-as-AccountID scheme to real nominal ledger accounts
+type CategoryLedgerMigrationReport struct {
+	CreditCardsResigned    int64  `json:"credit_cards_resigned"`    // Legacy CREDIT_CARD balances negated to the amount owed
+	AccountsClassified     int64  `json:"accounts_classified"`      // Existing accounts that received their ACCOUNT_CLASSIFICATION
+	LedgerAccountsCreated  int    `json:"ledger_accounts_created"`  // Nominal CATEGORY accounts created for existing categories
+	EntriesRewritten       int    `json:"entries_rewritten"`        // Journal entries repointed from a category ID to its ledger account
+	EntriesAlreadyMigrated int    `json:"entries_already_migrated"` // Category entries that already referenced a ledger account
+	UnresolvedEntryIDs     []uint `json:"unresolved_entry_ids"`     // Category entries whose account_id matched no category of the transaction
+}
+
+// OpeningBalanceBackfillReport summarizes the journaling of balances stored before opening balances
+// were posted through the Accounting Engine
+type OpeningBalanceBackfillReport struct {
+	AccountsChecked int    `json:"accounts_checked"` // Real accounts compared against their journal
+	TransactionIDs  []uint `json:"transaction_ids"`  // OPENING_BALANCE transactions posted for the differences
+}
+
+// ReversalMigrationReport summarizes the move of legacy reversing entries (posted under the original
+// transaction's ID, with the original flagged is_reconciled) to their own reversal records
+type ReversalMigrationReport struct {
+	TransactionsMigrated int   `json:"transactions_migrated"` // Originals that received a status and a reversal record
+	EntriesMoved         int64 `json:"entries_moved"`         // Reversing journal entries repointed to the reversal records
+}
+
+// LedgerVerificationReport is the result of comparing cached account balances with the journal
+type LedgerVerificationReport struct {
+	UserID                 *uint                   `json:"user_id,omitempty"` // Set when the check was limited to one user
+	AccountsChecked        int                     `json:"accounts_checked"`
+	TransactionsChecked    int                     `json:"transactions_checked"`
+	AccountDrifts          []AccountBalanceDrift   `json:"account_drifts"`
+	UnbalancedTransactions []UnbalancedTransaction `json:"unbalanced_transactions"`
+	Fixed                  bool                    `json:"fixed"` // True when the drifted cached balances were rebuilt
+}
+
+// AccountBalanceDrift describes an account whose cached balance disagrees with its journal entries
+type AccountBalanceDrift struct {
+	UserID        uint            `json:"user_id"`
+	AccountID     uint            `json:"account_id"`
+	AccountName   string          `json:"account_name"`
+	AccountType   string          `json:"account_type"`
+	CachedBalance decimal.Decimal `json:"cached_balance"` // Account.Balance
+	LedgerBalance decimal.Decimal `json:"ledger_balance"` // Recomputed from journal_entries
+	Difference    decimal.Decimal `json:"difference"`     // CachedBalance - LedgerBalance
+}
+
+// UnbalancedTransaction describes a transaction whose journal entries do not balance
+type UnbalancedTransaction struct {
+	UserID        uint            `json:"user_id"`
+	TransactionID uint            `json:"transaction_id"`
+	Type          string          `json:"type"`
+	TotalDebits   decimal.Decimal `json:"total_debits"`
+	TotalCredits  decimal.Decimal `json:"total_credits"`
+}
+
+// IsClean returns true if no drift and no unbalanced transaction was found
+func (r *LedgerVerificationReport) IsClean() bool {
+	return len(r.AccountDrifts) == 0 && len(r.UnbalancedTransactions) == 0
+}
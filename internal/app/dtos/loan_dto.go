@@ -0,0 +1,66 @@
+package dtos
+
+import (
+	"arabella-api/internal/app/models"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// CreateLoanDTO carries the terms of a LOAN or MORTGAGE account on creation
+type CreateLoanDTO struct {
+	Principal          decimal.Decimal `json:"principal"`
+	InterestRate       decimal.Decimal `json:"interest_rate"` // Annual nominal rate, in percent (e.g. 7.5)
+	TermMonths         int             `json:"term_months" binding:"required,gte=1,lte=600"`
+	PaymentDay         int             `json:"payment_day" binding:"required,gte=1,lte=31"`
+	StartDate          string          `json:"start_date"`           // ISO 8601 format (YYYY-MM-DD or RFC3339); defaults to today
+	InterestCategoryID *uint           `json:"interest_category_id"` // Expense category of the interest; defaults to "Loan Interest"
+}
+
+// LoanResponse represents the terms of a LOAN or MORTGAGE account
+type LoanResponse struct {
+	Principal          decimal.Decimal `json:"principal"`
+	InterestRate       decimal.Decimal `json:"interest_rate"`
+	TermMonths         int             `json:"term_months"`
+	PaymentDay         int             `json:"payment_day"`
+	StartDate          time.Time       `json:"start_date"`
+	InterestCategoryID *uint           `json:"interest_category_id"`
+	MonthlyPayment     decimal.Decimal `json:"monthly_payment"`
+}
+
+// AmortizationScheduleResponse is the full amortization schedule of a loan, computed from its terms.
+// CurrentBalance is what is actually owed today, after the payments posted so far.
+type AmortizationScheduleResponse struct {
+	AccountID      uint                      `json:"account_id"`
+	AccountName    string                    `json:"account_name"`
+	AccountType    string                    `json:"account_type"`
+	Loan           LoanResponse              `json:"loan"`
+	TotalPayments  decimal.Decimal           `json:"total_payments"`
+	TotalInterest  decimal.Decimal           `json:"total_interest"`
+	CurrentBalance decimal.Decimal           `json:"current_balance"`
+	NextDueDate    *time.Time                `json:"next_due_date,omitempty"` // Nil once the term is over
+	Installments   []AmortizationInstallment `json:"installments"`
+}
+
+// AmortizationInstallment is one row of an amortization schedule
+type AmortizationInstallment struct {
+	Number    int             `json:"number"`
+	DueDate   time.Time       `json:"due_date"`
+	Payment   decimal.Decimal `json:"payment"`
+	Principal decimal.Decimal `json:"principal"`
+	Interest  decimal.Decimal `json:"interest"`
+	Balance   decimal.Decimal `json:"balance"` // Outstanding principal after the payment
+}
+
+// ToLoanResponse converts models.Loan to LoanResponse
+func ToLoanResponse(loan *models.Loan) *LoanResponse {
+	return &LoanResponse{
+		Principal:          loan.Principal,
+		InterestRate:       loan.InterestRate,
+		TermMonths:         loan.TermMonths,
+		PaymentDay:         loan.PaymentDay,
+		StartDate:          loan.StartDate,
+		InterestCategoryID: loan.InterestCategoryID,
+		MonthlyPayment:     loan.Installment(),
+	}
+}
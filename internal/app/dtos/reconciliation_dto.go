@@ -0,0 +1,76 @@
+package dtos
+
+import (
+	"arabella-api/internal/app/models"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// StartReconciliationRequest represents the request payload for starting a reconciliation
+type StartReconciliationRequest struct {
+	AccountID        uint            `json:"account_id" validate:"required,gt=0" binding:"required,gt=0"`
+	StatementDate    string          `json:"statement_date" validate:"required" binding:"required"` // ISO 8601 format (YYYY-MM-DD or RFC3339)
+	StatementBalance decimal.Decimal `json:"statement_balance"`                                     // Ending balance on the statement (amount owed for credit cards)
+}
+
+// ClearTransactionsRequest ticks (cleared=true) or unticks (cleared=false) transactions of a reconciliation
+type ClearTransactionsRequest struct {
+	TransactionIDs []uint `json:"transaction_ids" validate:"required,min=1" binding:"required,min=1"`
+	Cleared        bool   `json:"cleared"`
+}
+
+// ReconciliationResponse represents a reconciliation with its live difference.
+// Difference = StatementBalance - ClearedBalance; the reconciliation can be completed only at zero.
+type ReconciliationResponse struct {
+	ID               uint            `json:"id"`
+	AccountID        uint            `json:"account_id"`
+	AccountName      string          `json:"account_name"`
+	StatementDate    time.Time       `json:"statement_date"`
+	StatementBalance decimal.Decimal `json:"statement_balance"`
+	OpeningBalance   decimal.Decimal `json:"opening_balance"`
+	ClearedBalance   decimal.Decimal `json:"cleared_balance"`
+	Difference       decimal.Decimal `json:"difference"`
+	Status           string          `json:"status"`
+	CompletedAt      *time.Time      `json:"completed_at"`
+	CreatedAt        time.Time       `json:"created_at"`
+
+	ClearedTransactions   []ReconciliationTransactionResponse `json:"cleared_transactions,omitempty"`
+	UnclearedTransactions []ReconciliationTransactionResponse `json:"uncleared_transactions,omitempty"` // Only while in progress
+}
+
+// ReconciliationTransactionResponse is a transaction as seen from the reconciled account
+type ReconciliationTransactionResponse struct {
+	TransactionID   uint            `json:"transaction_id"`
+	TransactionDate time.Time       `json:"transaction_date"`
+	Description     string          `json:"description"`
+	Amount          decimal.Decimal `json:"amount"` // Effect on the account balance (negative = money out / debt paid)
+}
+
+// FromModelToReconciliationResponse converts models.Reconciliation (with items loaded) to ReconciliationResponse
+func FromModelToReconciliationResponse(r *models.Reconciliation) ReconciliationResponse {
+	resp := ReconciliationResponse{
+		ID:               r.ID,
+		AccountID:        r.AccountID,
+		AccountName:      r.Account.Name,
+		StatementDate:    r.StatementDate,
+		StatementBalance: r.StatementBalance,
+		OpeningBalance:   r.OpeningBalance,
+		ClearedBalance:   r.ClearedBalance(),
+		Difference:       r.Difference(),
+		Status:           r.Status,
+		CompletedAt:      r.CompletedAt,
+		CreatedAt:        r.CreatedAt,
+	}
+
+	for _, item := range r.Items {
+		resp.ClearedTransactions = append(resp.ClearedTransactions, ReconciliationTransactionResponse{
+			TransactionID:   item.TransactionID,
+			TransactionDate: item.Transaction.TransactionDate,
+			Description:     item.Transaction.Description,
+			Amount:          item.Amount,
+		})
+	}
+
+	return resp
+}
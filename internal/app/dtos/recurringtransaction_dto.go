@@ -0,0 +1,172 @@
+package dtos
+
+import (
+	"arabella-api/internal/app/models"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// CreateRecurringTransactionRequest represents the request payload for creating a recurring transaction.
+// Examples: salary on the 1st = MONTHLY + day_of_month 1; a subscription every 30 days = DAILY + interval 30.
+type CreateRecurringTransactionRequest struct {
+	Type          string          `json:"type" binding:"required,oneof=INCOME EXPENSE TRANSFER DEBT_PAYMENT"`
+	Description   string          `json:"description" binding:"required,min=1,max=255"`
+	Amount        decimal.Decimal `json:"amount"`
+	AccountFromID uint            `json:"account_from_id" binding:"required,gt=0"`
+	AccountToID   *uint           `json:"account_to_id" binding:"omitempty,gt=0"`
+	CategoryID    *uint           `json:"category_id" binding:"omitempty,gt=0"`
+	Notes         string          `json:"notes" binding:"omitempty,max=1000"`
+
+	Frequency  string  `json:"frequency" binding:"required,oneof=DAILY WEEKLY MONTHLY YEARLY"`
+	Interval   int     `json:"interval" binding:"omitempty,gte=1"`            // Defaults to 1
+	DayOfMonth *int    `json:"day_of_month" binding:"omitempty,gte=1,lte=31"` // MONTHLY/YEARLY; clamped to the month's last day
+	StartDate  string  `json:"start_date" binding:"required"`                 // ISO 8601 format (YYYY-MM-DD or RFC3339): first occurrence
+	EndDate    *string `json:"end_date"`                                      // ISO 8601 format: no occurrence after this date
+	Count      *int    `json:"count" binding:"omitempty,gte=1"`               // Maximum number of occurrences
+}
+
+// UpdateRecurringTransactionRequest represents the request payload for updating a recurring transaction.
+// The schedule itself (frequency, interval, day of month, start date) cannot change once occurrences
+// were posted; end the template and create a new one instead.
+type UpdateRecurringTransactionRequest struct {
+	Description   *string          `json:"description" binding:"omitempty,min=1,max=255"`
+	Amount        *decimal.Decimal `json:"amount"`
+	AccountFromID *uint            `json:"account_from_id" binding:"omitempty,gt=0"`
+	AccountToID   *uint            `json:"account_to_id" binding:"omitempty,gt=0"`
+	CategoryID    *uint            `json:"category_id" binding:"omitempty,gt=0"`
+	Notes         *string          `json:"notes" binding:"omitempty,max=1000"`
+	Frequency     *string          `json:"frequency" binding:"omitempty,oneof=DAILY WEEKLY MONTHLY YEARLY"`
+	Interval      *int             `json:"interval" binding:"omitempty,gte=1"`
+	DayOfMonth    *int             `json:"day_of_month" binding:"omitempty,gte=1,lte=31"`
+	StartDate     *string          `json:"start_date"`
+	EndDate       *string          `json:"end_date"`                        // Empty string removes the end date
+	Count         *int             `json:"count" binding:"omitempty,gte=0"` // 0 removes the limit
+	IsActive      *bool            `json:"is_active"`
+}
+
+// ChangesSchedule returns true if the request changes when occurrences fall
+func (r *UpdateRecurringTransactionRequest) ChangesSchedule() bool {
+	return r.Frequency != nil || r.Interval != nil || r.DayOfMonth != nil || r.StartDate != nil
+}
+
+// RecurringOverrideRequest changes the amount of one occurrence or skips it
+type RecurringOverrideRequest struct {
+	OccurrenceDate string           `json:"occurrence_date" binding:"required"` // YYYY-MM-DD of the scheduled occurrence
+	Amount         *decimal.Decimal `json:"amount"`
+	Skip           bool             `json:"skip"`
+}
+
+// RecurringTransactionResponse represents a recurring transaction with its schedule and progress
+type RecurringTransactionResponse struct {
+	ID                 uint            `json:"id"`
+	Type               string          `json:"type"`
+	Description        string          `json:"description"`
+	Amount             decimal.Decimal `json:"amount"`
+	AccountFromID      uint            `json:"account_from_id"`
+	AccountFromName    string          `json:"account_from_name"`
+	AccountToID        *uint           `json:"account_to_id,omitempty"`
+	AccountToName      string          `json:"account_to_name,omitempty"`
+	CategoryID         *uint           `json:"category_id,omitempty"`
+	CategoryName       string          `json:"category_name,omitempty"`
+	Notes              string          `json:"notes"`
+	Frequency          string          `json:"frequency"`
+	Interval           int             `json:"interval"`
+	DayOfMonth         *int            `json:"day_of_month,omitempty"`
+	StartDate          time.Time       `json:"start_date"`
+	EndDate            *time.Time      `json:"end_date,omitempty"`
+	Count              *int            `json:"count,omitempty"`
+	IsActive           bool            `json:"is_active"`
+	OccurrencesPosted  int             `json:"occurrences_posted"` // Materialized so far (posted or skipped)
+	NextOccurrenceDate *time.Time      `json:"next_occurrence_date"`
+	CreatedAt          time.Time       `json:"created_at"`
+
+	Overrides []RecurringOverrideResponse `json:"overrides"`
+}
+
+// RecurringOverrideResponse represents an override of one occurrence
+type RecurringOverrideResponse struct {
+	ID             uint             `json:"id"`
+	OccurrenceDate time.Time        `json:"occurrence_date"`
+	Amount         *decimal.Decimal `json:"amount,omitempty"`
+	Skip           bool             `json:"skip"`
+}
+
+// RecurringOccurrenceResponse is one occurrence of a schedule.
+// Status is POSTED or SKIPPED for materialized occurrences and SCHEDULED for upcoming ones.
+type RecurringOccurrenceResponse struct {
+	Index         int             `json:"index"`
+	Date          time.Time       `json:"date"`
+	Amount        decimal.Decimal `json:"amount"`
+	Status        string          `json:"status"`
+	TransactionID *uint           `json:"transaction_id,omitempty"`
+}
+
+// RecurringOccurrencesResponse lists the materialized and the upcoming occurrences of a schedule
+type RecurringOccurrencesResponse struct {
+	RecurringTransactionID uint                          `json:"recurring_transaction_id"`
+	Posted                 []RecurringOccurrenceResponse `json:"posted"`
+	Upcoming               []RecurringOccurrenceResponse `json:"upcoming"`
+}
+
+// RecurringRunFailure reports an occurrence that could not be posted; it is retried on the next run
+type RecurringRunFailure struct {
+	RecurringTransactionID uint      `json:"recurring_transaction_id"`
+	UserID                 uint      `json:"user_id"`
+	OccurrenceDate         time.Time `json:"occurrence_date"`
+	Error                  string    `json:"error"`
+}
+
+// RecurringRunReport summarizes a scheduler run
+type RecurringRunReport struct {
+	AsOf               time.Time             `json:"as_of"`
+	TemplatesProcessed int                   `json:"templates_processed"`
+	Posted             int                   `json:"posted"`
+	Skipped            int                   `json:"skipped"`
+	AlreadyPosted      int                   `json:"already_posted"` // Claimed by a concurrent run
+	Failures           []RecurringRunFailure `json:"failures"`
+}
+
+// FromModelToRecurringTransactionResponse converts models.RecurringTransaction to RecurringTransactionResponse
+func FromModelToRecurringTransactionResponse(r *models.RecurringTransaction) RecurringTransactionResponse {
+	resp := RecurringTransactionResponse{
+		ID:                 r.ID,
+		Type:               r.Type,
+		Description:        r.Description,
+		Amount:             r.Amount,
+		AccountFromID:      r.AccountFromID,
+		AccountFromName:    r.AccountFrom.Name,
+		AccountToID:        r.AccountToID,
+		CategoryID:         r.CategoryID,
+		Notes:              r.Notes,
+		Frequency:          r.Frequency,
+		Interval:           r.Interval,
+		DayOfMonth:         r.DayOfMonth,
+		StartDate:          r.StartDate,
+		EndDate:            r.EndDate,
+		Count:              r.Count,
+		IsActive:           r.IsActive,
+		OccurrencesPosted:  r.NextOccurrence,
+		NextOccurrenceDate: r.NextOccurrenceDate,
+		CreatedAt:          r.CreatedAt,
+		Overrides:          []RecurringOverrideResponse{},
+	}
+
+	if r.AccountTo != nil {
+		resp.AccountToName = r.AccountTo.Name
+	}
+	if r.Category != nil {
+		resp.CategoryName = r.Category.Name
+	}
+
+	for _, override := range r.Overrides {
+		resp.Overrides = append(resp.Overrides, RecurringOverrideResponse{
+			ID:             override.ID,
+			OccurrenceDate: override.OccurrenceDate,
+			Amount:         override.Amount,
+			Skip:           override.Skip,
+		})
+	}
+
+	return resp
+}
@@ -0,0 +1,158 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// TrialBalanceReport lists every ledger account with its debit and credit totals as of a date,
+// grouped by ACCOUNT_CLASSIFICATION (ASSET, LIABILITY, EQUITY, INCOME, EXPENSE)
+type TrialBalanceReport struct {
+	AsOf   time.Time           `json:"as_of"`
+	Groups []TrialBalanceGroup `json:"groups"`
+
+	// Grand totals
+	TotalDebits         decimal.Decimal `json:"total_debits"`          // Sum of every debit entry
+	TotalCredits        decimal.Decimal `json:"total_credits"`         // Sum of every credit entry
+	TotalDebitBalances  decimal.Decimal `json:"total_debit_balances"`  // Sum of the debit balance column
+	TotalCreditBalances decimal.Decimal `json:"total_credit_balances"` // Sum of the credit balance column
+	IsBalanced          bool            `json:"is_balanced"`           // Debits = Credits and both balance columns agree
+}
+
+// TrialBalanceGroup holds the accounts of one ACCOUNT_CLASSIFICATION
+type TrialBalanceGroup struct {
+	Classification string             `json:"classification"`
+	Label          string             `json:"label"` // Label from the SystemValue catalog
+	Accounts       []TrialBalanceLine `json:"accounts"`
+	TotalDebits    decimal.Decimal    `json:"total_debits"`
+	TotalCredits   decimal.Decimal    `json:"total_credits"`
+	Balance        decimal.Decimal    `json:"balance"` // Net balance on the group's normal side
+}
+
+// TrialBalanceLine is one account in the trial balance
+type TrialBalanceLine struct {
+	AccountID     uint            `json:"account_id"`
+	AccountName   string          `json:"account_name"`
+	AccountType   string          `json:"account_type"`
+	TotalDebits   decimal.Decimal `json:"total_debits"`
+	TotalCredits  decimal.Decimal `json:"total_credits"`
+	DebitBalance  decimal.Decimal `json:"debit_balance"`  // Debits - Credits when positive, otherwise 0
+	CreditBalance decimal.Decimal `json:"credit_balance"` // Credits - Debits when positive, otherwise 0
+	Balance       decimal.Decimal `json:"balance"`        // Net balance on the account's normal side
+}
+
+// IncomeStatementReport (P&L) shows income and expenses for a date range, by category and by sub-period
+type IncomeStatementReport struct {
+	StartDate time.Time               `json:"start_date"`
+	EndDate   time.Time               `json:"end_date"`
+	Period    string                  `json:"period"`  // month, quarter or year
+	Periods   []IncomeStatementPeriod `json:"periods"` // Column headers; every Amounts slice follows this order
+	Income    IncomeStatementSection  `json:"income"`
+	Expenses  IncomeStatementSection  `json:"expenses"`
+
+	TotalIncome   decimal.Decimal `json:"total_income"`
+	TotalExpenses decimal.Decimal `json:"total_expenses"`
+	NetResult     decimal.Decimal `json:"net_result"` // TotalIncome - TotalExpenses
+}
+
+// IncomeStatementPeriod is one sub-period column of the income statement
+type IncomeStatementPeriod struct {
+	Label         string          `json:"label"` // e.g. 2025-01, 2025-Q1, 2025
+	StartDate     time.Time       `json:"start_date"`
+	EndDate       time.Time       `json:"end_date"` // Exclusive
+	TotalIncome   decimal.Decimal `json:"total_income"`
+	TotalExpenses decimal.Decimal `json:"total_expenses"`
+	NetResult     decimal.Decimal `json:"net_result"`
+}
+
+// IncomeStatementSection groups the income or expense categories with their subtotals
+type IncomeStatementSection struct {
+	Lines   []IncomeStatementLine `json:"lines"`
+	Amounts []decimal.Decimal     `json:"amounts"` // Subtotal per period
+	Total   decimal.Decimal       `json:"total"`
+}
+
+// IncomeStatementLine is one category (nominal ledger account) of the income statement.
+// Amounts are net of reversals: a reversed expense posts the opposite entries and cancels out.
+type IncomeStatementLine struct {
+	AccountID  uint              `json:"account_id"`
+	CategoryID *uint             `json:"category_id,omitempty"`
+	Name       string            `json:"name"`
+	Amounts    []decimal.Decimal `json:"amounts"` // Amount per period
+	Total      decimal.Decimal   `json:"total"`
+}
+
+// BalanceSheetReport shows assets, liabilities and equity per account at one or more points in time.
+// The first column is the requested as_of date; the rest are comparative columns.
+// Every Amounts slice follows the order of Columns.
+type BalanceSheetReport struct {
+	Columns     []BalanceSheetColumn `json:"columns"`
+	Assets      BalanceSheetSection  `json:"assets"`
+	Liabilities BalanceSheetSection  `json:"liabilities"`
+	Equity      BalanceSheetSection  `json:"equity"` // Equity accounts plus accumulated earnings (income - expenses)
+
+	NetWorth                  []decimal.Decimal `json:"net_worth"`                    // Assets - Liabilities
+	TotalLiabilitiesAndEquity []decimal.Decimal `json:"total_liabilities_and_equity"` // Must equal total assets
+	IsBalanced                []bool            `json:"is_balanced"`
+}
+
+// BalanceSheetColumn identifies one point in time of the balance sheet
+type BalanceSheetColumn struct {
+	Label string    `json:"label"`
+	AsOf  time.Time `json:"as_of"`
+}
+
+// BalanceSheetSection groups the lines of one classification with its totals
+type BalanceSheetSection struct {
+	Lines  []BalanceSheetLine `json:"lines"`
+	Totals []decimal.Decimal  `json:"totals"`
+}
+
+// BalanceSheetLine is one account of the balance sheet, with its balance at each column date
+type BalanceSheetLine struct {
+	AccountID   uint              `json:"account_id"` // 0 for the computed accumulated earnings line
+	Name        string            `json:"name"`
+	AccountType string            `json:"account_type"`
+	Amounts     []decimal.Decimal `json:"amounts"`
+}
+
+// CashFlowReport classifies the movements of liquid accounts (BANK, CASH) over a date range.
+// OpeningCash + NetChange = ClosingCash, and ClosingCash must match the liquid accounts
+// of the balance sheet at EndDate (BalanceSheetCash); IsReconciled reports that check.
+type CashFlowReport struct {
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"` // Inclusive
+
+	OpeningCash  decimal.Decimal `json:"opening_cash"`
+	Operating    CashFlowSection `json:"operating"`     // INCOME and EXPENSE transactions
+	Transfers    CashFlowSection `json:"transfers"`     // TRANSFER between own accounts (liquid-to-liquid nets to zero)
+	DebtPayments CashFlowSection `json:"debt_payments"` // DEBT_PAYMENT transactions
+	Adjustments  CashFlowSection `json:"adjustments"`   // OPENING_BALANCE and ADJUSTMENT transactions
+	NetChange    decimal.Decimal `json:"net_change"`
+	ClosingCash  decimal.Decimal `json:"closing_cash"`
+
+	BalanceSheetCash decimal.Decimal       `json:"balance_sheet_cash"`
+	IsReconciled     bool                  `json:"is_reconciled"`
+	Accounts         []CashFlowAccountLine `json:"accounts"`
+}
+
+// CashFlowSection holds the money in, money out and net effect of one flow class
+type CashFlowSection struct {
+	Inflows  decimal.Decimal `json:"inflows"`
+	Outflows decimal.Decimal `json:"outflows"`
+	Net      decimal.Decimal `json:"net"`
+}
+
+// CashFlowAccountLine breaks the cash flow down for one liquid account (net amount per flow class)
+type CashFlowAccountLine struct {
+	AccountID    uint            `json:"account_id"`
+	Name         string          `json:"name"`
+	AccountType  string          `json:"account_type"`
+	OpeningCash  decimal.Decimal `json:"opening_cash"`
+	Operating    decimal.Decimal `json:"operating"`
+	Transfers    decimal.Decimal `json:"transfers"`
+	DebtPayments decimal.Decimal `json:"debt_payments"`
+	Adjustments  decimal.Decimal `json:"adjustments"`
+	ClosingCash  decimal.Decimal `json:"closing_cash"`
+}
@@ -0,0 +1,116 @@
+package dtos
+
+import (
+	"arabella-api/internal/app/models"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// CreateSavingsGoalRequest represents the request payload for creating a savings goal.
+// AccountIDs are SAVINGS accounts whose whole balance counts towards the goal; portions of other
+// accounts are earmarked afterwards through contributions.
+type CreateSavingsGoalRequest struct {
+	Name         string          `json:"name" binding:"required,min=1,max=100"`
+	TargetAmount decimal.Decimal `json:"target_amount"`
+	TargetDate   *string         `json:"target_date"` // ISO 8601 format (YYYY-MM-DD or RFC3339)
+	Notes        string          `json:"notes" binding:"omitempty,max=1000"`
+	AccountIDs   []uint          `json:"account_ids" binding:"omitempty,dive,gt=0"`
+}
+
+// UpdateSavingsGoalRequest represents the request payload for updating a savings goal
+type UpdateSavingsGoalRequest struct {
+	Name         *string          `json:"name" binding:"omitempty,min=1,max=100"`
+	TargetAmount *decimal.Decimal `json:"target_amount"`
+	TargetDate   *string          `json:"target_date"` // Empty string removes the target date
+	Notes        *string          `json:"notes" binding:"omitempty,max=1000"`
+}
+
+// LinkSavingsGoalAccountRequest represents the request payload for linking a SAVINGS account to a goal
+type LinkSavingsGoalAccountRequest struct {
+	AccountID uint `json:"account_id" binding:"required,gt=0"`
+}
+
+// CreateSavingsGoalContributionRequest represents the request payload for earmarking part of an
+// asset account's balance for a goal (negative amounts release a previous earmark)
+type CreateSavingsGoalContributionRequest struct {
+	AccountID        uint            `json:"account_id" binding:"required,gt=0"`
+	Amount           decimal.Decimal `json:"amount"`
+	ContributionDate string          `json:"contribution_date"` // ISO 8601 format; defaults to now
+	Notes            string          `json:"notes" binding:"omitempty,max=1000"`
+}
+
+// SavingsGoalResponse represents a savings goal with its progress.
+// Status is ACHIEVED, ON_TRACK (projected to finish by the target date, or no target date),
+// AT_RISK (projected to finish late) or NO_PROGRESS (nothing saved over the last months).
+type SavingsGoalResponse struct {
+	ID           uint            `json:"id"`
+	Name         string          `json:"name"`
+	TargetAmount decimal.Decimal `json:"target_amount"`
+	TargetDate   *time.Time      `json:"target_date,omitempty"`
+	Notes        string          `json:"notes"`
+	CreatedAt    time.Time       `json:"created_at"`
+
+	Saved           decimal.Decimal `json:"saved"`
+	Remaining       decimal.Decimal `json:"remaining"` // Zero once achieved
+	PercentComplete float64         `json:"percent_complete"`
+	Status          string          `json:"status"`
+
+	MonthlyContributionNeeded  *decimal.Decimal `json:"monthly_contribution_needed,omitempty"` // To reach the target by the target date
+	AverageMonthlyContribution decimal.Decimal  `json:"average_monthly_contribution"`          // Over the last months
+	ProjectedCompletionDate    *time.Time       `json:"projected_completion_date,omitempty"`   // At the average pace; nil without progress
+
+	Accounts      []SavingsGoalAccountResponse      `json:"accounts"`
+	Contributions []SavingsGoalContributionResponse `json:"contributions,omitempty"`
+}
+
+// SavingsGoalAccountResponse is what one account brings to a goal.
+// Link is FULL for linked SAVINGS accounts (Amount = balance) and EARMARK for earmarked portions
+// (Amount = earmarked, capped by the account balance).
+type SavingsGoalAccountResponse struct {
+	AccountID   uint            `json:"account_id"`
+	AccountName string          `json:"account_name"`
+	AccountType string          `json:"account_type"`
+	Balance     decimal.Decimal `json:"balance"`
+	Link        string          `json:"link"`
+	Amount      decimal.Decimal `json:"amount"`
+}
+
+// SavingsGoalContributionResponse represents one earmark (or release) of a goal
+type SavingsGoalContributionResponse struct {
+	ID               uint            `json:"id"`
+	AccountID        uint            `json:"account_id"`
+	AccountName      string          `json:"account_name"`
+	Amount           decimal.Decimal `json:"amount"`
+	ContributionDate time.Time       `json:"contribution_date"`
+	Notes            string          `json:"notes"`
+}
+
+// SavingsGoalSummary aggregates the progress of every savings goal (dashboard section)
+type SavingsGoalSummary struct {
+	TotalTarget     decimal.Decimal       `json:"total_target"`
+	TotalSaved      decimal.Decimal       `json:"total_saved"`
+	PercentComplete float64               `json:"percent_complete"`
+	Achieved        int                   `json:"achieved"`
+	OnTrack         int                   `json:"on_track"`
+	AtRisk          int                   `json:"at_risk"`
+	NoProgress      int                   `json:"no_progress"`
+	Goals           []SavingsGoalResponse `json:"goals"`
+}
+
+// FromModelToSavingsGoalContributionResponse converts models.SavingsGoalContribution to SavingsGoalContributionResponse
+func FromModelToSavingsGoalContributionResponse(contribution *models.SavingsGoalContribution) SavingsGoalContributionResponse {
+	resp := SavingsGoalContributionResponse{
+		ID:               contribution.ID,
+		AccountID:        contribution.AccountID,
+		Amount:           contribution.Amount,
+		ContributionDate: contribution.ContributionDate,
+		Notes:            contribution.Notes,
+	}
+
+	if contribution.Account != nil {
+		resp.AccountName = contribution.Account.Name
+	}
+
+	return resp
+}
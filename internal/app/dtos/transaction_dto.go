@@ -9,7 +9,7 @@ import (
 
 // CreateTransactionRequest represents the request payload for creating a transaction
 type CreateTransactionRequest struct {
-	Type            string          `json:"type" validate:"required,oneof=INCOME EXPENSE TRANSFER"`
+	Type            string          `json:"type" validate:"required,oneof=INCOME EXPENSE TRANSFER DEBT_PAYMENT"`
 	Description     string          `json:"description" validate:"required,min=1,max=255"`
 	Amount          decimal.Decimal `json:"amount" validate:"required"`
 	AccountFromID   uint            `json:"account_from_id" validate:"required,gt=0"`
@@ -18,15 +18,63 @@ type CreateTransactionRequest struct {
 	TransactionDate string          `json:"transaction_date" validate:"required"` // ISO 8601 format
 	Notes           string          `json:"notes" validate:"omitempty,max=1000"`
 	ExchangeRate    decimal.Decimal `json:"exchange_rate" validate:"omitempty,gt=0"`
+
+	// Splits turns an INCOME/EXPENSE into a multi-leg transaction (category_id must be omitted).
+	// The sum of all leg amounts must equal Amount.
+	Splits []TransactionSplitRequest `json:"splits" validate:"omitempty,dive"`
+
+	// Pending creates the transaction unconfirmed: it does not change any balance until it is confirmed.
+	// Future-dated transactions are always created PENDING and posted automatically on their date.
+	Pending bool `json:"pending"`
+}
+
+// TransactionSplitRequest represents one leg of a split transaction.
+// Exactly one of CategoryID or AccountID must be set.
+type TransactionSplitRequest struct {
+	CategoryID  *uint           `json:"category_id" validate:"omitempty,gt=0"`
+	AccountID   *uint           `json:"account_id" validate:"omitempty,gt=0"`
+	Amount      decimal.Decimal `json:"amount" validate:"required"`
+	Description string          `json:"description" validate:"omitempty,max=255"`
+}
+
+// BatchCreateTransactionRequest represents the request payload for creating many transactions at once.
+// Mode "all_or_nothing" (default) posts nothing if any item fails; "best_effort" posts every valid item.
+type BatchCreateTransactionRequest struct {
+	Mode         string                     `json:"mode" binding:"omitempty,oneof=all_or_nothing best_effort"`
+	Transactions []CreateTransactionRequest `json:"transactions" binding:"required,min=1,max=1000"`
 }
 
-// UpdateTransactionRequest represents the request payload for updating a transaction
+// IsAllOrNothing returns true unless the batch explicitly asked for best effort
+func (r *BatchCreateTransactionRequest) IsAllOrNothing() bool {
+	return r.Mode != "best_effort"
+}
+
+// UpdateTransactionRequest represents the request payload for updating a transaction.
+// Changing Amount, TransactionDate, accounts or category reverses the original transaction
+// and posts a corrected replacement (see RequiresRepost).
 type UpdateTransactionRequest struct {
 	Description     *string          `json:"description" validate:"omitempty,min=1,max=255"`
 	Amount          *decimal.Decimal `json:"amount" validate:"omitempty,gt=0"`
 	TransactionDate *string          `json:"transaction_date" validate:"omitempty"` // ISO 8601 format
+	AccountFromID   *uint            `json:"account_from_id" validate:"omitempty,gt=0"`
+	AccountToID     *uint            `json:"account_to_id" validate:"omitempty,gt=0"`
+	CategoryID      *uint            `json:"category_id" validate:"omitempty,gt=0"`
 	Notes           *string          `json:"notes" validate:"omitempty,max=1000"`
-	IsReconciled    *bool            `json:"is_reconciled" validate:"omitempty"`
+	IsReconciled    *bool            `json:"is_reconciled" validate:"omitempty"` // Rejected: reconciliation status is set by completing a reconciliation
+}
+
+// ConfirmTransactionRequest represents the request payload for confirming a pending transaction
+type ConfirmTransactionRequest struct {
+	TransactionDate *string `json:"transaction_date"` // ISO 8601 format; re-dates the transaction before posting it
+}
+
+// RequiresRepost returns true if the request changes any field that affects the journal entries
+func (r *UpdateTransactionRequest) RequiresRepost() bool {
+	return r.Amount != nil ||
+		r.TransactionDate != nil ||
+		r.AccountFromID != nil ||
+		r.AccountToID != nil ||
+		r.CategoryID != nil
 }
 
 // TransactionResponse represents the full transaction response with all relationships
@@ -41,13 +89,38 @@ type TransactionResponse struct {
 	TransactionDate time.Time       `json:"transaction_date"`
 	Notes           string          `json:"notes"`
 	IsReconciled    bool            `json:"is_reconciled"`
+	Status          string          `json:"status"`
 	CreatedAt       time.Time       `json:"created_at"`
 	UpdatedAt       time.Time       `json:"updated_at"`
 
+	// Pending transactions: true when it waits for a confirmation instead of its date
+	RequiresConfirmation bool `json:"requires_confirmation,omitempty"`
+
+	// Reversal links
+	ReversedByTransactionID *uint `json:"reversed_by_transaction_id,omitempty"`
+	ReversesTransactionID   *uint `json:"reverses_transaction_id,omitempty"`
+
+	// Reconciliation that locked the transaction
+	ReconciliationID *uint `json:"reconciliation_id,omitempty"`
+
+	// Edit history
+	ReplacesTransactionID   *uint `json:"replaces_transaction_id,omitempty"`
+	ReplacedByTransactionID *uint `json:"replaced_by_transaction_id,omitempty"`
+
 	// Relationships
-	AccountFrom AccountSummary   `json:"account_from"`
-	AccountTo   *AccountSummary  `json:"account_to,omitempty"`
+	AccountFrom AccountSummary             `json:"account_from"`
+	AccountTo   *AccountSummary            `json:"account_to,omitempty"`
+	Category    *CategorySummary           `json:"category,omitempty"`
+	Splits      []TransactionSplitResponse `json:"splits,omitempty"`
+}
+
+// TransactionSplitResponse represents one leg of a split transaction
+type TransactionSplitResponse struct {
+	ID          uint             `json:"id"`
+	Amount      decimal.Decimal  `json:"amount"`
+	Description string           `json:"description"`
 	Category    *CategorySummary `json:"category,omitempty"`
+	Account     *AccountSummary  `json:"account,omitempty"`
 }
 
 // TransactionSummary represents a lightweight transaction for list views
@@ -70,14 +143,51 @@ type TransactionListResponse struct {
 	TotalPages   int                   `json:"total_pages"`
 }
 
+// BatchCreateTransactionResponse reports the outcome of a batch, item by item in request order.
+// Committed is false when an all-or-nothing batch was rolled back.
+type BatchCreateTransactionResponse struct {
+	Mode      string                   `json:"mode"`
+	Committed bool                     `json:"committed"`
+	Total     int                      `json:"total"`
+	Created   int                      `json:"created"`
+	Failed    int                      `json:"failed"`
+	Results   []BatchTransactionResult `json:"results"`
+}
+
+// BatchTransactionResult is the outcome of one batch item.
+// Status is CREATED, FAILED, or ROLLED_BACK (valid, but discarded with its all-or-nothing batch).
+type BatchTransactionResult struct {
+	Index       int                  `json:"index"` // Position in the request's transactions array
+	Status      string               `json:"status"`
+	Error       string               `json:"error,omitempty"`
+	Transaction *TransactionResponse `json:"transaction,omitempty"`
+}
+
+// PendingRunFailure reports a due pending transaction that could not be posted; it is retried on the next run
+type PendingRunFailure struct {
+	TransactionID uint   `json:"transaction_id"`
+	UserID        uint   `json:"user_id"`
+	Error         string `json:"error"`
+}
+
+// PendingRunReport summarizes a run of the pending transactions job
+type PendingRunReport struct {
+	AsOf          time.Time           `json:"as_of"`
+	Due           int                 `json:"due"`
+	Posted        int                 `json:"posted"`
+	AlreadyPosted int                 `json:"already_posted"` // Confirmed, voided or posted by a concurrent run
+	Failures      []PendingRunFailure `json:"failures"`
+}
+
 // TransactionFilters represents query parameters for filtering transactions
 type TransactionFilters struct {
-	Type         string     `json:"type" validate:"omitempty,oneof=INCOME EXPENSE TRANSFER"`
+	Type         string     `json:"type" validate:"omitempty,oneof=INCOME EXPENSE TRANSFER DEBT_PAYMENT OPENING_BALANCE ADJUSTMENT"`
 	AccountID    *uint      `json:"account_id" validate:"omitempty,gt=0"`
 	CategoryID   *uint      `json:"category_id" validate:"omitempty,gt=0"`
 	StartDate    *time.Time `json:"start_date" validate:"omitempty"`
 	EndDate      *time.Time `json:"end_date" validate:"omitempty"`
 	IsReconciled *bool      `json:"is_reconciled" validate:"omitempty"`
+	Status       string     `json:"status" validate:"omitempty,oneof=PENDING POSTED REVERSED VOIDED"` // Defaults to POSTED
 	Page         int        `json:"page" validate:"omitempty,gte=1"`
 	PageSize     int        `json:"page_size" validate:"omitempty,gte=1,lte=100"`
 }
@@ -102,6 +212,15 @@ func (r *CreateTransactionRequest) ToModel(userID uint) (*models.Transaction, er
 		ExchangeRate:    r.ExchangeRate,
 	}
 
+	for _, leg := range r.Splits {
+		tx.Splits = append(tx.Splits, models.TransactionSplit{
+			CategoryID:  leg.CategoryID,
+			AccountID:   leg.AccountID,
+			Amount:      leg.Amount,
+			Description: leg.Description,
+		})
+	}
+
 	// Set default exchange rate if not provided
 	if tx.ExchangeRate.IsZero() {
 		tx.ExchangeRate = decimal.NewFromInt(1)
@@ -126,8 +245,19 @@ func FromModelToTransactionResponse(tx *models.Transaction) TransactionResponse
 		TransactionDate: tx.TransactionDate,
 		Notes:           tx.Notes,
 		IsReconciled:    tx.IsReconciled,
+		Status:          tx.Status,
 		CreatedAt:       tx.CreatedAt,
 		UpdatedAt:       tx.UpdatedAt,
+
+		RequiresConfirmation: tx.RequiresConfirmation,
+
+		ReversedByTransactionID: tx.ReversedByTransactionID,
+		ReversesTransactionID:   tx.ReversesTransactionID,
+
+		ReconciliationID: tx.ReconciliationID,
+
+		ReplacesTransactionID:   tx.ReplacesTransactionID,
+		ReplacedByTransactionID: tx.ReplacedByTransactionID,
 	}
 
 	// Include related data if loaded
@@ -179,6 +309,30 @@ func FromModelToTransactionResponse(tx *models.Transaction) TransactionResponse
 		}
 	}
 
+	for _, leg := range tx.Splits {
+		split := TransactionSplitResponse{
+			ID:          leg.ID,
+			Amount:      leg.Amount,
+			Description: leg.Description,
+		}
+
+		if leg.Category != nil && leg.Category.ID != 0 {
+			categorySummary := FromModelToCategorySummary(leg.Category)
+			split.Category = &categorySummary
+		}
+
+		if leg.Account != nil && leg.Account.ID != 0 {
+			split.Account = &AccountSummary{
+				ID:      leg.Account.ID,
+				Name:    leg.Account.Name,
+				Type:    leg.Account.AccountType,
+				Balance: leg.Account.Balance,
+			}
+		}
+
+		resp.Splits = append(resp.Splits, split)
+	}
+
 	return resp
 }
 
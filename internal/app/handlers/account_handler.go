@@ -3,6 +3,7 @@ package handlers
 import (
 	"arabella-api/internal/app/dtos"
 	"arabella-api/internal/app/services"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -23,7 +24,7 @@ func NewAccountHandler(accountService services.AccountService) *AccountHandler {
 
 // GetAccounts godoc
 // @Summary      Listar cuentas
-// @Description  Obtiene todas las cuentas financieras del usuario autenticado (BANK, CASH, CREDIT_CARD, SAVINGS, INVESTMENT)
+// @Description  Obtiene todas las cuentas financieras del usuario autenticado (BANK, CASH, CREDIT_CARD, SAVINGS, INVESTMENT, LOAN, MORTGAGE)
 // @Tags         Accounts
 // @Produce      json
 // @Success      200  {object}  object{data=[]dtos.AccountResponseDTO,count=int}  "Lista de cuentas"
@@ -91,14 +92,16 @@ func (h *AccountHandler) GetAccountByID(c *gin.Context) {
 
 // CreateAccount godoc
 // @Summary      Crear cuenta
-// @Description  Crea una nueva cuenta financiera para el usuario autenticado. El campo user_id se asigna automáticamente desde el JWT. Tipos válidos: BANK, CASH, CREDIT_CARD, SAVINGS, INVESTMENT
+// @Description  Crea una nueva cuenta financiera para el usuario autenticado. El campo user_id se asigna automáticamente desde el JWT. Tipos válidos: BANK, CASH, CREDIT_CARD, SAVINGS, INVESTMENT, LOAN, MORTGAGE. Si se envía `balance`, se contabiliza como saldo inicial (OPENING_BALANCE) contra la cuenta de patrimonio en la fecha `opening_date`. LOAN y MORTGAGE requieren `loan` (capital, tasa anual, plazo en meses y día de pago); su saldo inicial por defecto es el capital. CREDIT_CARD acepta opcionalmente `credit_card` (límite, día de corte, día de pago y regla de pago mínimo)
 // @Tags         Accounts
 // @Accept       json
 // @Produce      json
 // @Param        body  body      dtos.CreateAccountDTO                        true  "Datos de la nueva cuenta"
+// @Param        Idempotency-Key  header  string  false  "Clave de idempotencia: repetir la petición con la misma clave devuelve la respuesta original sin volver a crear la cuenta"
 // @Success      201   {object}  object{message=string,data=dtos.AccountResponseDTO}  "Cuenta creada exitosamente"
 // @Failure      400   {object}  dtos.ErrorResponse                           "Datos inválidos"
 // @Failure      401   {object}  dtos.ErrorResponse                           "No autenticado"
+// @Failure      409   {object}  dtos.ErrorResponse                           "Idempotency-Key reutilizada con otra petición o aún en proceso"
 // @Failure      500   {object}  dtos.ErrorResponse                           "Error interno del servidor"
 // @Security     BearerAuth
 // @Router       /accounts [post]
@@ -137,7 +140,7 @@ func (h *AccountHandler) CreateAccount(c *gin.Context) {
 
 // UpdateAccount godoc
 // @Summary      Actualizar cuenta
-// @Description  Actualiza los datos de una cuenta financiera existente. Solo se actualizan los campos enviados (PATCH semántico)
+// @Description  Actualiza los datos de una cuenta financiera existente. Solo se actualizan los campos enviados (PATCH semántico). El saldo no puede modificarse aquí; usa POST /accounts/{id}/balance-adjustment
 // @Tags         Accounts
 // @Accept       json
 // @Produce      json
@@ -146,6 +149,7 @@ func (h *AccountHandler) CreateAccount(c *gin.Context) {
 // @Success      200   {object}  dtos.SuccessResponse      "Cuenta actualizada exitosamente"
 // @Failure      400   {object}  dtos.ErrorResponse        "ID o datos inválidos"
 // @Failure      401   {object}  dtos.ErrorResponse        "No autenticado"
+// @Failure      409   {object}  dtos.ErrorResponse        "La cuenta fue modificada por otra petición"
 // @Failure      500   {object}  dtos.ErrorResponse        "Error interno del servidor"
 // @Security     BearerAuth
 // @Router       /accounts/{id} [put]
@@ -169,6 +173,20 @@ func (h *AccountHandler) UpdateAccount(c *gin.Context) {
 	}
 
 	if err := h.accountService.Update(uint(id), &req); err != nil {
+		if errors.Is(err, services.ErrDirectBalanceWrite) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Balance cannot be updated directly",
+				"details": err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, services.ErrAccountVersionConflict) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Account was modified concurrently",
+				"details": err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to update account",
 			"details": err.Error(),
@@ -181,6 +199,67 @@ func (h *AccountHandler) UpdateAccount(c *gin.Context) {
 	})
 }
 
+// AdjustAccountBalance godoc
+// @Summary      Ajustar saldo de cuenta
+// @Description  Lleva una cuenta al saldo indicado registrando una transacción ADJUSTMENT por la diferencia contra la cuenta de patrimonio, a través del Motor Contable. Si el saldo ya coincide no se crea ninguna transacción
+// @Tags         Accounts
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                                                  true  "ID de la cuenta"
+// @Param        body  body      dtos.BalanceAdjustmentDTO                            true  "Saldo objetivo, fecha y notas"
+// @Success      200   {object}  object{message=string,data=dtos.TransactionResponse}  "Saldo ajustado exitosamente"
+// @Failure      400   {object}  dtos.ErrorResponse                                   "ID o datos inválidos"
+// @Failure      401   {object}  dtos.ErrorResponse                                   "No autenticado"
+// @Failure      500   {object}  dtos.ErrorResponse                                   "Error interno del servidor"
+// @Security     BearerAuth
+// @Router       /accounts/{id}/balance-adjustment [post]
+func (h *AccountHandler) AdjustAccountBalance(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid account ID",
+		})
+		return
+	}
+
+	var req dtos.BalanceAdjustmentDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	adjustment, err := h.accountService.AdjustBalance(userID, uint(id), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to adjust account balance",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if adjustment == nil {
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Account balance already matches; no adjustment posted",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Account balance adjusted successfully",
+		"data":    dtos.FromModelToTransactionResponse(adjustment),
+	})
+}
+
 // DeleteAccount godoc
 // @Summary      Eliminar cuenta
 // @Description  Realiza un borrado lógico (soft delete) de una cuenta financiera. Los registros históricos se conservan
@@ -215,3 +294,135 @@ func (h *AccountHandler) DeleteAccount(c *gin.Context) {
 		"message": "Account deleted successfully",
 	})
 }
+
+// GetAmortizationSchedule godoc
+// @Summary      Tabla de amortización
+// @Description  Genera la tabla de amortización completa de una cuenta LOAN o MORTGAGE a partir de sus condiciones: cuota fija mensual, y para cada cuota su fecha de vencimiento, capital, interés y saldo pendiente. Incluye el saldo adeudado actual y el próximo vencimiento
+// @Tags         Accounts
+// @Produce      json
+// @Param        id   path      int                                                true  "ID de la cuenta"
+// @Success      200  {object}  object{data=dtos.AmortizationScheduleResponse}     "Tabla de amortización"
+// @Failure      400  {object}  dtos.ErrorResponse                                 "ID inválido o la cuenta no es un préstamo"
+// @Failure      401  {object}  dtos.ErrorResponse                                 "No autenticado"
+// @Security     BearerAuth
+// @Router       /accounts/{id}/amortization [get]
+func (h *AccountHandler) GetAmortizationSchedule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid account ID",
+		})
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	schedule, err := h.accountService.GetAmortizationSchedule(userID, uint(id))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to generate amortization schedule",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": schedule,
+	})
+}
+
+// SetCreditCardTerms godoc
+// @Summary      Configurar condiciones de tarjeta de crédito
+// @Description  Establece o reemplaza las condiciones de una cuenta CREDIT_CARD: límite de crédito, día de corte del estado de cuenta, día de pago (el primero posterior al corte), pago mínimo (porcentaje del saldo del estado con un monto mínimo) y tasa anual
+// @Tags         Accounts
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                                                            true  "ID de la cuenta"
+// @Param        body  body      dtos.CreditCardTermsDTO                                        true  "Condiciones de la tarjeta"
+// @Success      200   {object}  object{message=string,data=dtos.AccountResponseDTO}            "Condiciones guardadas"
+// @Failure      400   {object}  dtos.ErrorResponse                                             "Datos inválidos o la cuenta no es una tarjeta de crédito"
+// @Failure      401   {object}  dtos.ErrorResponse                                             "No autenticado"
+// @Security     BearerAuth
+// @Router       /accounts/{id}/credit-card [put]
+func (h *AccountHandler) SetCreditCardTerms(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid account ID",
+		})
+		return
+	}
+
+	var req dtos.CreditCardTermsDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	account, err := h.accountService.SetCreditCardTerms(userID, uint(id), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to set credit card terms",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Credit card terms saved successfully",
+		"data":    account,
+	})
+}
+
+// GetAccountStatement godoc
+// @Summary      Estado de cuenta de tarjeta de crédito
+// @Description  Calcula el ciclo actual de una cuenta CREDIT_CARD con condiciones: saldo del último estado de cuenta, pagos desde el corte, saldo pendiente del estado y pago mínimo, fecha de pago y estado (PAID, DUE, OVERDUE), saldo no facturado, crédito disponible y próximas fechas de corte y de pago
+// @Tags         Accounts
+// @Produce      json
+// @Param        id   path      int                                                true  "ID de la cuenta"
+// @Success      200  {object}  object{data=dtos.CreditCardStatementResponse}      "Estado de cuenta"
+// @Failure      400  {object}  dtos.ErrorResponse                                 "ID inválido o la tarjeta no tiene condiciones"
+// @Failure      401  {object}  dtos.ErrorResponse                                 "No autenticado"
+// @Security     BearerAuth
+// @Router       /accounts/{id}/statement [get]
+func (h *AccountHandler) GetAccountStatement(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid account ID",
+		})
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	statement, err := h.accountService.GetStatement(userID, uint(id))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to compute statement",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": statement,
+	})
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"arabella-api/internal/app/dtos"
+	"arabella-api/internal/app/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccountingPeriodHandler handles accounting period HTTP requests
+type AccountingPeriodHandler struct {
+	periodService services.AccountingPeriodService
+}
+
+// NewAccountingPeriodHandler creates a new accounting period handler
+func NewAccountingPeriodHandler(periodService services.AccountingPeriodService) *AccountingPeriodHandler {
+	return &AccountingPeriodHandler{
+		periodService: periodService,
+	}
+}
+
+// GetAccountingPeriods godoc
+// @Summary      Listar periodos contables
+// @Description  Obtiene los periodos contables (meses) que el usuario autenticado ha cerrado alguna vez, con su estado actual. Los meses sin registro están abiertos
+// @Tags         Accounting Periods
+// @Produce      json
+// @Success      200  {object}  object{data=[]dtos.AccountingPeriodResponse,count=int}  "Lista de periodos contables"
+// @Failure      401  {object}  dtos.ErrorResponse                                      "No autenticado"
+// @Failure      500  {object}  dtos.ErrorResponse                                      "Error interno del servidor"
+// @Security     BearerAuth
+// @Router       /accounting-periods [get]
+func (h *AccountingPeriodHandler) GetAccountingPeriods(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	periods, err := h.periodService.GetByUser(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve accounting periods",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  periods,
+		"count": len(periods),
+	})
+}
+
+// CloseAccountingPeriod godoc
+// @Summary      Cerrar periodo contable
+// @Description  Cierra un mes del usuario autenticado. Mientras esté cerrado, el Motor Contable rechaza crear, editar o revertir transacciones con fecha dentro del periodo. La acción queda registrada en el historial de auditoría
+// @Tags         Accounting Periods
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dtos.AccountingPeriodActionRequest                         true  "Año, mes y motivo"
+// @Success      200   {object}  object{message=string,data=dtos.AccountingPeriodResponse}  "Periodo cerrado exitosamente"
+// @Failure      400   {object}  dtos.ErrorResponse                                         "Datos inválidos o periodo ya cerrado"
+// @Failure      401   {object}  dtos.ErrorResponse                                         "No autenticado"
+// @Security     BearerAuth
+// @Router       /accounting-periods/close [post]
+func (h *AccountingPeriodHandler) CloseAccountingPeriod(c *gin.Context) {
+	var req dtos.AccountingPeriodActionRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	period, err := h.periodService.Close(userID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to close accounting period",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Accounting period closed successfully",
+		"data":    period,
+	})
+}
+
+// ReopenAccountingPeriod godoc
+// @Summary      Reabrir periodo contable
+// @Description  Reabre un mes cerrado del usuario autenticado para permitir correcciones. Solo el propietario puede reabrir sus periodos. La acción queda registrada en el historial de auditoría
+// @Tags         Accounting Periods
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dtos.AccountingPeriodActionRequest                         true  "Año, mes y motivo"
+// @Success      200   {object}  object{message=string,data=dtos.AccountingPeriodResponse}  "Periodo reabierto exitosamente"
+// @Failure      400   {object}  dtos.ErrorResponse                                         "Datos inválidos o periodo no cerrado"
+// @Failure      401   {object}  dtos.ErrorResponse                                         "No autenticado"
+// @Security     BearerAuth
+// @Router       /accounting-periods/reopen [post]
+func (h *AccountingPeriodHandler) ReopenAccountingPeriod(c *gin.Context) {
+	var req dtos.AccountingPeriodActionRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	period, err := h.periodService.Reopen(userID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to reopen accounting period",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Accounting period reopened successfully",
+		"data":    period,
+	})
+}
+
+// GetAccountingPeriodEvents godoc
+// @Summary      Historial de auditoría de un periodo
+// @Description  Obtiene en orden cronológico los cierres y reaperturas de un periodo contable del usuario autenticado
+// @Tags         Accounting Periods
+// @Produce      json
+// @Param        id   path      int                                                          true  "ID del periodo contable"
+// @Success      200  {object}  object{data=[]dtos.AccountingPeriodEventResponse,count=int}  "Historial del periodo"
+// @Failure      400  {object}  dtos.ErrorResponse                                           "ID inválido"
+// @Failure      401  {object}  dtos.ErrorResponse                                           "No autenticado"
+// @Failure      404  {object}  dtos.ErrorResponse                                           "Periodo no encontrado"
+// @Security     BearerAuth
+// @Router       /accounting-periods/{id}/events [get]
+func (h *AccountingPeriodHandler) GetAccountingPeriodEvents(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid accounting period ID",
+		})
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	events, err := h.periodService.GetEvents(userID, uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Accounting period not found",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  events,
+		"count": len(events),
+	})
+}
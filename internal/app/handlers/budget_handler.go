@@ -0,0 +1,357 @@
+package handlers
+
+import (
+	"arabella-api/internal/app/dtos"
+	"arabella-api/internal/app/services"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BudgetHandler handles budget HTTP requests
+type BudgetHandler struct {
+	budgetService services.BudgetService
+}
+
+// NewBudgetHandler creates a new budget handler
+func NewBudgetHandler(budgetService services.BudgetService) *BudgetHandler {
+	return &BudgetHandler{
+		budgetService: budgetService,
+	}
+}
+
+// CreateBudget godoc
+// @Summary      Crear presupuesto
+// @Description  Crea un presupuesto sobre una categoría de gastos para un período que se repite: MONTHLY (meses calendario), WEEKLY (7 días) o CUSTOM (`period_days` días) desde `start_date`, opcionalmente hasta `end_date`. `rollover` define qué pasa al cerrar un período: NONE (cada período empieza de cero), UNSPENT (lo no gastado se suma al siguiente) o ALL (también el exceso de gasto se descuenta del siguiente). Una categoría no puede tener dos presupuestos que se solapen
+// @Tags         Budgets
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dtos.CreateBudgetRequest                          true  "Presupuesto"
+// @Success      201   {object}  object{message=string,data=dtos.BudgetResponse}  "Presupuesto creado"
+// @Failure      400   {object}  dtos.ErrorResponse                               "Datos inválidos"
+// @Failure      401   {object}  dtos.ErrorResponse                               "No autenticado"
+// @Failure      409   {object}  dtos.ErrorResponse                               "La categoría ya tiene un presupuesto en ese período"
+// @Security     BearerAuth
+// @Router       /budgets [post]
+func (h *BudgetHandler) CreateBudget(c *gin.Context) {
+	var req dtos.CreateBudgetRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	budget, err := h.budgetService.Create(userID, &req)
+	if err != nil {
+		respondBudgetError(c, "Failed to create budget", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Budget created successfully",
+		"data":    budget,
+	})
+}
+
+// GetBudgets godoc
+// @Summary      Listar presupuestos
+// @Description  Obtiene los presupuestos del usuario con las cifras del período que contiene `date`: presupuestado, arrastre del período anterior, disponible, gastado (transacciones EXPENSE de la categoría, incluidas las partidas de transacciones divididas), restante y porcentaje usado. Estado: ON_TRACK, WARNING (≥80%) u OVER
+// @Tags         Budgets
+// @Produce      json
+// @Param        date  query     string                                       false  "Fecha de referencia (YYYY-MM-DD o RFC3339, default: hoy)"
+// @Success      200   {object}  object{data=[]dtos.BudgetResponse,count=int}  "Lista de presupuestos"
+// @Failure      400   {object}  dtos.ErrorResponse                            "Fecha inválida"
+// @Failure      401   {object}  dtos.ErrorResponse                            "No autenticado"
+// @Failure      500   {object}  dtos.ErrorResponse                            "Error interno del servidor"
+// @Security     BearerAuth
+// @Router       /budgets [get]
+func (h *BudgetHandler) GetBudgets(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	asOf, ok := parseBudgetDate(c)
+	if !ok {
+		return
+	}
+
+	budgets, err := h.budgetService.GetByUser(userID, asOf)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve budgets",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  budgets,
+		"count": len(budgets),
+	})
+}
+
+// GetBudgetSummary godoc
+// @Summary      Resumen de presupuestos
+// @Description  Suma disponible, gastado y restante de los presupuestos con un período en curso en `date`, y cuántos están ON_TRACK, WARNING u OVER
+// @Tags         Budgets
+// @Produce      json
+// @Param        date  query     string                                 false  "Fecha de referencia (YYYY-MM-DD o RFC3339, default: hoy)"
+// @Success      200   {object}  object{data=dtos.BudgetSummary}        "Resumen de presupuestos"
+// @Failure      400   {object}  dtos.ErrorResponse                     "Fecha inválida"
+// @Failure      401   {object}  dtos.ErrorResponse                     "No autenticado"
+// @Failure      500   {object}  dtos.ErrorResponse                     "Error interno del servidor"
+// @Security     BearerAuth
+// @Router       /budgets/summary [get]
+func (h *BudgetHandler) GetBudgetSummary(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	asOf, ok := parseBudgetDate(c)
+	if !ok {
+		return
+	}
+
+	summary, err := h.budgetService.GetSummary(userID, asOf)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve budget summary",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": summary,
+	})
+}
+
+// GetBudgetByID godoc
+// @Summary      Obtener presupuesto
+// @Description  Obtiene un presupuesto con las cifras del período que contiene `date`
+// @Tags         Budgets
+// @Produce      json
+// @Param        id    path      int                                 true   "ID del presupuesto"
+// @Param        date  query     string                              false  "Fecha de referencia (YYYY-MM-DD o RFC3339, default: hoy)"
+// @Success      200   {object}  object{data=dtos.BudgetResponse}   "Presupuesto"
+// @Failure      400   {object}  dtos.ErrorResponse                 "ID o fecha inválidos"
+// @Failure      401   {object}  dtos.ErrorResponse                 "No autenticado"
+// @Failure      404   {object}  dtos.ErrorResponse                 "Presupuesto no encontrado"
+// @Security     BearerAuth
+// @Router       /budgets/{id} [get]
+func (h *BudgetHandler) GetBudgetByID(c *gin.Context) {
+	id, ok := parseBudgetID(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	asOf, ok := parseBudgetDate(c)
+	if !ok {
+		return
+	}
+
+	budget, err := h.budgetService.GetByID(userID, id, asOf)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Budget not found",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": budget,
+	})
+}
+
+// GetBudgetPeriods godoc
+// @Summary      Períodos de un presupuesto
+// @Description  Lista todos los períodos del presupuesto hasta el que contiene `date` (el más antiguo primero), mostrando cómo se acumula el arrastre
+// @Tags         Budgets
+// @Produce      json
+// @Param        id    path      int                                               true   "ID del presupuesto"
+// @Param        date  query     string                                            false  "Fecha de referencia (YYYY-MM-DD o RFC3339, default: hoy)"
+// @Success      200   {object}  object{data=[]dtos.BudgetPeriodResponse,count=int}  "Períodos del presupuesto"
+// @Failure      400   {object}  dtos.ErrorResponse                                "ID o fecha inválidos"
+// @Failure      401   {object}  dtos.ErrorResponse                                "No autenticado"
+// @Failure      404   {object}  dtos.ErrorResponse                                "Presupuesto no encontrado"
+// @Security     BearerAuth
+// @Router       /budgets/{id}/periods [get]
+func (h *BudgetHandler) GetBudgetPeriods(c *gin.Context) {
+	id, ok := parseBudgetID(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	asOf, ok := parseBudgetDate(c)
+	if !ok {
+		return
+	}
+
+	periods, err := h.budgetService.GetPeriods(userID, id, asOf)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Failed to retrieve budget periods",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  periods,
+		"count": len(periods),
+	})
+}
+
+// UpdateBudget godoc
+// @Summary      Actualizar presupuesto
+// @Description  Modifica el monto, la fecha de fin (`end_date` vacío la quita), el arrastre o las notas de un presupuesto. El monto aplica a todos los períodos, incluidos los pasados que alimentan el arrastre; para cambiarlo desde un período en adelante, finalice el presupuesto y cree uno nuevo
+// @Tags         Budgets
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                                              true  "ID del presupuesto"
+// @Param        body  body      dtos.UpdateBudgetRequest                          true  "Campos a actualizar"
+// @Success      200   {object}  object{message=string,data=dtos.BudgetResponse}  "Presupuesto actualizado"
+// @Failure      400   {object}  dtos.ErrorResponse                               "ID o datos inválidos"
+// @Failure      401   {object}  dtos.ErrorResponse                               "No autenticado"
+// @Failure      409   {object}  dtos.ErrorResponse                               "La categoría ya tiene un presupuesto en ese período"
+// @Security     BearerAuth
+// @Router       /budgets/{id} [put]
+func (h *BudgetHandler) UpdateBudget(c *gin.Context) {
+	id, ok := parseBudgetID(c)
+	if !ok {
+		return
+	}
+
+	var req dtos.UpdateBudgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	budget, err := h.budgetService.Update(userID, id, &req)
+	if err != nil {
+		respondBudgetError(c, "Failed to update budget", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Budget updated successfully",
+		"data":    budget,
+	})
+}
+
+// DeleteBudget godoc
+// @Summary      Eliminar presupuesto
+// @Description  Elimina un presupuesto; las transacciones de la categoría no se modifican
+// @Tags         Budgets
+// @Produce      json
+// @Param        id   path      int                   true  "ID del presupuesto"
+// @Success      200  {object}  dtos.SuccessResponse  "Presupuesto eliminado"
+// @Failure      400  {object}  dtos.ErrorResponse    "ID inválido"
+// @Failure      401  {object}  dtos.ErrorResponse    "No autenticado"
+// @Failure      404  {object}  dtos.ErrorResponse    "Presupuesto no encontrado"
+// @Security     BearerAuth
+// @Router       /budgets/{id} [delete]
+func (h *BudgetHandler) DeleteBudget(c *gin.Context) {
+	id, ok := parseBudgetID(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	if err := h.budgetService.Delete(userID, id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Failed to delete budget",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Budget deleted successfully",
+	})
+}
+
+// respondBudgetError maps budget validation errors: overlaps are conflicts, the rest bad requests
+func respondBudgetError(c *gin.Context, message string, err error) {
+	status := http.StatusBadRequest
+	if errors.Is(err, services.ErrBudgetOverlap) {
+		status = http.StatusConflict
+	}
+
+	c.JSON(status, gin.H{
+		"error":   message,
+		"details": err.Error(),
+	})
+}
+
+// parseBudgetID reads the :id path parameter, responding 400 when it is not a valid ID
+func parseBudgetID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid budget ID",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// parseBudgetDate reads the optional date query parameter (default: now), responding 400 when invalid
+func parseBudgetDate(c *gin.Context) (time.Time, bool) {
+	date, err := parseDateQuery(c, "date", true)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid date",
+			"details": err.Error(),
+		})
+		return time.Time{}, false
+	}
+	if date == nil {
+		return time.Now(), true
+	}
+	return *date, true
+}
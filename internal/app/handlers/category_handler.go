@@ -107,9 +107,11 @@ func (h *CategoryHandler) GetCategoryByID(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        body  body      dtos.CreateCategoryRequest                          true  "Datos de la nueva categoría"
+// @Param        Idempotency-Key  header  string  false  "Clave de idempotencia: repetir la petición con la misma clave devuelve la respuesta original sin volver a crear la categoría"
 // @Success      201   {object}  object{message=string,data=dtos.CategoryResponse}  "Categoría creada exitosamente"
 // @Failure      400   {object}  dtos.ErrorResponse                                 "Datos inválidos"
 // @Failure      401   {object}  dtos.ErrorResponse                                 "No autenticado"
+// @Failure      409   {object}  dtos.ErrorResponse                                 "Idempotency-Key reutilizada con otra petición o aún en proceso"
 // @Failure      500   {object}  dtos.ErrorResponse                                 "Error interno del servidor"
 // @Security     BearerAuth
 // @Router       /categories [post]
@@ -3,6 +3,7 @@ package handlers
 import (
 	"arabella-api/internal/app/services"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -117,3 +118,49 @@ func (h *DashboardHandler) GetMonthlyStats(c *gin.Context) {
 		"data": stats,
 	})
 }
+
+// GetProjection godoc
+// @Summary      Proyección de saldos con transacciones pendientes
+// @Description  Proyecta los saldos del usuario a una fecha aplicando las transacciones pendientes (futuras o sin confirmar) fechadas hasta entonces, que aún no afectan los saldos. Devuelve los activos líquidos y el patrimonio neto actuales y proyectados, el saldo proyectado de cada cuenta afectada y la lista de transacciones pendientes
+// @Tags         Dashboard
+// @Produce      json
+// @Param        until  query     string  false  "Fecha límite de la proyección (YYYY-MM-DD o RFC3339, default: 30 días desde hoy)"
+// @Success      200    {object}  object{data=dtos.BalanceProjection}  "Proyección de saldos"
+// @Failure      400    {object}  dtos.ErrorResponse                   "Fecha inválida"
+// @Failure      401    {object}  dtos.ErrorResponse                   "No autenticado"
+// @Failure      500    {object}  dtos.ErrorResponse                   "Error interno del servidor"
+// @Security     BearerAuth
+// @Router       /dashboard/projection [get]
+func (h *DashboardHandler) GetProjection(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	until, err := parseDateQuery(c, "until", true)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid until date",
+			"details": err.Error(),
+		})
+		return
+	}
+	if until == nil {
+		defaultUntil := time.Now().AddDate(0, 0, 30)
+		until = &defaultUntil
+	}
+
+	projection, err := h.dashboardService.GetProjection(userID, *until)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to calculate projection",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": projection,
+	})
+}
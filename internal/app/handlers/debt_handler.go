@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"arabella-api/internal/app/dtos"
+	"arabella-api/internal/app/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DebtHandler handles HTTP requests on the user's debts as a whole
+type DebtHandler struct {
+	debtService services.DebtService
+}
+
+// NewDebtHandler creates a new debt handler
+func NewDebtHandler(debtService services.DebtService) *DebtHandler {
+	return &DebtHandler{
+		debtService: debtService,
+	}
+}
+
+// SimulateDebtPayoff godoc
+// @Summary      Simular el pago de deudas
+// @Description  Simula mes a mes el pago de todas las cuentas de pasivo con saldo (CREDIT_CARD, LOAN, MORTGAGE) con un presupuesto fijo: los pagos mínimos más `extra_monthly_payment`. Compara las estrategias AVALANCHE (mayor tasa primero), SNOWBALL (menor saldo primero) y, si se envía `custom_order`, CUSTOM, con la fecha de liquidación, el interés total y el calendario de cada deuda. Los préstamos usan por defecto la tasa y la cuota de sus condiciones, y las tarjetas su tasa y su regla de pago mínimo; las tarjetas sin condiciones requieren `interest_rate` y `minimum_payment` en `debts`
+// @Tags         Debts
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dtos.DebtPayoffSimulationRequest                          true  "Parámetros de la simulación"
+// @Success      200   {object}  object{data=dtos.DebtPayoffSimulationResponse}            "Resultado de la simulación"
+// @Failure      400   {object}  dtos.ErrorResponse                                        "Datos inválidos o deudas sin condiciones"
+// @Failure      401   {object}  dtos.ErrorResponse                                        "No autenticado"
+// @Security     BearerAuth
+// @Router       /debts/payoff-simulation [post]
+func (h *DebtHandler) SimulateDebtPayoff(c *gin.Context) {
+	var req dtos.DebtPayoffSimulationRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	simulation, err := h.debtService.SimulatePayoff(userID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to simulate debt payoff",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": simulation,
+	})
+}
+
+// GetUpcomingDebts godoc
+// @Summary      Deudas próximas a vencer
+// @Description  Lista el próximo pago de cada tarjeta de crédito con condiciones y de cada préstamo, ordenados por fecha de vencimiento: para las tarjetas, el saldo pendiente del último estado de cuenta mientras esté por vencer o vencido (o lo no facturado si ya está pagado); para los préstamos, la próxima cuota. Los estados vencidos siempre se incluyen; el resto solo si vence dentro de `days` días. Estado: OVERDUE, DUE_SOON (en 7 días o menos) o UPCOMING
+// @Tags         Debts
+// @Produce      json
+// @Param        days  query     int                                                false  "Días hacia adelante (1-366, por defecto 30)"
+// @Success      200   {object}  object{data=dtos.UpcomingDebtsResponse}            "Deudas próximas a vencer"
+// @Failure      400   {object}  dtos.ErrorResponse                                 "Parámetros inválidos"
+// @Failure      401   {object}  dtos.ErrorResponse                                 "No autenticado"
+// @Security     BearerAuth
+// @Router       /debts/upcoming [get]
+func (h *DebtHandler) GetUpcomingDebts(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	upcoming, err := h.debtService.GetUpcoming(userID, parseIntParam(c, "days", 30))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to retrieve upcoming debts",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": upcoming,
+	})
+}
@@ -0,0 +1,318 @@
+package handlers
+
+import (
+	"arabella-api/internal/app/dtos"
+	"arabella-api/internal/app/services"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EnvelopeHandler handles envelope HTTP requests
+type EnvelopeHandler struct {
+	envelopeService services.EnvelopeService
+}
+
+// NewEnvelopeHandler creates a new envelope handler
+func NewEnvelopeHandler(envelopeService services.EnvelopeService) *EnvelopeHandler {
+	return &EnvelopeHandler{
+		envelopeService: envelopeService,
+	}
+}
+
+// CreateEnvelope godoc
+// @Summary      Crear sobre
+// @Description  Crea el sobre de una categoría de gastos. Las transacciones EXPENSE de la categoría desde `start_date` descuentan de su saldo. Si se indica `amount`, se le asigna de inmediato desde los fondos sin asignar
+// @Tags         Envelopes
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dtos.CreateEnvelopeRequest                          true  "Sobre"
+// @Success      201   {object}  object{message=string,data=dtos.EnvelopeResponse}  "Sobre creado"
+// @Failure      400   {object}  dtos.ErrorResponse                                 "Datos inválidos"
+// @Failure      401   {object}  dtos.ErrorResponse                                 "No autenticado"
+// @Failure      409   {object}  dtos.ErrorResponse                                 "La categoría ya tiene un sobre o fondos sin asignar insuficientes"
+// @Security     BearerAuth
+// @Router       /envelopes [post]
+func (h *EnvelopeHandler) CreateEnvelope(c *gin.Context) {
+	var req dtos.CreateEnvelopeRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	envelope, err := h.envelopeService.Create(userID, &req)
+	if err != nil {
+		respondEnvelopeError(c, "Failed to create envelope", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Envelope created successfully",
+		"data":    envelope,
+	})
+}
+
+// GetEnvelopes godoc
+// @Summary      Listar sobres
+// @Description  Obtiene los sobres del usuario con lo asignado, lo gastado y el saldo (negativo si se gastó de más)
+// @Tags         Envelopes
+// @Produce      json
+// @Success      200  {object}  object{data=[]dtos.EnvelopeResponse,count=int}  "Lista de sobres"
+// @Failure      401  {object}  dtos.ErrorResponse                              "No autenticado"
+// @Failure      500  {object}  dtos.ErrorResponse                              "Error interno del servidor"
+// @Security     BearerAuth
+// @Router       /envelopes [get]
+func (h *EnvelopeHandler) GetEnvelopes(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	envelopes, err := h.envelopeService.GetByUser(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve envelopes",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  envelopes,
+		"count": len(envelopes),
+	})
+}
+
+// GetEnvelopeSummary godoc
+// @Summary      Resumen de sobres
+// @Description  Reporta los fondos líquidos (BANK + CASH), lo asignado a sobres y lo que queda sin asignar. Los sobres con saldo negativo se reportan en `overspent` y deben cubrirse con fondos de otros sobres o sin asignar
+// @Tags         Envelopes
+// @Produce      json
+// @Success      200  {object}  object{data=dtos.EnvelopeSummary}  "Resumen de sobres"
+// @Failure      401  {object}  dtos.ErrorResponse                 "No autenticado"
+// @Failure      500  {object}  dtos.ErrorResponse                 "Error interno del servidor"
+// @Security     BearerAuth
+// @Router       /envelopes/summary [get]
+func (h *EnvelopeHandler) GetEnvelopeSummary(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	summary, err := h.envelopeService.GetSummary(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve envelope summary",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": summary,
+	})
+}
+
+// GetEnvelopeByID godoc
+// @Summary      Obtener sobre
+// @Description  Obtiene un sobre con lo asignado, lo gastado y su saldo
+// @Tags         Envelopes
+// @Produce      json
+// @Param        id   path      int                                  true  "ID del sobre"
+// @Success      200  {object}  object{data=dtos.EnvelopeResponse}  "Sobre"
+// @Failure      400  {object}  dtos.ErrorResponse                  "ID inválido"
+// @Failure      401  {object}  dtos.ErrorResponse                  "No autenticado"
+// @Failure      404  {object}  dtos.ErrorResponse                  "Sobre no encontrado"
+// @Security     BearerAuth
+// @Router       /envelopes/{id} [get]
+func (h *EnvelopeHandler) GetEnvelopeByID(c *gin.Context) {
+	id, ok := parseEnvelopeID(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	envelope, err := h.envelopeService.GetByID(userID, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Envelope not found",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": envelope,
+	})
+}
+
+// DeleteEnvelope godoc
+// @Summary      Eliminar sobre
+// @Description  Elimina un sobre; su saldo vuelve a los fondos sin asignar. Las transacciones de la categoría no se modifican
+// @Tags         Envelopes
+// @Produce      json
+// @Param        id   path      int                   true  "ID del sobre"
+// @Success      200  {object}  dtos.SuccessResponse  "Sobre eliminado"
+// @Failure      400  {object}  dtos.ErrorResponse    "ID inválido"
+// @Failure      401  {object}  dtos.ErrorResponse    "No autenticado"
+// @Failure      404  {object}  dtos.ErrorResponse    "Sobre no encontrado"
+// @Security     BearerAuth
+// @Router       /envelopes/{id} [delete]
+func (h *EnvelopeHandler) DeleteEnvelope(c *gin.Context) {
+	id, ok := parseEnvelopeID(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	if err := h.envelopeService.Delete(userID, id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Failed to delete envelope",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Envelope deleted successfully",
+	})
+}
+
+// MoveEnvelopeFunds godoc
+// @Summary      Mover fondos entre sobres
+// @Description  Mueve fondos sin crear transferencias reales: sin `from_envelope_id` asigna desde los fondos sin asignar, sin `to_envelope_id` los devuelve a ellos, y con ambos mueve entre sobres. El origen debe tener al menos el monto
+// @Tags         Envelopes
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dtos.MoveEnvelopeFundsRequest                      true  "Movimiento"
+// @Success      201   {object}  object{message=string,data=dtos.EnvelopeSummary}  "Fondos movidos"
+// @Failure      400   {object}  dtos.ErrorResponse                                "Datos inválidos"
+// @Failure      401   {object}  dtos.ErrorResponse                                "No autenticado"
+// @Failure      409   {object}  dtos.ErrorResponse                                "Fondos insuficientes en el origen"
+// @Security     BearerAuth
+// @Router       /envelopes/moves [post]
+func (h *EnvelopeHandler) MoveEnvelopeFunds(c *gin.Context) {
+	var req dtos.MoveEnvelopeFundsRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	summary, err := h.envelopeService.Move(userID, &req)
+	if err != nil {
+		respondEnvelopeError(c, "Failed to move funds", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Funds moved successfully",
+		"data":    summary,
+	})
+}
+
+// GetEnvelopeMoves godoc
+// @Summary      Historial de movimientos
+// @Description  Lista los movimientos de fondos del usuario, el más reciente primero; con `envelope_id`, solo los que entran o salen de ese sobre
+// @Tags         Envelopes
+// @Produce      json
+// @Param        envelope_id  query     int                                                false  "Filtrar por sobre"
+// @Success      200          {object}  object{data=[]dtos.EnvelopeMoveResponse,count=int}  "Movimientos"
+// @Failure      400          {object}  dtos.ErrorResponse                                 "ID inválido"
+// @Failure      401          {object}  dtos.ErrorResponse                                 "No autenticado"
+// @Failure      404          {object}  dtos.ErrorResponse                                 "Sobre no encontrado"
+// @Security     BearerAuth
+// @Router       /envelopes/moves [get]
+func (h *EnvelopeHandler) GetEnvelopeMoves(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	var envelopeID *uint
+	if envelopeIDStr := c.Query("envelope_id"); envelopeIDStr != "" {
+		parsed, err := strconv.ParseUint(envelopeIDStr, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid envelope ID",
+			})
+			return
+		}
+		id := uint(parsed)
+		envelopeID = &id
+	}
+
+	moves, err := h.envelopeService.GetMoves(userID, envelopeID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Failed to retrieve moves",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  moves,
+		"count": len(moves),
+	})
+}
+
+// respondEnvelopeError maps envelope errors: duplicate envelopes and insufficient funds are conflicts,
+// the rest bad requests
+func respondEnvelopeError(c *gin.Context, message string, err error) {
+	status := http.StatusBadRequest
+	if errors.Is(err, services.ErrEnvelopeExists) || errors.Is(err, services.ErrInsufficientEnvelopeFunds) {
+		status = http.StatusConflict
+	}
+
+	c.JSON(status, gin.H{
+		"error":   message,
+		"details": err.Error(),
+	})
+}
+
+// parseEnvelopeID reads the :id path parameter, responding 400 when it is not a valid ID
+func parseEnvelopeID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid envelope ID",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
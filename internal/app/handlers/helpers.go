@@ -3,6 +3,7 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -36,3 +37,32 @@ func parseIntParam(c *gin.Context, key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+// parseDateQuery parses an optional date query parameter, accepting YYYY-MM-DD or RFC3339.
+// For a plain date, endOfDay selects the last instant of that day (inclusive upper bounds)
+// instead of midnight. Returns nil when the parameter is absent.
+func parseDateQuery(c *gin.Context, key string, endOfDay bool) (*time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	return parseDateValue(value, endOfDay)
+}
+
+// parseDateValue parses a YYYY-MM-DD or RFC3339 date; see parseDateQuery for the endOfDay rule
+func parseDateValue(value string, endOfDay bool) (*time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return &parsed, nil
+	}
+
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, err
+	}
+	if endOfDay {
+		// Microsecond precision matches PostgreSQL timestamps
+		parsed = parsed.AddDate(0, 0, 1).Add(-time.Microsecond)
+	}
+	return &parsed, nil
+}
@@ -149,3 +149,70 @@ func (h *JournalEntryHandler) VerifyTransactionBalance(c *gin.Context) {
 		"data": verification,
 	})
 }
+
+// GetAccountLedger godoc
+// @Summary      Libro mayor de una cuenta
+// @Description  Devuelve los asientos de una cuenta en orden cronológico, cada uno con el saldo acumulado, junto con el saldo inicial al comienzo del rango solicitado y el saldo final. Es la vista de estado de cuenta. Los saldos siguen el lado normal de la cuenta (para pasivos, el monto adeudado)
+// @Tags         Accounts
+// @Produce      json
+// @Param        id          path      int                                        true   "ID de la cuenta"
+// @Param        start_date  query     string                                     false  "Fecha inicial (YYYY-MM-DD o RFC3339)"
+// @Param        end_date    query     string                                     false  "Fecha final inclusiva (YYYY-MM-DD o RFC3339)"
+// @Param        page        query     int                                        false  "Número de página (default: 1)"
+// @Param        page_size   query     int                                        false  "Elementos por página (default: 50, máx: 100)"
+// @Success      200         {object}  object{data=dtos.AccountLedgerResponse}    "Libro mayor de la cuenta"
+// @Failure      400         {object}  dtos.ErrorResponse                         "Parámetros inválidos"
+// @Failure      401         {object}  dtos.ErrorResponse                         "No autenticado"
+// @Failure      404         {object}  dtos.ErrorResponse                         "Cuenta no encontrada"
+// @Security     BearerAuth
+// @Router       /accounts/{id}/ledger [get]
+func (h *JournalEntryHandler) GetAccountLedger(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid account ID",
+		})
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	filters := dtos.JournalEntryFilters{
+		Page:     parseIntParam(c, "page", 1),
+		PageSize: parseIntParam(c, "page_size", 50),
+	}
+
+	if filters.StartDate, err = parseDateQuery(c, "start_date", false); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid start_date",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if filters.EndDate, err = parseDateQuery(c, "end_date", true); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid end_date",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	ledger, err := h.journalEntryService.GetAccountLedger(userID, uint(id), filters)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Failed to retrieve account ledger",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": ledger,
+	})
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"arabella-api/internal/app/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LedgerHandler handles administrative general ledger requests
+type LedgerHandler struct {
+	ledgerService services.LedgerService
+}
+
+// NewLedgerHandler creates a new ledger handler
+func NewLedgerHandler(ledgerService services.LedgerService) *LedgerHandler {
+	return &LedgerHandler{
+		ledgerService: ledgerService,
+	}
+}
+
+// VerifyLedger godoc
+// @Summary      Verificar el libro mayor (admin)
+// @Description  Recalcula el saldo de cada cuenta a partir de los asientos contables y lo compara con el saldo almacenado, reportando las diferencias por usuario y cuenta. También verifica que débitos = créditos en cada transacción. No modifica datos. Requiere super administrador
+// @Tags         Admin
+// @Produce      json
+// @Param        user_id  query     int                                          false  "Limitar la verificación a un usuario"
+// @Success      200      {object}  object{data=dtos.LedgerVerificationReport}   "Resultado de la verificación"
+// @Failure      400      {object}  dtos.ErrorResponse                           "Parámetros inválidos"
+// @Failure      401      {object}  dtos.ErrorResponse                           "No autenticado"
+// @Failure      403      {object}  dtos.ErrorResponse                           "Requiere super administrador"
+// @Failure      500      {object}  dtos.ErrorResponse                           "Error interno del servidor"
+// @Security     BearerAuth
+// @Router       /admin/ledger/verify [get]
+func (h *LedgerHandler) VerifyLedger(c *gin.Context) {
+	h.runVerification(c, false)
+}
+
+// RebuildLedgerBalances godoc
+// @Summary      Reconstruir saldos desde el libro mayor (admin)
+// @Description  Ejecuta la misma verificación y reconstruye, en una sola transacción, el saldo almacenado de cada cuenta con diferencias a partir de sus asientos contables. Las transacciones descuadradas solo se reportan. Requiere super administrador
+// @Tags         Admin
+// @Produce      json
+// @Param        user_id  query     int                                                        false  "Limitar la reconstrucción a un usuario"
+// @Success      200      {object}  object{message=string,data=dtos.LedgerVerificationReport}  "Resultado de la verificación y reconstrucción"
+// @Failure      400      {object}  dtos.ErrorResponse                                         "Parámetros inválidos"
+// @Failure      401      {object}  dtos.ErrorResponse                                         "No autenticado"
+// @Failure      403      {object}  dtos.ErrorResponse                                         "Requiere super administrador"
+// @Failure      500      {object}  dtos.ErrorResponse                                         "Error interno del servidor"
+// @Security     BearerAuth
+// @Router       /admin/ledger/rebuild [post]
+func (h *LedgerHandler) RebuildLedgerBalances(c *gin.Context) {
+	h.runVerification(c, true)
+}
+
+// runVerification parses the optional user filter and runs the ledger verification
+func (h *LedgerHandler) runVerification(c *gin.Context, fix bool) {
+	var userID *uint
+	if userIDStr := c.Query("user_id"); userIDStr != "" {
+		parsed, err := strconv.ParseUint(userIDStr, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid user ID",
+			})
+			return
+		}
+		id := uint(parsed)
+		userID = &id
+	}
+
+	report, err := h.ledgerService.Verify(userID, fix)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to verify ledger",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if !fix {
+		c.JSON(http.StatusOK, gin.H{
+			"data": report,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Ledger balances rebuilt successfully",
+		"data":    report,
+	})
+}
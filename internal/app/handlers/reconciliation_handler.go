@@ -0,0 +1,285 @@
+package handlers
+
+import (
+	"arabella-api/internal/app/dtos"
+	"arabella-api/internal/app/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReconciliationHandler handles bank statement reconciliation HTTP requests
+type ReconciliationHandler struct {
+	reconciliationService services.ReconciliationService
+}
+
+// NewReconciliationHandler creates a new reconciliation handler
+func NewReconciliationHandler(reconciliationService services.ReconciliationService) *ReconciliationHandler {
+	return &ReconciliationHandler{
+		reconciliationService: reconciliationService,
+	}
+}
+
+// StartReconciliation godoc
+// @Summary      Iniciar conciliación
+// @Description  Inicia la conciliación de una cuenta contra un estado de cuenta, indicando la fecha de corte y el saldo final. El saldo inicial es el saldo final de la última conciliación completada. Solo puede haber una conciliación en curso por cuenta
+// @Tags         Reconciliations
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dtos.StartReconciliationRequest                          true  "Cuenta, fecha y saldo final del estado de cuenta"
+// @Success      201   {object}  object{message=string,data=dtos.ReconciliationResponse}  "Conciliación iniciada"
+// @Failure      400   {object}  dtos.ErrorResponse                                       "Datos inválidos o conciliación ya en curso"
+// @Failure      401   {object}  dtos.ErrorResponse                                       "No autenticado"
+// @Security     BearerAuth
+// @Router       /reconciliations [post]
+func (h *ReconciliationHandler) StartReconciliation(c *gin.Context) {
+	var req dtos.StartReconciliationRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	reconciliation, err := h.reconciliationService.Start(userID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to start reconciliation",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Reconciliation started successfully",
+		"data":    reconciliation,
+	})
+}
+
+// GetReconciliations godoc
+// @Summary      Historial de conciliaciones de una cuenta
+// @Description  Obtiene las conciliaciones (en curso y completadas) de una cuenta del usuario autenticado, de la más reciente a la más antigua
+// @Tags         Reconciliations
+// @Produce      json
+// @Param        account_id  query     int                                                     true  "ID de la cuenta"
+// @Success      200         {object}  object{data=[]dtos.ReconciliationResponse,count=int}    "Lista de conciliaciones"
+// @Failure      400         {object}  dtos.ErrorResponse                                      "account_id inválido"
+// @Failure      401         {object}  dtos.ErrorResponse                                      "No autenticado"
+// @Failure      404         {object}  dtos.ErrorResponse                                      "Cuenta no encontrada"
+// @Security     BearerAuth
+// @Router       /reconciliations [get]
+func (h *ReconciliationHandler) GetReconciliations(c *gin.Context) {
+	accountID, err := strconv.ParseUint(c.Query("account_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid or missing account_id",
+		})
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	reconciliations, err := h.reconciliationService.GetByAccount(userID, uint(accountID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Failed to retrieve reconciliations",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  reconciliations,
+		"count": len(reconciliations),
+	})
+}
+
+// GetReconciliationByID godoc
+// @Summary      Obtener conciliación
+// @Description  Obtiene una conciliación con la diferencia actual, las transacciones marcadas como conciliadas y, mientras esté en curso, las transacciones pendientes de marcar hasta la fecha de corte
+// @Tags         Reconciliations
+// @Produce      json
+// @Param        id   path      int                                        true  "ID de la conciliación"
+// @Success      200  {object}  object{data=dtos.ReconciliationResponse}   "Conciliación"
+// @Failure      400  {object}  dtos.ErrorResponse                         "ID inválido"
+// @Failure      401  {object}  dtos.ErrorResponse                         "No autenticado"
+// @Failure      404  {object}  dtos.ErrorResponse                         "Conciliación no encontrada"
+// @Security     BearerAuth
+// @Router       /reconciliations/{id} [get]
+func (h *ReconciliationHandler) GetReconciliationByID(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid reconciliation ID",
+		})
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	reconciliation, err := h.reconciliationService.GetByID(userID, uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Reconciliation not found",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": reconciliation,
+	})
+}
+
+// SetClearedTransactions godoc
+// @Summary      Marcar transacciones conciliadas
+// @Description  Marca (cleared=true) o desmarca (cleared=false) transacciones de una conciliación en curso y devuelve la diferencia actualizada. Solo se pueden marcar transacciones de la cuenta con fecha hasta la fecha de corte que no hayan sido conciliadas antes en esa cuenta
+// @Tags         Reconciliations
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                                                      true  "ID de la conciliación"
+// @Param        body  body      dtos.ClearTransactionsRequest                            true  "Transacciones a marcar o desmarcar"
+// @Success      200   {object}  object{message=string,data=dtos.ReconciliationResponse}  "Conciliación actualizada"
+// @Failure      400   {object}  dtos.ErrorResponse                                       "Datos inválidos"
+// @Failure      401   {object}  dtos.ErrorResponse                                       "No autenticado"
+// @Security     BearerAuth
+// @Router       /reconciliations/{id}/cleared [put]
+func (h *ReconciliationHandler) SetClearedTransactions(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid reconciliation ID",
+		})
+		return
+	}
+
+	var req dtos.ClearTransactionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	reconciliation, err := h.reconciliationService.SetCleared(userID, uint(id), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to update cleared transactions",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Cleared transactions updated successfully",
+		"data":    reconciliation,
+	})
+}
+
+// CompleteReconciliation godoc
+// @Summary      Completar conciliación
+// @Description  Finaliza una conciliación cuando la diferencia es cero. Las transacciones conciliadas quedan bloqueadas: ya no pueden editarse ni revertirse
+// @Tags         Reconciliations
+// @Produce      json
+// @Param        id   path      int                                                      true  "ID de la conciliación"
+// @Success      200  {object}  object{message=string,data=dtos.ReconciliationResponse}  "Conciliación completada"
+// @Failure      400  {object}  dtos.ErrorResponse                                       "La diferencia no es cero o la conciliación ya está completada"
+// @Failure      401  {object}  dtos.ErrorResponse                                       "No autenticado"
+// @Security     BearerAuth
+// @Router       /reconciliations/{id}/complete [post]
+func (h *ReconciliationHandler) CompleteReconciliation(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid reconciliation ID",
+		})
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	reconciliation, err := h.reconciliationService.Complete(userID, uint(id))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to complete reconciliation",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Reconciliation completed successfully",
+		"data":    reconciliation,
+	})
+}
+
+// CancelReconciliation godoc
+// @Summary      Cancelar conciliación
+// @Description  Descarta una conciliación en curso. Las conciliaciones completadas no pueden cancelarse
+// @Tags         Reconciliations
+// @Produce      json
+// @Param        id   path      int                   true  "ID de la conciliación"
+// @Success      200  {object}  dtos.SuccessResponse  "Conciliación cancelada"
+// @Failure      400  {object}  dtos.ErrorResponse    "ID inválido o conciliación completada"
+// @Failure      401  {object}  dtos.ErrorResponse    "No autenticado"
+// @Security     BearerAuth
+// @Router       /reconciliations/{id} [delete]
+func (h *ReconciliationHandler) CancelReconciliation(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid reconciliation ID",
+		})
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	if err := h.reconciliationService.Cancel(userID, uint(id)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to cancel reconciliation",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Reconciliation cancelled successfully",
+	})
+}
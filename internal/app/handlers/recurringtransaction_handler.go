@@ -0,0 +1,376 @@
+package handlers
+
+import (
+	"arabella-api/internal/app/dtos"
+	"arabella-api/internal/app/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RecurringTransactionHandler handles recurring transaction HTTP requests
+type RecurringTransactionHandler struct {
+	recurringService services.RecurringTransactionService
+}
+
+// NewRecurringTransactionHandler creates a new recurring transaction handler
+func NewRecurringTransactionHandler(recurringService services.RecurringTransactionService) *RecurringTransactionHandler {
+	return &RecurringTransactionHandler{
+		recurringService: recurringService,
+	}
+}
+
+// CreateRecurringTransaction godoc
+// @Summary      Crear transacción recurrente
+// @Description  Crea una plantilla que genera una transacción según una regla de repetición: frecuencia (DAILY, WEEKLY, MONTHLY, YEARLY) cada `interval` unidades desde `start_date`, con `day_of_month` opcional (se ajusta al último día en meses cortos) y fin por `end_date` y/o `count`. Ejemplos: sueldo el día 1 = MONTHLY + day_of_month 1; suscripción cada 30 días = DAILY + interval 30. Las ocurrencias vencidas se contabilizan con el programador o con `console recurring run`
+// @Tags         Recurring Transactions
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dtos.CreateRecurringTransactionRequest                          true  "Plantilla y regla de repetición"
+// @Success      201   {object}  object{message=string,data=dtos.RecurringTransactionResponse}  "Transacción recurrente creada"
+// @Failure      400   {object}  dtos.ErrorResponse                                             "Datos inválidos"
+// @Failure      401   {object}  dtos.ErrorResponse                                             "No autenticado"
+// @Security     BearerAuth
+// @Router       /recurring-transactions [post]
+func (h *RecurringTransactionHandler) CreateRecurringTransaction(c *gin.Context) {
+	var req dtos.CreateRecurringTransactionRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	template, err := h.recurringService.Create(userID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to create recurring transaction",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Recurring transaction created successfully",
+		"data":    template,
+	})
+}
+
+// GetRecurringTransactions godoc
+// @Summary      Listar transacciones recurrentes
+// @Description  Obtiene las transacciones recurrentes del usuario autenticado, de la próxima a vencer a la última (las finalizadas al final)
+// @Tags         Recurring Transactions
+// @Produce      json
+// @Success      200  {object}  object{data=[]dtos.RecurringTransactionResponse,count=int}  "Lista de transacciones recurrentes"
+// @Failure      401  {object}  dtos.ErrorResponse                                          "No autenticado"
+// @Failure      500  {object}  dtos.ErrorResponse                                          "Error interno del servidor"
+// @Security     BearerAuth
+// @Router       /recurring-transactions [get]
+func (h *RecurringTransactionHandler) GetRecurringTransactions(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	templates, err := h.recurringService.GetByUser(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve recurring transactions",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  templates,
+		"count": len(templates),
+	})
+}
+
+// GetRecurringTransactionByID godoc
+// @Summary      Obtener transacción recurrente
+// @Description  Obtiene una transacción recurrente con su regla, su próxima ocurrencia y sus ajustes por ocurrencia
+// @Tags         Recurring Transactions
+// @Produce      json
+// @Param        id   path      int                                              true  "ID de la transacción recurrente"
+// @Success      200  {object}  object{data=dtos.RecurringTransactionResponse}   "Transacción recurrente"
+// @Failure      400  {object}  dtos.ErrorResponse                               "ID inválido"
+// @Failure      401  {object}  dtos.ErrorResponse                               "No autenticado"
+// @Failure      404  {object}  dtos.ErrorResponse                               "Transacción recurrente no encontrada"
+// @Security     BearerAuth
+// @Router       /recurring-transactions/{id} [get]
+func (h *RecurringTransactionHandler) GetRecurringTransactionByID(c *gin.Context) {
+	id, ok := parseRecurringTransactionID(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	template, err := h.recurringService.GetByID(userID, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Recurring transaction not found",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": template,
+	})
+}
+
+// UpdateRecurringTransaction godoc
+// @Summary      Actualizar transacción recurrente
+// @Description  Modifica una transacción recurrente. Monto, cuentas, categoría y notas aplican a las ocurrencias aún no contabilizadas; `end_date` vacío y `count` 0 quitan el límite; `is_active` pausa o reanuda (al reanudar se contabilizan las ocurrencias atrasadas). La regla (frecuencia, intervalo, día del mes, fecha de inicio) solo puede cambiar si aún no se contabilizó ninguna ocurrencia
+// @Tags         Recurring Transactions
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                                                             true  "ID de la transacción recurrente"
+// @Param        body  body      dtos.UpdateRecurringTransactionRequest                          true  "Campos a actualizar"
+// @Success      200   {object}  object{message=string,data=dtos.RecurringTransactionResponse}  "Transacción recurrente actualizada"
+// @Failure      400   {object}  dtos.ErrorResponse                                             "ID o datos inválidos"
+// @Failure      401   {object}  dtos.ErrorResponse                                             "No autenticado"
+// @Security     BearerAuth
+// @Router       /recurring-transactions/{id} [put]
+func (h *RecurringTransactionHandler) UpdateRecurringTransaction(c *gin.Context) {
+	id, ok := parseRecurringTransactionID(c)
+	if !ok {
+		return
+	}
+
+	var req dtos.UpdateRecurringTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	template, err := h.recurringService.Update(userID, id, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to update recurring transaction",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Recurring transaction updated successfully",
+		"data":    template,
+	})
+}
+
+// DeleteRecurringTransaction godoc
+// @Summary      Eliminar transacción recurrente
+// @Description  Elimina una transacción recurrente; las transacciones que ya generó se conservan
+// @Tags         Recurring Transactions
+// @Produce      json
+// @Param        id   path      int                   true  "ID de la transacción recurrente"
+// @Success      200  {object}  dtos.SuccessResponse  "Transacción recurrente eliminada"
+// @Failure      400  {object}  dtos.ErrorResponse    "ID inválido"
+// @Failure      401  {object}  dtos.ErrorResponse    "No autenticado"
+// @Failure      404  {object}  dtos.ErrorResponse    "Transacción recurrente no encontrada"
+// @Security     BearerAuth
+// @Router       /recurring-transactions/{id} [delete]
+func (h *RecurringTransactionHandler) DeleteRecurringTransaction(c *gin.Context) {
+	id, ok := parseRecurringTransactionID(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	if err := h.recurringService.Delete(userID, id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Failed to delete recurring transaction",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Recurring transaction deleted successfully",
+	})
+}
+
+// GetRecurringOccurrences godoc
+// @Summary      Ocurrencias de una transacción recurrente
+// @Description  Lista las ocurrencias ya contabilizadas u omitidas (con su transacción) y las próximas programadas con el monto que se contabilizará tras los ajustes
+// @Tags         Recurring Transactions
+// @Produce      json
+// @Param        id        path      int                                              true   "ID de la transacción recurrente"
+// @Param        upcoming  query     int                                              false  "Cantidad de próximas ocurrencias (por defecto 12, máximo 100)"
+// @Success      200       {object}  object{data=dtos.RecurringOccurrencesResponse}   "Ocurrencias"
+// @Failure      400       {object}  dtos.ErrorResponse                               "ID inválido"
+// @Failure      401       {object}  dtos.ErrorResponse                               "No autenticado"
+// @Failure      404       {object}  dtos.ErrorResponse                               "Transacción recurrente no encontrada"
+// @Security     BearerAuth
+// @Router       /recurring-transactions/{id}/occurrences [get]
+func (h *RecurringTransactionHandler) GetRecurringOccurrences(c *gin.Context) {
+	id, ok := parseRecurringTransactionID(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	upcoming := parseIntParam(c, "upcoming", 12)
+	if upcoming < 0 {
+		upcoming = 0
+	}
+	if upcoming > 100 {
+		upcoming = 100
+	}
+
+	occurrences, err := h.recurringService.GetOccurrences(userID, id, upcoming)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Failed to retrieve occurrences",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": occurrences,
+	})
+}
+
+// SetRecurringOverride godoc
+// @Summary      Ajustar una ocurrencia
+// @Description  Cambia el monto de una próxima ocurrencia (p. ej. una factura más alta) u omite esa ocurrencia con `skip`. Reemplaza el ajuste existente para ese día
+// @Tags         Recurring Transactions
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                                                             true  "ID de la transacción recurrente"
+// @Param        body  body      dtos.RecurringOverrideRequest                                   true  "Fecha de la ocurrencia y nuevo monto u omisión"
+// @Success      200   {object}  object{message=string,data=dtos.RecurringTransactionResponse}  "Ajuste guardado"
+// @Failure      400   {object}  dtos.ErrorResponse                                             "ID o datos inválidos, o no hay ocurrencia ese día"
+// @Failure      401   {object}  dtos.ErrorResponse                                             "No autenticado"
+// @Security     BearerAuth
+// @Router       /recurring-transactions/{id}/overrides [put]
+func (h *RecurringTransactionHandler) SetRecurringOverride(c *gin.Context) {
+	id, ok := parseRecurringTransactionID(c)
+	if !ok {
+		return
+	}
+
+	var req dtos.RecurringOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	template, err := h.recurringService.SetOverride(userID, id, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to save override",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Override saved successfully",
+		"data":    template,
+	})
+}
+
+// DeleteRecurringOverride godoc
+// @Summary      Quitar ajuste de una ocurrencia
+// @Description  Elimina el ajuste de una ocurrencia; se vuelve a contabilizar con el monto de la plantilla
+// @Tags         Recurring Transactions
+// @Produce      json
+// @Param        id           path      int                   true  "ID de la transacción recurrente"
+// @Param        overrideId   path      int                   true  "ID del ajuste"
+// @Success      200          {object}  dtos.SuccessResponse  "Ajuste eliminado"
+// @Failure      400          {object}  dtos.ErrorResponse    "ID inválido"
+// @Failure      401          {object}  dtos.ErrorResponse    "No autenticado"
+// @Failure      404          {object}  dtos.ErrorResponse    "Ajuste no encontrado"
+// @Security     BearerAuth
+// @Router       /recurring-transactions/{id}/overrides/{overrideId} [delete]
+func (h *RecurringTransactionHandler) DeleteRecurringOverride(c *gin.Context) {
+	id, ok := parseRecurringTransactionID(c)
+	if !ok {
+		return
+	}
+
+	overrideID, err := strconv.ParseUint(c.Param("overrideId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid override ID",
+		})
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	if err := h.recurringService.DeleteOverride(userID, id, uint(overrideID)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Failed to delete override",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Override deleted successfully",
+	})
+}
+
+// parseRecurringTransactionID parses the :id path parameter, responding 400 when it is invalid
+func parseRecurringTransactionID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid recurring transaction ID",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
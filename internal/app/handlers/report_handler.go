@@ -0,0 +1,288 @@
+package handlers
+
+import (
+	"arabella-api/internal/app/services"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxCompareToDates caps the comparative columns of a balance sheet, each of which is a full ledger scan
+const maxCompareToDates = 12
+
+// ReportHandler handles financial report HTTP requests
+type ReportHandler struct {
+	reportService services.ReportService
+}
+
+// NewReportHandler creates a new report handler
+func NewReportHandler(reportService services.ReportService) *ReportHandler {
+	return &ReportHandler{
+		reportService: reportService,
+	}
+}
+
+// GetTrialBalance godoc
+// @Summary      Balance de comprobación
+// @Description  Lista cada cuenta del libro mayor con el total de débitos, el total de créditos y el saldo neto a la fecha indicada, agrupadas por clasificación (ASSET, LIABILITY, EQUITY, INCOME, EXPENSE). Calculado desde los asientos contables; indica si los totales generales cuadran
+// @Tags         Reports
+// @Produce      json
+// @Param        as_of  query     string                                  false  "Fecha de corte (YYYY-MM-DD o RFC3339, inclusiva). Por defecto: ahora"
+// @Success      200    {object}  object{data=dtos.TrialBalanceReport}    "Balance de comprobación"
+// @Failure      400    {object}  dtos.ErrorResponse                      "Fecha inválida"
+// @Failure      401    {object}  dtos.ErrorResponse                      "No autenticado"
+// @Failure      500    {object}  dtos.ErrorResponse                      "Error interno del servidor"
+// @Security     BearerAuth
+// @Router       /reports/trial-balance [get]
+func (h *ReportHandler) GetTrialBalance(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	asOf, err := parseDateQuery(c, "as_of", true)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid as_of date",
+			"details": err.Error(),
+		})
+		return
+	}
+	if asOf == nil {
+		now := time.Now()
+		asOf = &now
+	}
+
+	report, err := h.reportService.GetTrialBalance(userID, *asOf)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to build trial balance",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": report,
+	})
+}
+
+// GetIncomeStatement godoc
+// @Summary      Estado de resultados (P&L)
+// @Description  Muestra ingresos y gastos por categoría para cualquier rango de fechas, desglosados por subperiodo (mes, trimestre o año), con subtotales y resultado neto. Calculado desde los asientos contables, por lo que las reversiones se compensan automáticamente
+// @Tags         Reports
+// @Produce      json
+// @Param        start_date  query     string                                    false  "Fecha inicial (YYYY-MM-DD o RFC3339). Por defecto: 1 de enero del año actual"
+// @Param        end_date    query     string                                    false  "Fecha final inclusiva (YYYY-MM-DD o RFC3339). Por defecto: ahora"
+// @Param        period      query     string                                    false  "Subperiodo: month, quarter, year (default: month)"
+// @Success      200         {object}  object{data=dtos.IncomeStatementReport}   "Estado de resultados"
+// @Failure      400         {object}  dtos.ErrorResponse                        "Parámetros inválidos"
+// @Failure      401         {object}  dtos.ErrorResponse                        "No autenticado"
+// @Failure      500         {object}  dtos.ErrorResponse                        "Error interno del servidor"
+// @Security     BearerAuth
+// @Router       /reports/income-statement [get]
+func (h *ReportHandler) GetIncomeStatement(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	now := time.Now()
+	startDate, err := parseDateQuery(c, "start_date", false)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid start_date",
+			"details": err.Error(),
+		})
+		return
+	}
+	if startDate == nil {
+		start := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+		startDate = &start
+	}
+
+	endDate, err := parseDateQuery(c, "end_date", true)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid end_date",
+			"details": err.Error(),
+		})
+		return
+	}
+	if endDate == nil {
+		endDate = &now
+	}
+
+	period := c.DefaultQuery("period", "month")
+	if period != "month" && period != "quarter" && period != "year" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid period (use month, quarter or year)",
+		})
+		return
+	}
+
+	report, err := h.reportService.GetIncomeStatement(userID, *startDate, *endDate, period)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to build income statement",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": report,
+	})
+}
+
+// GetBalanceSheet godoc
+// @Summary      Balance general
+// @Description  Muestra activos, pasivos y patrimonio por cuenta a cualquier fecha pasada, calculado desde los asientos contables (no desde el saldo en caché). Admite columnas comparativas: compare=previous_month (cierre del mes anterior), compare=previous_year (misma fecha del año anterior) o compare_to con hasta 12 fechas separadas por comas
+// @Tags         Reports
+// @Produce      json
+// @Param        as_of       query     string                                 false  "Fecha de corte (YYYY-MM-DD o RFC3339, inclusiva). Por defecto: ahora"
+// @Param        compare     query     string                                 false  "Columna comparativa: previous_month, previous_year"
+// @Param        compare_to  query     string                                 false  "Fechas comparativas adicionales separadas por comas, máximo 12 (YYYY-MM-DD o RFC3339)"
+// @Success      200         {object}  object{data=dtos.BalanceSheetReport}   "Balance general"
+// @Failure      400         {object}  dtos.ErrorResponse                     "Parámetros inválidos"
+// @Failure      401         {object}  dtos.ErrorResponse                     "No autenticado"
+// @Failure      500         {object}  dtos.ErrorResponse                     "Error interno del servidor"
+// @Security     BearerAuth
+// @Router       /reports/balance-sheet [get]
+func (h *ReportHandler) GetBalanceSheet(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	asOf, err := parseDateQuery(c, "as_of", true)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid as_of date",
+			"details": err.Error(),
+		})
+		return
+	}
+	if asOf == nil {
+		now := time.Now()
+		asOf = &now
+	}
+
+	dates := []time.Time{*asOf}
+
+	switch c.Query("compare") {
+	case "":
+	case "previous_month":
+		// Last instant of the month before as_of
+		monthStart := time.Date(asOf.Year(), asOf.Month(), 1, 0, 0, 0, 0, asOf.Location())
+		dates = append(dates, monthStart.Add(-time.Microsecond))
+	case "previous_year":
+		dates = append(dates, asOf.AddDate(-1, 0, 0))
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid compare (use previous_month or previous_year)",
+		})
+		return
+	}
+
+	if compareTo := c.Query("compare_to"); compareTo != "" {
+		values := strings.Split(compareTo, ",")
+		if len(values) > maxCompareToDates {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("compare_to accepts at most %d dates", maxCompareToDates),
+			})
+			return
+		}
+
+		for _, value := range values {
+			date, err := parseDateValue(strings.TrimSpace(value), true)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   "Invalid compare_to date",
+					"details": err.Error(),
+				})
+				return
+			}
+			dates = append(dates, *date)
+		}
+	}
+
+	report, err := h.reportService.GetBalanceSheet(userID, dates)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to build balance sheet",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": report,
+	})
+}
+
+// GetCashFlow godoc
+// @Summary      Estado de flujo de efectivo
+// @Description  Clasifica los movimientos de las cuentas líquidas (BANK, CASH) del periodo en operativos (ingresos/gastos), transferencias entre cuentas propias, pagos de deuda y ajustes. Muestra el efectivo inicial, cada flujo y el efectivo final, y verifica que cuadre con el balance general a la fecha final
+// @Tags         Reports
+// @Produce      json
+// @Param        start_date  query     string                             false  "Fecha inicial (YYYY-MM-DD o RFC3339). Por defecto: inicio del mes actual"
+// @Param        end_date    query     string                             false  "Fecha final inclusiva (YYYY-MM-DD o RFC3339). Por defecto: ahora"
+// @Success      200         {object}  object{data=dtos.CashFlowReport}   "Flujo de efectivo"
+// @Failure      400         {object}  dtos.ErrorResponse                 "Parámetros inválidos"
+// @Failure      401         {object}  dtos.ErrorResponse                 "No autenticado"
+// @Failure      500         {object}  dtos.ErrorResponse                 "Error interno del servidor"
+// @Security     BearerAuth
+// @Router       /reports/cash-flow [get]
+func (h *ReportHandler) GetCashFlow(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	now := time.Now()
+	startDate, err := parseDateQuery(c, "start_date", false)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid start_date",
+			"details": err.Error(),
+		})
+		return
+	}
+	if startDate == nil {
+		start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		startDate = &start
+	}
+
+	endDate, err := parseDateQuery(c, "end_date", true)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid end_date",
+			"details": err.Error(),
+		})
+		return
+	}
+	if endDate == nil {
+		endDate = &now
+	}
+
+	report, err := h.reportService.GetCashFlow(userID, *startDate, *endDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to build cash flow statement",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": report,
+	})
+}
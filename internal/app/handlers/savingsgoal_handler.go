@@ -0,0 +1,417 @@
+package handlers
+
+import (
+	"arabella-api/internal/app/dtos"
+	"arabella-api/internal/app/services"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SavingsGoalHandler handles savings goal HTTP requests
+type SavingsGoalHandler struct {
+	savingsGoalService services.SavingsGoalService
+}
+
+// NewSavingsGoalHandler creates a new savings goal handler
+func NewSavingsGoalHandler(savingsGoalService services.SavingsGoalService) *SavingsGoalHandler {
+	return &SavingsGoalHandler{
+		savingsGoalService: savingsGoalService,
+	}
+}
+
+// CreateSavingsGoal godoc
+// @Summary      Crear meta de ahorro
+// @Description  Crea una meta de ahorro (fondo de emergencia, vacaciones, auto...) con un monto objetivo y, opcionalmente, una fecha objetivo. `account_ids` son cuentas SAVINGS cuyo saldo completo cuenta para la meta; porciones de otras cuentas se reservan luego con aportes
+// @Tags         Savings Goals
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dtos.CreateSavingsGoalRequest                          true  "Meta de ahorro"
+// @Success      201   {object}  object{message=string,data=dtos.SavingsGoalResponse}  "Meta creada"
+// @Failure      400   {object}  dtos.ErrorResponse                                    "Datos inválidos"
+// @Failure      401   {object}  dtos.ErrorResponse                                    "No autenticado"
+// @Failure      409   {object}  dtos.ErrorResponse                                    "La cuenta ya está vinculada a una meta"
+// @Security     BearerAuth
+// @Router       /savings-goals [post]
+func (h *SavingsGoalHandler) CreateSavingsGoal(c *gin.Context) {
+	var req dtos.CreateSavingsGoalRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	goal, err := h.savingsGoalService.Create(userID, &req)
+	if err != nil {
+		respondSavingsGoalError(c, "Failed to create savings goal", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Savings goal created successfully",
+		"data":    goal,
+	})
+}
+
+// GetSavingsGoals godoc
+// @Summary      Listar metas de ahorro
+// @Description  Obtiene las metas de ahorro del usuario con su progreso: ahorrado (saldo de las cuentas vinculadas más lo reservado), restante, porcentaje, aporte mensual necesario para llegar a la fecha objetivo, aporte mensual promedio de los últimos 6 meses y fecha de cumplimiento proyectada a ese ritmo. Estado: ACHIEVED, ON_TRACK, AT_RISK o NO_PROGRESS
+// @Tags         Savings Goals
+// @Produce      json
+// @Success      200  {object}  object{data=[]dtos.SavingsGoalResponse,count=int}  "Lista de metas"
+// @Failure      401  {object}  dtos.ErrorResponse                                 "No autenticado"
+// @Failure      500  {object}  dtos.ErrorResponse                                 "Error interno del servidor"
+// @Security     BearerAuth
+// @Router       /savings-goals [get]
+func (h *SavingsGoalHandler) GetSavingsGoals(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	goals, err := h.savingsGoalService.GetByUser(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve savings goals",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  goals,
+		"count": len(goals),
+	})
+}
+
+// GetSavingsGoalSummary godoc
+// @Summary      Resumen de metas de ahorro
+// @Description  Suma el objetivo y lo ahorrado de todas las metas, y cuántas están ACHIEVED, ON_TRACK, AT_RISK o NO_PROGRESS
+// @Tags         Savings Goals
+// @Produce      json
+// @Success      200  {object}  object{data=dtos.SavingsGoalSummary}  "Resumen de metas"
+// @Failure      401  {object}  dtos.ErrorResponse                    "No autenticado"
+// @Failure      500  {object}  dtos.ErrorResponse                    "Error interno del servidor"
+// @Security     BearerAuth
+// @Router       /savings-goals/summary [get]
+func (h *SavingsGoalHandler) GetSavingsGoalSummary(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	summary, err := h.savingsGoalService.GetSummary(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve savings goal summary",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": summary,
+	})
+}
+
+// GetSavingsGoalByID godoc
+// @Summary      Obtener meta de ahorro
+// @Description  Obtiene una meta de ahorro con su progreso y el historial de aportes
+// @Tags         Savings Goals
+// @Produce      json
+// @Param        id   path      int                                     true  "ID de la meta"
+// @Success      200  {object}  object{data=dtos.SavingsGoalResponse}  "Meta de ahorro"
+// @Failure      400  {object}  dtos.ErrorResponse                     "ID inválido"
+// @Failure      401  {object}  dtos.ErrorResponse                     "No autenticado"
+// @Failure      404  {object}  dtos.ErrorResponse                     "Meta no encontrada"
+// @Security     BearerAuth
+// @Router       /savings-goals/{id} [get]
+func (h *SavingsGoalHandler) GetSavingsGoalByID(c *gin.Context) {
+	id, ok := parseSavingsGoalID(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	goal, err := h.savingsGoalService.GetByID(userID, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Savings goal not found",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": goal,
+	})
+}
+
+// UpdateSavingsGoal godoc
+// @Summary      Actualizar meta de ahorro
+// @Description  Modifica el nombre, el monto objetivo, la fecha objetivo (`target_date` vacío la quita) o las notas de una meta
+// @Tags         Savings Goals
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                                                    true  "ID de la meta"
+// @Param        body  body      dtos.UpdateSavingsGoalRequest                          true  "Campos a actualizar"
+// @Success      200   {object}  object{message=string,data=dtos.SavingsGoalResponse}  "Meta actualizada"
+// @Failure      400   {object}  dtos.ErrorResponse                                    "ID o datos inválidos"
+// @Failure      401   {object}  dtos.ErrorResponse                                    "No autenticado"
+// @Security     BearerAuth
+// @Router       /savings-goals/{id} [put]
+func (h *SavingsGoalHandler) UpdateSavingsGoal(c *gin.Context) {
+	id, ok := parseSavingsGoalID(c)
+	if !ok {
+		return
+	}
+
+	var req dtos.UpdateSavingsGoalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	goal, err := h.savingsGoalService.Update(userID, id, &req)
+	if err != nil {
+		respondSavingsGoalError(c, "Failed to update savings goal", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Savings goal updated successfully",
+		"data":    goal,
+	})
+}
+
+// DeleteSavingsGoal godoc
+// @Summary      Eliminar meta de ahorro
+// @Description  Elimina una meta de ahorro, desvinculando sus cuentas y liberando lo reservado; los saldos de las cuentas no se modifican
+// @Tags         Savings Goals
+// @Produce      json
+// @Param        id   path      int                   true  "ID de la meta"
+// @Success      200  {object}  dtos.SuccessResponse  "Meta eliminada"
+// @Failure      400  {object}  dtos.ErrorResponse    "ID inválido"
+// @Failure      401  {object}  dtos.ErrorResponse    "No autenticado"
+// @Failure      404  {object}  dtos.ErrorResponse    "Meta no encontrada"
+// @Security     BearerAuth
+// @Router       /savings-goals/{id} [delete]
+func (h *SavingsGoalHandler) DeleteSavingsGoal(c *gin.Context) {
+	id, ok := parseSavingsGoalID(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	if err := h.savingsGoalService.Delete(userID, id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Failed to delete savings goal",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Savings goal deleted successfully",
+	})
+}
+
+// LinkSavingsGoalAccount godoc
+// @Summary      Vincular cuenta a una meta
+// @Description  Vincula una cuenta SAVINGS a la meta: todo su saldo cuenta para ella. Una cuenta solo puede estar vinculada a una meta y no puede tener reservas
+// @Tags         Savings Goals
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                                                    true  "ID de la meta"
+// @Param        body  body      dtos.LinkSavingsGoalAccountRequest                     true  "Cuenta"
+// @Success      200   {object}  object{message=string,data=dtos.SavingsGoalResponse}  "Cuenta vinculada"
+// @Failure      400   {object}  dtos.ErrorResponse                                    "ID o datos inválidos"
+// @Failure      401   {object}  dtos.ErrorResponse                                    "No autenticado"
+// @Failure      409   {object}  dtos.ErrorResponse                                    "La cuenta ya está vinculada a una meta"
+// @Security     BearerAuth
+// @Router       /savings-goals/{id}/accounts [post]
+func (h *SavingsGoalHandler) LinkSavingsGoalAccount(c *gin.Context) {
+	id, ok := parseSavingsGoalID(c)
+	if !ok {
+		return
+	}
+
+	var req dtos.LinkSavingsGoalAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	goal, err := h.savingsGoalService.LinkAccount(userID, id, req.AccountID)
+	if err != nil {
+		respondSavingsGoalError(c, "Failed to link account", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Account linked successfully",
+		"data":    goal,
+	})
+}
+
+// UnlinkSavingsGoalAccount godoc
+// @Summary      Desvincular cuenta de una meta
+// @Description  Quita una cuenta vinculada de la meta; su saldo deja de contar para ella
+// @Tags         Savings Goals
+// @Produce      json
+// @Param        id         path      int                                                    true  "ID de la meta"
+// @Param        accountId  path      int                                                    true  "ID de la cuenta"
+// @Success      200        {object}  object{message=string,data=dtos.SavingsGoalResponse}  "Cuenta desvinculada"
+// @Failure      400        {object}  dtos.ErrorResponse                                    "ID inválido"
+// @Failure      401        {object}  dtos.ErrorResponse                                    "No autenticado"
+// @Failure      404        {object}  dtos.ErrorResponse                                    "Meta o vínculo no encontrado"
+// @Security     BearerAuth
+// @Router       /savings-goals/{id}/accounts/{accountId} [delete]
+func (h *SavingsGoalHandler) UnlinkSavingsGoalAccount(c *gin.Context) {
+	id, ok := parseSavingsGoalID(c)
+	if !ok {
+		return
+	}
+
+	accountID, err := strconv.ParseUint(c.Param("accountId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid account ID",
+		})
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	goal, err := h.savingsGoalService.UnlinkAccount(userID, id, uint(accountID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Failed to unlink account",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Account unlinked successfully",
+		"data":    goal,
+	})
+}
+
+// CreateSavingsGoalContribution godoc
+// @Summary      Reservar saldo para una meta
+// @Description  Reserva parte del saldo de una cuenta de activo para la meta, sin mover dinero (un monto negativo libera una reserva anterior). Las reservas de todas las metas sobre una cuenta no pueden superar su saldo
+// @Tags         Savings Goals
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                                                    true  "ID de la meta"
+// @Param        body  body      dtos.CreateSavingsGoalContributionRequest              true  "Aporte"
+// @Success      201   {object}  object{message=string,data=dtos.SavingsGoalResponse}  "Aporte registrado"
+// @Failure      400   {object}  dtos.ErrorResponse                                    "ID o datos inválidos"
+// @Failure      401   {object}  dtos.ErrorResponse                                    "No autenticado"
+// @Failure      409   {object}  dtos.ErrorResponse                                    "Saldo insuficiente o cuenta vinculada a una meta"
+// @Security     BearerAuth
+// @Router       /savings-goals/{id}/contributions [post]
+func (h *SavingsGoalHandler) CreateSavingsGoalContribution(c *gin.Context) {
+	id, ok := parseSavingsGoalID(c)
+	if !ok {
+		return
+	}
+
+	var req dtos.CreateSavingsGoalContributionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	goal, err := h.savingsGoalService.AddContribution(userID, id, &req)
+	if err != nil {
+		respondSavingsGoalError(c, "Failed to add contribution", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Contribution added successfully",
+		"data":    goal,
+	})
+}
+
+// respondSavingsGoalError maps savings goal errors: linked accounts and earmarks beyond the balance
+// are conflicts, the rest bad requests
+func respondSavingsGoalError(c *gin.Context, message string, err error) {
+	status := http.StatusBadRequest
+	if errors.Is(err, services.ErrSavingsGoalAccountLinked) || errors.Is(err, services.ErrEarmarkExceedsBalance) {
+		status = http.StatusConflict
+	}
+
+	c.JSON(status, gin.H{
+		"error":   message,
+		"details": err.Error(),
+	})
+}
+
+// parseSavingsGoalID reads the :id path parameter, responding 400 when it is not a valid ID
+func parseSavingsGoalID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid savings goal ID",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -3,6 +3,8 @@ package handlers
 import (
 	"arabella-api/internal/app/dtos"
 	"arabella-api/internal/app/services"
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
 
@@ -26,9 +28,10 @@ func NewTransactionHandler(transactionService services.TransactionService) *Tran
 // @Description  Obtiene una lista paginada de transacciones del usuario autenticado con filtros opcionales. Cada transacción pasa por el Motor Contable de doble partida
 // @Tags         Transactions
 // @Produce      json
-// @Param        type         query     string  false  "Tipo de transacción (INCOME, EXPENSE, TRANSFER)"
+// @Param        type         query     string  false  "Tipo de transacción (INCOME, EXPENSE, TRANSFER, DEBT_PAYMENT, OPENING_BALANCE, ADJUSTMENT)"
 // @Param        account_id   query     int     false  "Filtrar por ID de cuenta"
 // @Param        category_id  query     int     false  "Filtrar por ID de categoría"
+// @Param        status       query     string  false  "Estado (PENDING, POSTED, REVERSED, VOIDED). Por defecto solo las vigentes (POSTED)"
 // @Param        page         query     int     false  "Número de página (default: 1)"
 // @Param        page_size    query     int     false  "Elementos por página (default: 20, máx: 100)"
 // @Success      200  {object}  dtos.TransactionListResponse  "Lista de transacciones paginada"
@@ -46,6 +49,7 @@ func (h *TransactionHandler) GetTransactions(c *gin.Context) {
 	// Parse filters from query params
 	filters := dtos.TransactionFilters{
 		Type:     c.Query("type"),
+		Status:   c.Query("status"),
 		Page:     parseIntParam(c, "page", 1),
 		PageSize: parseIntParam(c, "page_size", 20),
 	}
@@ -112,14 +116,16 @@ func (h *TransactionHandler) GetTransactionByID(c *gin.Context) {
 
 // CreateTransaction godoc
 // @Summary      Crear transacción
-// @Description  Crea una nueva transacción procesándola a través del Motor Contable de doble partida. Genera automáticamente los asientos de débito y crédito y actualiza los saldos de las cuentas involucradas. Tipos: INCOME (requiere category_id), EXPENSE (requiere category_id), TRANSFER (requiere account_to_id)
+// @Description  Crea una nueva transacción procesándola a través del Motor Contable de doble partida. Genera automáticamente los asientos de débito y crédito y actualiza los saldos de las cuentas involucradas. Tipos: INCOME (requiere category_id), EXPENSE (requiere category_id), TRANSFER (requiere account_to_id), DEBT_PAYMENT (account_from_id BANK/CASH y account_to_id de la deuda: CREDIT_CARD, LOAN o MORTGAGE; reduce el pasivo y no cuenta como gasto; en LOAN/MORTGAGE el primer pago del mes se divide en interés, registrado como gasto en la categoría de intereses del préstamo, y capital; el interés que el pago no cubre se suma a la deuda). INCOME/EXPENSE pueden enviarse divididas con `splits` (N partidas, cada una con category_id o account_id y su monto) en lugar de category_id; la suma de las partidas debe ser igual al monto total. Las transacciones con fecha futura o con pending=true quedan PENDING: no generan asientos ni cambian saldos hasta su fecha (se contabilizan automáticamente) o hasta confirmarlas (POST /transactions/{id}/confirm)
 // @Tags         Transactions
 // @Accept       json
 // @Produce      json
 // @Param        body  body      dtos.CreateTransactionRequest                          true  "Datos de la transacción"
+// @Param        Idempotency-Key  header  string  false  "Clave de idempotencia: repetir la petición con la misma clave devuelve la respuesta original sin volver a crear la transacción"
 // @Success      201   {object}  object{message=string,data=dtos.TransactionResponse}  "Transacción creada y contabilizada exitosamente"
 // @Failure      400   {object}  dtos.ErrorResponse                                    "Datos inválidos o regla de negocio violada"
 // @Failure      401   {object}  dtos.ErrorResponse                                    "No autenticado"
+// @Failure      409   {object}  dtos.ErrorResponse                                    "Idempotency-Key reutilizada con otra petición o aún en proceso"
 // @Failure      500   {object}  dtos.ErrorResponse                                    "Error interno del servidor"
 // @Security     BearerAuth
 // @Router       /transactions [post]
@@ -156,21 +162,89 @@ func (h *TransactionHandler) CreateTransaction(c *gin.Context) {
 	})
 }
 
+// CreateTransactionBatch godoc
+// @Summary      Crear transacciones en lote
+// @Description  Crea hasta 1000 transacciones en una sola operación de base de datos, procesándolas a través del Motor Contable. Devuelve el resultado de cada elemento (CREATED, FAILED o ROLLED_BACK) con su error. Modo `all_or_nothing` (por defecto): si algún elemento falla no se crea ninguna transacción. Modo `best_effort`: se crean todas las válidas
+// @Tags         Transactions
+// @Accept       json
+// @Produce      json
+// @Param        Idempotency-Key  header  string                              false  "Clave de idempotencia: repetir la petición con la misma clave devuelve la respuesta original sin volver a crear el lote"
+// @Param        body  body      dtos.BatchCreateTransactionRequest                               true  "Transacciones y modo del lote"
+// @Success      201   {object}  object{message=string,data=dtos.BatchCreateTransactionResponse}  "Todas las transacciones fueron creadas"
+// @Success      207   {object}  object{message=string,data=dtos.BatchCreateTransactionResponse}  "Modo best_effort: algunas transacciones fallaron"
+// @Failure      400   {object}  object{error=string,data=dtos.BatchCreateTransactionResponse}    "Datos inválidos o lote all_or_nothing rechazado"
+// @Failure      401   {object}  dtos.ErrorResponse                                             "No autenticado"
+// @Failure      409   {object}  dtos.ErrorResponse                                             "Idempotency-Key reutilizada con otra petición o aún en proceso"
+// @Failure      500   {object}  dtos.ErrorResponse                                             "Error interno del servidor"
+// @Security     BearerAuth
+// @Router       /transactions/batch [post]
+func (h *TransactionHandler) CreateTransactionBatch(c *gin.Context) {
+	var req dtos.BatchCreateTransactionRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	result, err := h.transactionService.CreateBatch(&req, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to create transactions",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	switch {
+	case !result.Committed:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Batch rejected: no transaction was created",
+			"data":  result,
+		})
+	case result.Failed > 0:
+		c.JSON(http.StatusMultiStatus, gin.H{
+			"message": "Some transactions could not be created",
+			"data":    result,
+		})
+	default:
+		c.JSON(http.StatusCreated, gin.H{
+			"message": "Transactions created successfully",
+			"data":    result,
+		})
+	}
+}
+
 // UpdateTransaction godoc
 // @Summary      Actualizar transacción
-// @Description  Actualiza los campos editables de una transacción existente (descripción, notas, fecha, estado de conciliación). El monto y las cuentas no pueden modificarse directamente; para ello, elimina la transacción y crea una nueva
+// @Description  Actualiza una transacción existente. Descripción y notas se modifican directamente; el estado de conciliación (is_reconciled) no puede modificarse aquí y se rechaza con 400: las transacciones se marcan como conciliadas desde una conciliación. Cambiar monto, fecha, cuentas o categoría revierte la transacción original y contabiliza una versión corregida en la misma operación; ambas quedan enlazadas (replaces_transaction_id / replaced_by_transaction_id) y la respuesta devuelve la nueva versión
 // @Tags         Transactions
 // @Accept       json
 // @Produce      json
 // @Param        id    path      int                          true  "ID de la transacción"
 // @Param        body  body      dtos.UpdateTransactionRequest  true  "Campos a actualizar"
-// @Success      200   {object}  dtos.SuccessResponse         "Transacción actualizada exitosamente"
+// @Success      200   {object}  object{message=string,data=dtos.TransactionResponse}  "Transacción actualizada exitosamente"
 // @Failure      400   {object}  dtos.ErrorResponse           "ID o datos inválidos"
 // @Failure      401   {object}  dtos.ErrorResponse           "No autenticado"
+// @Failure      409   {object}  dtos.ErrorResponse           "La transacción fue revertida, reemplazada o conciliada durante la edición"
 // @Failure      500   {object}  dtos.ErrorResponse           "Error interno del servidor"
 // @Security     BearerAuth
 // @Router       /transactions/{id} [put]
 func (h *TransactionHandler) UpdateTransaction(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
 	idParam := c.Param("id")
 	id, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
@@ -189,7 +263,22 @@ func (h *TransactionHandler) UpdateTransaction(c *gin.Context) {
 		return
 	}
 
-	if err := h.transactionService.Update(uint(id), &req); err != nil {
+	transaction, err := h.transactionService.Update(userID, uint(id), &req)
+	if err != nil {
+		if errors.Is(err, services.ErrManualReconciliation) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Reconciliation status cannot be updated directly",
+				"details": err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, services.ErrTransactionNotEditable) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Transaction changed while it was being edited",
+				"details": err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to update transaction",
 			"details": err.Error(),
@@ -199,6 +288,49 @@ func (h *TransactionHandler) UpdateTransaction(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Transaction updated successfully",
+		"data":    dtos.FromModelToTransactionResponse(transaction),
+	})
+}
+
+// GetTransactionHistory godoc
+// @Summary      Historial de ediciones de una transacción
+// @Description  Devuelve todas las versiones de una transacción editada, de la original a la vigente. Cada edición de monto, fecha, cuentas o categoría revierte la versión anterior y crea una nueva enlazada
+// @Tags         Transactions
+// @Produce      json
+// @Param        id   path      int                                         true  "ID de cualquier versión de la transacción"
+// @Success      200  {object}  object{data=[]dtos.TransactionResponse}     "Versiones de la transacción (la más antigua primero)"
+// @Failure      400  {object}  dtos.ErrorResponse                          "ID inválido"
+// @Failure      401  {object}  dtos.ErrorResponse                          "No autenticado"
+// @Failure      404  {object}  dtos.ErrorResponse                          "Transacción no encontrada"
+// @Security     BearerAuth
+// @Router       /transactions/{id}/history [get]
+func (h *TransactionHandler) GetTransactionHistory(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid transaction ID",
+		})
+		return
+	}
+
+	history, err := h.transactionService.GetHistory(userID, uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Transaction not found",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": history,
 	})
 }
 
@@ -237,3 +369,65 @@ func (h *TransactionHandler) DeleteTransaction(c *gin.Context) {
 		"message": "Transaction deleted successfully (reversed)",
 	})
 }
+
+// ConfirmTransaction godoc
+// @Summary      Confirmar transacción pendiente
+// @Description  Contabiliza una transacción pendiente (futura o creada con pending=true): genera sus asientos y actualiza los saldos. Opcionalmente la re-fecha antes de contabilizarla (por ejemplo, un pago futuro adelantado a hoy)
+// @Tags         Transactions
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                             true   "ID de la transacción pendiente"
+// @Param        body  body      dtos.ConfirmTransactionRequest  false  "Nueva fecha de la transacción (opcional)"
+// @Success      200   {object}  object{message=string,data=dtos.TransactionResponse}  "Transacción contabilizada exitosamente"
+// @Failure      400   {object}  dtos.ErrorResponse              "ID o datos inválidos, o regla contable violada"
+// @Failure      401   {object}  dtos.ErrorResponse              "No autenticado"
+// @Failure      409   {object}  dtos.ErrorResponse              "La transacción no está pendiente"
+// @Security     BearerAuth
+// @Router       /transactions/{id}/confirm [post]
+func (h *TransactionHandler) ConfirmTransaction(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		respondUnauthorized(c)
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid transaction ID",
+		})
+		return
+	}
+
+	// The body is optional
+	var req dtos.ConfirmTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	transaction, err := h.transactionService.Confirm(userID, uint(id), &req)
+	if err != nil {
+		if errors.Is(err, services.ErrTransactionNotPending) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Transaction is not pending",
+				"details": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to confirm transaction",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Transaction confirmed and posted successfully",
+		"data":    dtos.FromModelToTransactionResponse(transaction),
+	})
+}
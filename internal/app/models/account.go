@@ -9,19 +9,48 @@ import (
 
 type Account struct {
 	gorm.Model
-	UserID      uint            `gorm:"not null;index" json:"user_id"`
-	Name        string          `gorm:"size:100;not null" json:"name"`
-	AccountType string          `gorm:"size:50;not null" json:"account_type"` // BANK, CASH, CREDIT_CARD, SAVINGS, INVESTMENT
-	CurrencyID  *uint           `gorm:"not null" json:"currency_id"`
-	Balance     decimal.Decimal `gorm:"type:decimal(19,4);default:0" json:"balance"` // Upgraded precision per DOC.md
-	IsActive    bool            `gorm:"default:true" json:"is_active"`
-	Currency    *Currency       `gorm:"foreignKey:CurrencyID;references:ID" json:"currency,omitempty"`
+	UserID      uint   `gorm:"not null;index;uniqueIndex:idx_accounts_user_equity,where:account_type = 'EQUITY' AND deleted_at IS NULL" json:"user_id"` // One live equity account per user
+	Name        string `gorm:"size:100;not null" json:"name"`
+	AccountType string `gorm:"size:50;not null" json:"account_type"` // BANK, CASH, CREDIT_CARD, SAVINGS, INVESTMENT, LOAN, MORTGAGE, CATEGORY, EQUITY
+	// Classification is the ACCOUNT_CLASSIFICATION (ASSET, LIABILITY, EQUITY, INCOME, EXPENSE).
+	// Derived from AccountType when empty; nominal CATEGORY accounts take it from their category.
+	Classification string          `gorm:"size:20;index" json:"classification"`
+	CurrencyID     *uint           `gorm:"not null" json:"currency_id"`
+	Balance        decimal.Decimal `gorm:"type:decimal(19,4);default:0" json:"balance"` // Upgraded precision per DOC.md
+	IsActive       bool            `gorm:"default:true" json:"is_active"`
+	Version        uint            `gorm:"not null;default:1" json:"version"` // Optimistic lock: bumped on every write, including balance changes
+	Currency       *Currency       `gorm:"foreignKey:CurrencyID;references:ID" json:"currency,omitempty"`
+	Loan           *Loan           `gorm:"foreignKey:AccountID" json:"loan,omitempty"`        // LOAN and MORTGAGE accounts only
+	CreditCard     *CreditCard     `gorm:"foreignKey:AccountID" json:"credit_card,omitempty"` // CREDIT_CARD accounts only (optional)
 }
 
 func (Account) TableName() string {
 	return "accounts"
 }
 
+// BeforeSave fills the classification from the account type when it was not set explicitly
+func (a *Account) BeforeSave(tx *gorm.DB) error {
+	if a.Classification == "" {
+		a.Classification = DefaultClassification(a.AccountType)
+	}
+	return nil
+}
+
+// DefaultClassification returns the ACCOUNT_CLASSIFICATION implied by a real account type.
+// Nominal CATEGORY accounts have no default: they are INCOME or EXPENSE depending on the category.
+func DefaultClassification(accountType string) string {
+	switch accountType {
+	case "CREDIT_CARD", "LOAN", "MORTGAGE":
+		return "LIABILITY"
+	case "EQUITY":
+		return "EQUITY"
+	case "CATEGORY":
+		return ""
+	default:
+		return "ASSET"
+	}
+}
+
 // Validate performs business rule validation on the Account
 func (a *Account) Validate() error {
 	if a.UserID == 0 {
@@ -41,7 +70,7 @@ func (a *Account) Validate() error {
 	}
 
 	// Basic validation - detailed validation should be done at service layer with SystemValue
-	// Valid types are: BANK, CASH, CREDIT_CARD, SAVINGS, INVESTMENT, CATEGORY
+	// Valid types are: BANK, CASH, CREDIT_CARD, SAVINGS, INVESTMENT, LOAN, MORTGAGE, CATEGORY, EQUITY
 	// (These should match SystemValue.Category='ACCOUNT_TYPE')
 
 	if a.CurrencyID == nil {
@@ -62,7 +91,58 @@ func (a *Account) IsLiquidAsset() bool {
 	return a.AccountType == "BANK" || a.AccountType == "CASH"
 }
 
-// IsLiability returns true if this account represents a liability (CREDIT_CARD)
+// IsLiability returns true if this account represents a liability (CREDIT_CARD, LOAN or MORTGAGE)
 func (a *Account) IsLiability() bool {
-	return a.AccountType == "CREDIT_CARD"
+	return a.AccountType == "CREDIT_CARD" || a.IsLoan()
+}
+
+// IsLoan returns true if this account is an amortizing loan (LOAN or MORTGAGE) with Loan terms
+func (a *Account) IsLoan() bool {
+	return IsLoanType(a.AccountType)
+}
+
+// IsLoanType returns true for the account types that carry Loan terms
+func IsLoanType(accountType string) bool {
+	return accountType == "LOAN" || accountType == "MORTGAGE"
+}
+
+// IsNominal returns true if this is a ledger-only account backing a category (ACCOUNT_TYPE=CATEGORY).
+// Nominal accounts are managed by the system and are not shown in the user's account list.
+func (a *Account) IsNominal() bool {
+	return a.AccountType == "CATEGORY"
+}
+
+// IsEquity returns true if this is the user's equity account (counterpart of opening balances and adjustments)
+func (a *Account) IsEquity() bool {
+	return a.AccountType == "EQUITY"
+}
+
+// IsSystemManaged returns true for ledger accounts the system creates and maintains on its own
+// (category nominal accounts and the equity account). Users cannot create, edit or delete them.
+func (a *Account) IsSystemManaged() bool {
+	return a.IsNominal() || a.IsEquity()
+}
+
+// GetClassification returns the stored classification, falling back to the one implied by the type
+func (a *Account) GetClassification() string {
+	if a.Classification != "" {
+		return a.Classification
+	}
+	return DefaultClassification(a.AccountType)
+}
+
+// IsDebitNormal returns true if the account balance grows with debits (ASSET and EXPENSE accounts).
+// LIABILITY, EQUITY and INCOME accounts grow with credits.
+func (a *Account) IsDebitNormal() bool {
+	classification := a.GetClassification()
+	return classification == "ASSET" || classification == "EXPENSE"
+}
+
+// BalanceDelta returns the change a journal entry produces on this account's balance,
+// according to the account's normal side (debit-normal or credit-normal)
+func (a *Account) BalanceDelta(debitOrCredit string, amount decimal.Decimal) decimal.Decimal {
+	if (debitOrCredit == "DEBIT") == a.IsDebitNormal() {
+		return amount
+	}
+	return amount.Neg()
 }
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AccountingPeriod represents one calendar month of a user's books.
+// A period only exists once it has been closed at least once; months without a row are open.
+// While a period is CLOSED the Accounting Engine refuses any journal entry dated inside it.
+// Periods are calendar months in UTC.
+type AccountingPeriod struct {
+	gorm.Model
+	UserID     uint       `gorm:"not null;uniqueIndex:idx_accounting_periods_user_month" json:"user_id"`
+	Year       int        `gorm:"not null;uniqueIndex:idx_accounting_periods_user_month" json:"year"`
+	Month      int        `gorm:"not null;uniqueIndex:idx_accounting_periods_user_month;check:month BETWEEN 1 AND 12" json:"month"`
+	Status     string     `gorm:"size:10;not null;default:'OPEN';check:status IN ('OPEN', 'CLOSED')" json:"status"`
+	ClosedAt   *time.Time `json:"closed_at"`
+	ReopenedAt *time.Time `json:"reopened_at"`
+
+	// Relationships
+	Events []AccountingPeriodEvent `gorm:"foreignKey:AccountingPeriodID" json:"events,omitempty"`
+}
+
+// TableName overrides the table name
+func (AccountingPeriod) TableName() string {
+	return "accounting_periods"
+}
+
+// Validate performs business rule validation on the AccountingPeriod
+func (p *AccountingPeriod) Validate() error {
+	if p.UserID == 0 {
+		return errors.New("user_id is required")
+	}
+
+	if p.Month < 1 || p.Month > 12 {
+		return fmt.Errorf("month must be between 1 and 12, got: %d", p.Month)
+	}
+
+	if p.Year < 1900 {
+		return fmt.Errorf("year is out of range, got: %d", p.Year)
+	}
+
+	if p.Status != "OPEN" && p.Status != "CLOSED" {
+		return fmt.Errorf("status must be OPEN or CLOSED, got: %s", p.Status)
+	}
+
+	return nil
+}
+
+// IsClosed returns true if entries dated inside this period are locked
+func (p *AccountingPeriod) IsClosed() bool {
+	return p.Status == "CLOSED"
+}
+
+// StartDate returns the first instant of the period
+func (p *AccountingPeriod) StartDate() time.Time {
+	return time.Date(p.Year, time.Month(p.Month), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// EndDate returns the first instant after the period (exclusive bound)
+func (p *AccountingPeriod) EndDate() time.Time {
+	return p.StartDate().AddDate(0, 1, 0)
+}
+
+// PeriodOf returns the year and month of the accounting period that contains the date
+func PeriodOf(date time.Time) (int, int) {
+	utc := date.UTC()
+	return utc.Year(), int(utc.Month())
+}
@@ -0,0 +1,18 @@
+package models
+
+import "gorm.io/gorm"
+
+// AccountingPeriodEvent is the audit trail of an accounting period.
+// One row is written every time the owner closes or reopens the period; rows are never updated.
+type AccountingPeriodEvent struct {
+	gorm.Model
+	AccountingPeriodID uint   `gorm:"index;not null" json:"accounting_period_id"`
+	UserID             uint   `gorm:"index;not null" json:"user_id"` // User who performed the action
+	Action             string `gorm:"size:10;not null;check:action IN ('CLOSE', 'REOPEN')" json:"action"`
+	Reason             string `gorm:"size:255" json:"reason"`
+}
+
+// TableName overrides the table name
+func (AccountingPeriodEvent) TableName() string {
+	return "accounting_period_events"
+}
@@ -0,0 +1,149 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// Budget caps the spending of an expense category over a repeating period.
+// Periods are numbered from 0 and anchored at StartDate (UTC): MONTHLY budgets follow calendar
+// months (StartDate is the first day of the first month), WEEKLY budgets run 7 days and CUSTOM
+// budgets PeriodDays days. The last period is the one containing EndDate, if set.
+//
+// Rollover carries the result of a period into the next one:
+//   - NONE:    every period starts from Amount
+//   - UNSPENT: what was left unspent is added to the next period (overspending is forgiven)
+//   - ALL:     both the unspent amount and the overspending carry over
+type Budget struct {
+	gorm.Model
+	UserID     uint            `gorm:"not null;index" json:"user_id"`
+	CategoryID uint            `gorm:"not null;index" json:"category_id"`
+	Amount     decimal.Decimal `gorm:"type:decimal(19,4);not null" json:"amount"` // Budgeted per period
+	Period     string          `gorm:"size:10;not null;check:period IN ('MONTHLY', 'WEEKLY', 'CUSTOM')" json:"period"`
+	PeriodDays int             `gorm:"not null;default:0" json:"period_days"` // CUSTOM only
+	StartDate  time.Time       `gorm:"not null" json:"start_date"`
+	EndDate    *time.Time      `json:"end_date"`
+	Rollover   string          `gorm:"size:10;not null;default:'NONE';check:rollover IN ('NONE', 'UNSPENT', 'ALL')" json:"rollover"`
+	Notes      string          `gorm:"type:text" json:"notes"`
+
+	// Relationships
+	Category *Category `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+}
+
+// TableName overrides the table name
+func (Budget) TableName() string {
+	return "budgets"
+}
+
+// Validate performs business rule validation on the Budget
+func (b *Budget) Validate() error {
+	if b.UserID == 0 {
+		return errors.New("user_id is required")
+	}
+
+	if b.CategoryID == 0 {
+		return errors.New("category_id is required")
+	}
+
+	if !b.Amount.IsPositive() {
+		return fmt.Errorf("amount must be positive, got: %s", b.Amount.String())
+	}
+
+	switch b.Period {
+	case "MONTHLY", "WEEKLY":
+		if b.PeriodDays != 0 {
+			return errors.New("period_days is only supported for CUSTOM budgets")
+		}
+	case "CUSTOM":
+		if b.PeriodDays < 1 || b.PeriodDays > 366 {
+			return errors.New("period_days must be between 1 and 366 for CUSTOM budgets")
+		}
+	default:
+		return fmt.Errorf("period must be MONTHLY, WEEKLY or CUSTOM, got: %q", b.Period)
+	}
+
+	switch b.Rollover {
+	case "NONE", "UNSPENT", "ALL":
+	default:
+		return fmt.Errorf("rollover must be NONE, UNSPENT or ALL, got: %q", b.Rollover)
+	}
+
+	if b.StartDate.IsZero() {
+		return errors.New("start_date is required")
+	}
+
+	if b.EndDate != nil && b.EndDate.Before(b.StartDate) {
+		return errors.New("end_date cannot be before start_date")
+	}
+
+	return nil
+}
+
+// PeriodStart returns the start of the n-th period (0-based)
+func (b *Budget) PeriodStart(n int) time.Time {
+	start := b.StartDate.UTC()
+
+	switch b.Period {
+	case "MONTHLY":
+		return time.Date(start.Year(), start.Month()+time.Month(n), 1, 0, 0, 0, 0, time.UTC)
+	case "WEEKLY":
+		return start.AddDate(0, 0, 7*n)
+	default:
+		return start.AddDate(0, 0, b.PeriodDays*n)
+	}
+}
+
+// PeriodIndex returns the period containing date, and false if date is before the first
+// period or after the last one
+func (b *Budget) PeriodIndex(date time.Time) (int, bool) {
+	if date.Before(b.PeriodStart(0)) || b.HasEnded(date) {
+		return 0, false
+	}
+
+	return b.periodOf(date), true
+}
+
+// HasEnded returns true if date falls after the period containing EndDate
+func (b *Budget) HasEnded(date time.Time) bool {
+	return b.EndDate != nil && b.periodOf(date) > b.periodOf(*b.EndDate)
+}
+
+// Overlaps returns true if the periods of both budgets share at least one day
+func (b *Budget) Overlaps(other *Budget) bool {
+	if end := b.endsAt(); end != nil && !end.After(other.PeriodStart(0)) {
+		return false
+	}
+	if end := other.endsAt(); end != nil && !end.After(b.PeriodStart(0)) {
+		return false
+	}
+	return true
+}
+
+// endsAt returns the end (exclusive) of the last period, or nil for an open-ended budget
+func (b *Budget) endsAt() *time.Time {
+	if b.EndDate == nil {
+		return nil
+	}
+
+	end := b.PeriodStart(b.periodOf(*b.EndDate) + 1)
+	return &end
+}
+
+// periodOf returns the index of the period containing date (dates after StartDate only)
+func (b *Budget) periodOf(date time.Time) int {
+	date = date.UTC()
+	first := b.PeriodStart(0)
+
+	switch b.Period {
+	case "MONTHLY":
+		return (date.Year()-first.Year())*12 + int(date.Month()-first.Month())
+	case "WEEKLY":
+		return int(date.Sub(first) / (7 * 24 * time.Hour))
+	default:
+		return int(date.Sub(first) / (time.Duration(b.PeriodDays) * 24 * time.Hour))
+	}
+}
@@ -5,10 +5,14 @@ import "gorm.io/gorm"
 // Category represents the category entity in the database
 type Category struct {
 	gorm.Model
-	UserID   uint   `gorm:"not null;index" json:"user_id"`
+	UserID   uint   `gorm:"not null;index;uniqueIndex:idx_categories_user_loan_interest,where:name = 'Loan Interest' AND type = 'expense' AND deleted_at IS NULL" json:"user_id"` // One live "Loan Interest" expense category per user
 	Name     string `gorm:"size:100;not null" json:"name"`
 	Type     string `gorm:"size:50;not null" json:"type"` // e.g., "income" or "expense"
 	IsActive bool   `gorm:"default:true" json:"is_active"`
+
+	// LedgerAccountID is the nominal account (ACCOUNT_TYPE=CATEGORY) that receives this category's journal entries
+	LedgerAccountID *uint    `gorm:"index" json:"ledger_account_id"`
+	LedgerAccount   *Account `gorm:"foreignKey:LedgerAccountID" json:"ledger_account,omitempty"`
 }
 
 // TableName overrides the table name (optional)
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// CreditCard holds the statement terms of a CREDIT_CARD account.
+// A statement closes every month on StatementClosingDay (the last day of the month when shorter) and bills
+// what is owed at the end of that day; it is due on the first PaymentDueDay after the closing.
+// The minimum payment of a statement is MinimumPaymentPercent of its balance, but no less than
+// MinimumPaymentAmount (and never more than the balance itself).
+type CreditCard struct {
+	gorm.Model
+	AccountID             uint            `gorm:"not null;uniqueIndex" json:"account_id"`
+	CreditLimit           decimal.Decimal `gorm:"type:decimal(19,4);not null" json:"credit_limit"`
+	StatementClosingDay   int             `gorm:"not null" json:"statement_closing_day"`                               // 1-31
+	PaymentDueDay         int             `gorm:"not null" json:"payment_due_day"`                                     // 1-31
+	MinimumPaymentPercent decimal.Decimal `gorm:"type:decimal(9,4);not null;default:0" json:"minimum_payment_percent"` // Of the statement balance (e.g. 5)
+	MinimumPaymentAmount  decimal.Decimal `gorm:"type:decimal(19,4);not null;default:0" json:"minimum_payment_amount"` // Floor of the minimum payment
+	InterestRate          decimal.Decimal `gorm:"type:decimal(9,4);not null;default:0" json:"interest_rate"`           // Annual rate, in percent (informative: interest is posted by the user)
+}
+
+// TableName overrides the table name
+func (CreditCard) TableName() string {
+	return "credit_cards"
+}
+
+// Validate performs business rule validation on the CreditCard
+func (c *CreditCard) Validate() error {
+	if !c.CreditLimit.IsPositive() {
+		return fmt.Errorf("credit_limit must be positive, got: %s", c.CreditLimit.String())
+	}
+
+	if c.StatementClosingDay < 1 || c.StatementClosingDay > 31 {
+		return errors.New("statement_closing_day must be between 1 and 31")
+	}
+
+	if c.PaymentDueDay < 1 || c.PaymentDueDay > 31 {
+		return errors.New("payment_due_day must be between 1 and 31")
+	}
+
+	if c.MinimumPaymentPercent.IsNegative() || c.MinimumPaymentPercent.GreaterThan(decimal.NewFromInt(100)) {
+		return fmt.Errorf("minimum_payment_percent must be between 0 and 100, got: %s", c.MinimumPaymentPercent.String())
+	}
+
+	if c.MinimumPaymentAmount.IsNegative() {
+		return fmt.Errorf("minimum_payment_amount cannot be negative, got: %s", c.MinimumPaymentAmount.String())
+	}
+
+	if c.InterestRate.IsNegative() || c.InterestRate.GreaterThan(decimal.NewFromInt(100)) {
+		return fmt.Errorf("interest_rate must be between 0 and 100, got: %s", c.InterestRate.String())
+	}
+
+	return nil
+}
+
+// ClosingDate returns the statement closing date of a month
+func (c *CreditCard) ClosingDate(year int, month time.Month) time.Time {
+	return dayOfMonth(year, month, c.StatementClosingDay)
+}
+
+// LastClosingDate returns the last statement closing date on or before date
+func (c *CreditCard) LastClosingDate(date time.Time) time.Time {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+
+	closing := c.ClosingDate(day.Year(), day.Month())
+	if closing.After(day) {
+		closing = c.ClosingDate(day.Year(), day.Month()-1)
+	}
+	return closing
+}
+
+// NextClosingDate returns the statement closing date following closing
+func (c *CreditCard) NextClosingDate(closing time.Time) time.Time {
+	return c.ClosingDate(closing.Year(), closing.Month()+1)
+}
+
+// DueDateFor returns the payment due date of the statement closed on closing
+func (c *CreditCard) DueDateFor(closing time.Time) time.Time {
+	due := dayOfMonth(closing.Year(), closing.Month(), c.PaymentDueDay)
+	if !due.After(closing) {
+		due = dayOfMonth(closing.Year(), closing.Month()+1, c.PaymentDueDay)
+	}
+	return due
+}
+
+// MinimumPayment returns the minimum payment of a statement balance, rounded to cents
+func (c *CreditCard) MinimumPayment(statementBalance decimal.Decimal) decimal.Decimal {
+	if !statementBalance.IsPositive() {
+		return decimal.Zero
+	}
+
+	minimum := statementBalance.Mul(c.MinimumPaymentPercent).Div(decimal.NewFromInt(100)).Round(2)
+	minimum = decimal.Max(minimum, c.MinimumPaymentAmount)
+	return decimal.Min(minimum, statementBalance)
+}
+
+// dayOfMonth returns the given day of a month (UTC), or the month's last day when it is shorter
+func dayOfMonth(year int, month time.Month, day int) time.Time {
+	firstOfMonth := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	if last := firstOfMonth.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+
+	return firstOfMonth.AddDate(0, 0, day-1)
+}
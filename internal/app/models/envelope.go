@@ -0,0 +1,45 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Envelope holds liquid funds assigned to an expense category ("give every dollar a job").
+// Its balance is what was moved into it (EnvelopeMove) minus what was moved out, minus the EXPENSE
+// transactions posted to the category from StartDate on. No money moves between real accounts.
+// A category has at most one live envelope; deleted ones do not count.
+type Envelope struct {
+	gorm.Model
+	UserID     uint      `gorm:"not null;index;uniqueIndex:idx_envelopes_user_category,where:deleted_at IS NULL" json:"user_id"`
+	CategoryID uint      `gorm:"not null;index;uniqueIndex:idx_envelopes_user_category,where:deleted_at IS NULL" json:"category_id"`
+	StartDate  time.Time `gorm:"not null" json:"start_date"` // Expenses are drawn from the envelope from this date on
+	Notes      string    `gorm:"type:text" json:"notes"`
+
+	// Relationships
+	Category *Category `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+}
+
+// TableName overrides the table name
+func (Envelope) TableName() string {
+	return "envelopes"
+}
+
+// Validate performs business rule validation on the Envelope
+func (e *Envelope) Validate() error {
+	if e.UserID == 0 {
+		return errors.New("user_id is required")
+	}
+
+	if e.CategoryID == 0 {
+		return errors.New("category_id is required")
+	}
+
+	if e.StartDate.IsZero() {
+		return errors.New("start_date is required")
+	}
+
+	return nil
+}
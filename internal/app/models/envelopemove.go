@@ -0,0 +1,53 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// EnvelopeMove moves funds into, out of or between envelopes.
+// A nil FromEnvelopeID takes the amount from the unassigned funds (assignment); a nil ToEnvelopeID
+// returns it to them. Real account balances are never touched.
+type EnvelopeMove struct {
+	gorm.Model
+	UserID         uint            `gorm:"not null;index" json:"user_id"`
+	FromEnvelopeID *uint           `gorm:"index" json:"from_envelope_id"`
+	ToEnvelopeID   *uint           `gorm:"index" json:"to_envelope_id"`
+	Amount         decimal.Decimal `gorm:"type:decimal(19,4);not null" json:"amount"`
+	MoveDate       time.Time       `gorm:"not null" json:"move_date"`
+	Notes          string          `gorm:"type:text" json:"notes"`
+}
+
+// TableName overrides the table name
+func (EnvelopeMove) TableName() string {
+	return "envelope_moves"
+}
+
+// Validate performs business rule validation on the EnvelopeMove
+func (m *EnvelopeMove) Validate() error {
+	if m.UserID == 0 {
+		return errors.New("user_id is required")
+	}
+
+	if m.FromEnvelopeID == nil && m.ToEnvelopeID == nil {
+		return errors.New("from_envelope_id or to_envelope_id is required")
+	}
+
+	if m.FromEnvelopeID != nil && m.ToEnvelopeID != nil && *m.FromEnvelopeID == *m.ToEnvelopeID {
+		return errors.New("cannot move funds to the same envelope")
+	}
+
+	if !m.Amount.IsPositive() {
+		return fmt.Errorf("amount must be positive, got: %s", m.Amount.String())
+	}
+
+	if m.MoveDate.IsZero() {
+		return errors.New("move_date is required")
+	}
+
+	return nil
+}
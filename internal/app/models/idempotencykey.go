@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// IdempotencyKey records a create request sent with an Idempotency-Key header, so that a retry with
+// the same key replays the original response instead of creating the resource twice.
+// The key is reserved (StatusCode 0) before the request runs and completed with the response afterwards.
+// Records are hard-deleted: once expired, the same key can be used again.
+type IdempotencyKey struct {
+	gorm.Model
+	UserID       uint      `gorm:"not null;uniqueIndex:idx_idempotency_keys_user_key" json:"user_id"`
+	Key          string    `gorm:"size:255;not null;uniqueIndex:idx_idempotency_keys_user_key" json:"key"`
+	Method       string    `gorm:"size:10;not null" json:"method"`
+	Path         string    `gorm:"size:255;not null" json:"path"`         // Route pattern, e.g. /api/v1/transactions
+	RequestHash  string    `gorm:"size:64;not null" json:"request_hash"`  // SHA-256 of method, path and body
+	StatusCode   int       `gorm:"not null;default:0" json:"status_code"` // 0 while the original request is still running
+	ContentType  string    `gorm:"size:100" json:"content_type"`
+	ResponseBody string    `gorm:"type:text" json:"response_body"`
+	ExpiresAt    time.Time `gorm:"not null;index" json:"expires_at"`
+}
+
+// TableName overrides the table name
+func (IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
+
+// IsCompleted returns true once the original request finished and its response was stored
+func (k *IdempotencyKey) IsCompleted() bool {
+	return k.StatusCode != 0
+}
+
+// IsExpired returns true when the key is past its retention window
+func (k *IdempotencyKey) IsExpired(now time.Time) bool {
+	return !now.Before(k.ExpiresAt)
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// Loan holds the terms of a LOAN or MORTGAGE account.
+// The loan is amortized with a fixed monthly installment (French system): installment n is due on
+// PaymentDay of the n-th month after StartDate (the last day of the month when shorter), and its
+// interest is the outstanding principal times the monthly rate (InterestRate / 12).
+// DEBT_PAYMENT transactions to the account are split into principal and interest (InterestCategoryID).
+type Loan struct {
+	gorm.Model
+	AccountID          uint            `gorm:"not null;uniqueIndex" json:"account_id"`
+	Principal          decimal.Decimal `gorm:"type:decimal(19,4);not null" json:"principal"`
+	InterestRate       decimal.Decimal `gorm:"type:decimal(9,4);not null;default:0" json:"interest_rate"` // Annual nominal rate, in percent (e.g. 7.5)
+	TermMonths         int             `gorm:"not null" json:"term_months"`
+	PaymentDay         int             `gorm:"not null" json:"payment_day"`       // Day of the month installments are due (1-31)
+	StartDate          time.Time       `gorm:"not null" json:"start_date"`        // Disbursement date; the first installment is due the following month
+	InterestCategoryID *uint           `gorm:"index" json:"interest_category_id"` // Expense category receiving the interest legs
+}
+
+// TableName overrides the table name
+func (Loan) TableName() string {
+	return "loans"
+}
+
+// Validate performs business rule validation on the Loan
+func (l *Loan) Validate() error {
+	if !l.Principal.IsPositive() {
+		return fmt.Errorf("principal must be positive, got: %s", l.Principal.String())
+	}
+
+	if l.InterestRate.IsNegative() || l.InterestRate.GreaterThan(decimal.NewFromInt(100)) {
+		return fmt.Errorf("interest_rate must be between 0 and 100, got: %s", l.InterestRate.String())
+	}
+
+	if l.TermMonths < 1 || l.TermMonths > 600 {
+		return errors.New("term_months must be between 1 and 600")
+	}
+
+	if l.PaymentDay < 1 || l.PaymentDay > 31 {
+		return errors.New("payment_day must be between 1 and 31")
+	}
+
+	if l.StartDate.IsZero() {
+		return errors.New("start_date is required")
+	}
+
+	return nil
+}
+
+// MonthlyRate returns the interest rate applied each month, as a fraction
+func (l *Loan) MonthlyRate() decimal.Decimal {
+	return l.InterestRate.Div(decimal.NewFromInt(1200))
+}
+
+// Installment returns the fixed monthly payment that repays the principal over the term:
+// P * r / (1 - (1 + r)^-n), or P / n for interest-free loans. Rounded to cents.
+func (l *Loan) Installment() decimal.Decimal {
+	n := decimal.NewFromInt(int64(l.TermMonths))
+	rate := l.MonthlyRate()
+	if rate.IsZero() {
+		return l.Principal.DivRound(n, 2)
+	}
+
+	growth := decimal.NewFromInt(1).Add(rate).Pow(n)
+	return l.Principal.Mul(rate).Mul(growth).Div(growth.Sub(decimal.NewFromInt(1))).Round(2)
+}
+
+// InterestFor returns the interest accrued in one month on an outstanding principal, rounded to cents
+func (l *Loan) InterestFor(outstanding decimal.Decimal) decimal.Decimal {
+	return outstanding.Mul(l.MonthlyRate()).Round(2)
+}
+
+// DueDate returns the due date of installment n (1-based)
+func (l *Loan) DueDate(n int) time.Time {
+	start := l.StartDate.UTC()
+	return dayOfMonth(start.Year(), start.Month()+time.Month(n), l.PaymentDay)
+}
+
+// NextDueDate returns the first installment due on or after date, and false once the term is over
+func (l *Loan) NextDueDate(date time.Time) (int, time.Time, bool) {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	for n := 1; n <= l.TermMonths; n++ {
+		if due := l.DueDate(n); !due.Before(day) {
+			return n, due, true
+		}
+	}
+	return 0, time.Time{}, false
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// Reconciliation matches an account's books against a bank or card statement.
+// The user ticks the transactions that appear on the statement (Items); the reconciliation can only
+// be completed once OpeningBalance + cleared amounts equals StatementBalance. Completing it locks
+// the cleared transactions against edits and reversal.
+// Balances follow the account's normal side (for a credit card, the amount owed).
+type Reconciliation struct {
+	gorm.Model
+	UserID           uint            `gorm:"not null;index" json:"user_id"`
+	AccountID        uint            `gorm:"not null;index;uniqueIndex:idx_reconciliations_account_in_progress,where:status = 'IN_PROGRESS' AND deleted_at IS NULL" json:"account_id"` // At most one IN_PROGRESS per account
+	StatementDate    time.Time       `gorm:"not null" json:"statement_date"`
+	StatementBalance decimal.Decimal `gorm:"type:decimal(19,4);not null" json:"statement_balance"`         // Ending balance printed on the statement
+	OpeningBalance   decimal.Decimal `gorm:"type:decimal(19,4);not null;default:0" json:"opening_balance"` // Ending balance of the previous completed reconciliation
+	Status           string          `gorm:"size:20;not null;default:'IN_PROGRESS';check:status IN ('IN_PROGRESS', 'COMPLETED')" json:"status"`
+	CompletedAt      *time.Time      `json:"completed_at"`
+
+	// Relationships
+	Account Account              `gorm:"foreignKey:AccountID" json:"account,omitempty"`
+	Items   []ReconciliationItem `gorm:"foreignKey:ReconciliationID" json:"items,omitempty"`
+}
+
+// TableName overrides the table name
+func (Reconciliation) TableName() string {
+	return "reconciliations"
+}
+
+// Validate performs business rule validation on the Reconciliation
+func (r *Reconciliation) Validate() error {
+	if r.UserID == 0 {
+		return errors.New("user_id is required")
+	}
+
+	if r.AccountID == 0 {
+		return errors.New("account_id is required")
+	}
+
+	if r.StatementDate.IsZero() {
+		return errors.New("statement_date is required")
+	}
+
+	if r.Status != "IN_PROGRESS" && r.Status != "COMPLETED" {
+		return errors.New("status must be IN_PROGRESS or COMPLETED")
+	}
+
+	return nil
+}
+
+// IsCompleted returns true once the reconciliation has been finished (read-only from then on)
+func (r *Reconciliation) IsCompleted() bool {
+	return r.Status == "COMPLETED"
+}
+
+// ClearedBalance returns the opening balance plus every cleared amount
+func (r *Reconciliation) ClearedBalance() decimal.Decimal {
+	balance := r.OpeningBalance
+	for _, item := range r.Items {
+		balance = balance.Add(item.Amount)
+	}
+	return balance
+}
+
+// Difference returns what is still unexplained between the statement and the cleared transactions.
+// The reconciliation can be completed only when it is zero.
+func (r *Reconciliation) Difference() decimal.Decimal {
+	return r.StatementBalance.Sub(r.ClearedBalance())
+}
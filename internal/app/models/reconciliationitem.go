@@ -0,0 +1,23 @@
+package models
+
+import (
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// ReconciliationItem is a transaction ticked as cleared in a reconciliation.
+// Amount is the transaction's net effect on the reconciled account (normal side), captured when ticked.
+type ReconciliationItem struct {
+	gorm.Model
+	ReconciliationID uint            `gorm:"not null;uniqueIndex:idx_reconciliation_items_transaction" json:"reconciliation_id"`
+	TransactionID    uint            `gorm:"not null;uniqueIndex:idx_reconciliation_items_transaction;index" json:"transaction_id"`
+	Amount           decimal.Decimal `gorm:"type:decimal(19,4);not null" json:"amount"`
+
+	// Relationships
+	Transaction Transaction `gorm:"foreignKey:TransactionID" json:"transaction,omitempty"`
+}
+
+// TableName overrides the table name
+func (ReconciliationItem) TableName() string {
+	return "reconciliation_items"
+}
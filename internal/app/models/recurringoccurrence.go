@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// RecurringOccurrence records that an occurrence of a recurring transaction was materialized.
+// The unique index on (RecurringTransactionID, OccurrenceIndex) guarantees an occurrence is never
+// posted twice, even by concurrent scheduler runs. Skipped occurrences have no transaction.
+type RecurringOccurrence struct {
+	gorm.Model
+	RecurringTransactionID uint            `gorm:"not null;uniqueIndex:idx_recurring_occurrences_index" json:"recurring_transaction_id"`
+	OccurrenceIndex        int             `gorm:"not null;uniqueIndex:idx_recurring_occurrences_index" json:"occurrence_index"`
+	OccurrenceDate         time.Time       `gorm:"not null" json:"occurrence_date"`
+	Amount                 decimal.Decimal `gorm:"type:decimal(19,4);not null;default:0" json:"amount"` // Amount posted (after overrides)
+	Status                 string          `gorm:"size:20;not null;check:status IN ('POSTED', 'SKIPPED')" json:"status"`
+	TransactionID          *uint           `gorm:"index" json:"transaction_id"`
+}
+
+// TableName overrides the table name
+func (RecurringOccurrence) TableName() string {
+	return "recurring_occurrences"
+}
@@ -0,0 +1,205 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// RecurringTransaction is a template that materializes a transaction on a schedule
+// (salary on the 1st, monthly rent, a subscription every 30 days).
+// The schedule is RRULE-like: FREQ (Frequency) x INTERVAL, anchored at StartDate, optionally pinned
+// to a day of the month (BYMONTHDAY, clamped to the last day of short months) and bounded by
+// EndDate (UNTIL) and/or Count (COUNT). Occurrences are numbered from 0; NextOccurrence is the
+// index of the first one not yet materialized.
+type RecurringTransaction struct {
+	gorm.Model
+	UserID        uint            `gorm:"not null;index" json:"user_id"`
+	Type          string          `gorm:"type:varchar(20);not null;check:type IN ('INCOME', 'EXPENSE', 'TRANSFER', 'DEBT_PAYMENT')" json:"type"`
+	Description   string          `gorm:"size:255;not null" json:"description"`
+	Amount        decimal.Decimal `gorm:"type:decimal(19,4);not null" json:"amount"`
+	AccountFromID uint            `gorm:"index;not null" json:"account_from_id"`
+	AccountToID   *uint           `gorm:"index" json:"account_to_id"`
+	CategoryID    *uint           `gorm:"index" json:"category_id"`
+	Notes         string          `gorm:"type:text" json:"notes"`
+
+	// Schedule
+	Frequency  string     `gorm:"size:10;not null;check:frequency IN ('DAILY', 'WEEKLY', 'MONTHLY', 'YEARLY')" json:"frequency"`
+	Interval   int        `gorm:"not null;default:1" json:"interval"`
+	DayOfMonth *int       `json:"day_of_month"` // MONTHLY/YEARLY only; defaults to the day of StartDate
+	StartDate  time.Time  `gorm:"not null" json:"start_date"`
+	EndDate    *time.Time `json:"end_date"`
+	Count      *int       `json:"count"` // Maximum number of occurrences
+	IsActive   bool       `gorm:"not null;default:true" json:"is_active"`
+
+	// Progress: index and date of the next occurrence to materialize (date is nil once the schedule ended)
+	NextOccurrence     int        `gorm:"not null;default:0" json:"next_occurrence"`
+	NextOccurrenceDate *time.Time `gorm:"index" json:"next_occurrence_date"`
+
+	// Relationships
+	AccountFrom Account                        `gorm:"foreignKey:AccountFromID" json:"account_from,omitempty"`
+	AccountTo   *Account                       `gorm:"foreignKey:AccountToID" json:"account_to,omitempty"`
+	Category    *Category                      `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+	Overrides   []RecurringTransactionOverride `gorm:"foreignKey:RecurringTransactionID" json:"overrides,omitempty"`
+}
+
+// TableName overrides the table name
+func (RecurringTransaction) TableName() string {
+	return "recurring_transactions"
+}
+
+// Validate performs business rule validation on the schedule; the transaction fields are
+// validated by building an occurrence (see NewTransaction) and validating it
+func (r *RecurringTransaction) Validate() error {
+	if r.UserID == 0 {
+		return errors.New("user_id is required")
+	}
+
+	switch r.Frequency {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+	default:
+		return fmt.Errorf("frequency must be DAILY, WEEKLY, MONTHLY or YEARLY, got: %q", r.Frequency)
+	}
+
+	if r.Interval < 1 {
+		return errors.New("interval must be at least 1")
+	}
+
+	if r.DayOfMonth != nil {
+		if r.Frequency != "MONTHLY" && r.Frequency != "YEARLY" {
+			return errors.New("day_of_month is only supported for MONTHLY and YEARLY schedules")
+		}
+		if *r.DayOfMonth < 1 || *r.DayOfMonth > 31 {
+			return errors.New("day_of_month must be between 1 and 31")
+		}
+	}
+
+	if r.StartDate.IsZero() {
+		return errors.New("start_date is required")
+	}
+
+	if r.EndDate != nil && r.EndDate.Before(r.StartDate) {
+		return errors.New("end_date cannot be before start_date")
+	}
+
+	if r.Count != nil && *r.Count < 1 {
+		return errors.New("count must be at least 1")
+	}
+
+	return nil
+}
+
+// OccurrenceDate returns the date of the n-th occurrence (0-based), regardless of EndDate and Count.
+// Monthly and yearly dates are computed from the anchor, not from the previous occurrence,
+// so a schedule on the 31st comes back to the 31st after a short month.
+func (r *RecurringTransaction) OccurrenceDate(n int) time.Time {
+	start := r.StartDate
+	step := n * r.Interval
+
+	switch r.Frequency {
+	case "DAILY":
+		return start.AddDate(0, 0, step)
+	case "WEEKLY":
+		return start.AddDate(0, 0, 7*step)
+	}
+
+	day := start.Day()
+	if r.DayOfMonth != nil {
+		day = *r.DayOfMonth
+	}
+
+	year, month := start.Year(), start.Month()
+	// The first occurrence cannot fall before the start date: a day of month already past moves the anchor
+	if clampDay(year, month, day) < start.Day() {
+		if r.Frequency == "MONTHLY" {
+			month++
+		} else {
+			year++
+		}
+	}
+
+	if r.Frequency == "MONTHLY" {
+		month += time.Month(step)
+	} else {
+		year += step
+	}
+
+	// Normalize the month overflow before clamping the day
+	first := time.Date(year, month, 1, 0, 0, 0, 0, start.Location())
+	year, month = first.Year(), first.Month()
+
+	return time.Date(year, month, clampDay(year, month, day),
+		start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), start.Location())
+}
+
+// HasOccurrence returns true if the n-th occurrence is within the schedule's Count and EndDate
+func (r *RecurringTransaction) HasOccurrence(n int) bool {
+	if r.Count != nil && n >= *r.Count {
+		return false
+	}
+
+	if r.EndDate != nil && r.OccurrenceDate(n).After(*r.EndDate) {
+		return false
+	}
+
+	return true
+}
+
+// Advance moves NextOccurrence to the given index and refreshes NextOccurrenceDate
+// (nil when the schedule has no occurrence left)
+func (r *RecurringTransaction) Advance(next int) {
+	r.NextOccurrence = next
+	r.NextOccurrenceDate = nil
+	if r.HasOccurrence(next) {
+		date := r.OccurrenceDate(next)
+		r.NextOccurrenceDate = &date
+	}
+}
+
+// FindOverride returns the override for the occurrence on the given calendar day, if any
+func (r *RecurringTransaction) FindOverride(date time.Time) *RecurringTransactionOverride {
+	for i := range r.Overrides {
+		if sameDay(r.Overrides[i].OccurrenceDate, date) {
+			return &r.Overrides[i]
+		}
+	}
+	return nil
+}
+
+// NewTransaction builds the transaction of an occurrence with the given amount
+func (r *RecurringTransaction) NewTransaction(date time.Time, amount decimal.Decimal) *Transaction {
+	tx := &Transaction{
+		UserID:          r.UserID,
+		Type:            r.Type,
+		Description:     r.Description,
+		Amount:          amount,
+		AccountFromID:   r.AccountFromID,
+		AccountToID:     r.AccountToID,
+		CategoryID:      r.CategoryID,
+		TransactionDate: date,
+		Notes:           r.Notes,
+		ExchangeRate:    decimal.NewFromInt(1),
+	}
+	tx.AmountInUSD = tx.CalculateAmountInUSD()
+
+	return tx
+}
+
+// clampDay limits a day of month to the last day of the given month
+func clampDay(year int, month time.Month, day int) int {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if day > last {
+		return last
+	}
+	return day
+}
+
+// sameDay returns true if both times fall on the same calendar day (UTC)
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.UTC().Date()
+	by, bm, bd := b.UTC().Date()
+	return ay == by && am == bm && ad == bd
+}
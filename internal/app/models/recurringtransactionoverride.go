@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// RecurringTransactionOverride changes a single occurrence of a recurring transaction:
+// a different amount (e.g. a higher utility bill) or skipping it altogether.
+// OccurrenceDate is the calendar day of the scheduled occurrence.
+type RecurringTransactionOverride struct {
+	gorm.Model
+	RecurringTransactionID uint             `gorm:"not null;uniqueIndex:idx_recurring_overrides_occurrence" json:"recurring_transaction_id"`
+	OccurrenceDate         time.Time        `gorm:"type:date;not null;uniqueIndex:idx_recurring_overrides_occurrence" json:"occurrence_date"`
+	Amount                 *decimal.Decimal `gorm:"type:decimal(19,4)" json:"amount"` // Replaces the template amount when set
+	Skip                   bool             `gorm:"not null;default:false" json:"skip"`
+}
+
+// TableName overrides the table name
+func (RecurringTransactionOverride) TableName() string {
+	return "recurring_transaction_overrides"
+}
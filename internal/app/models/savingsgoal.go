@@ -0,0 +1,49 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// SavingsGoal is an amount the user wants to put aside, optionally by a target date
+// (emergency fund, vacation, car...). Money counts towards a goal in two ways:
+//   - Accounts: SAVINGS accounts linked in full; their whole balance belongs to the goal
+//   - Contributions: earmarked portions of an asset account's balance, set aside without a real transfer
+type SavingsGoal struct {
+	gorm.Model
+	UserID       uint            `gorm:"not null;index" json:"user_id"`
+	Name         string          `gorm:"size:100;not null" json:"name"`
+	TargetAmount decimal.Decimal `gorm:"type:decimal(19,4);not null" json:"target_amount"`
+	TargetDate   *time.Time      `json:"target_date"`
+	Notes        string          `gorm:"type:text" json:"notes"`
+
+	// Relationships
+	Accounts      []SavingsGoalAccount      `gorm:"foreignKey:SavingsGoalID" json:"accounts,omitempty"`
+	Contributions []SavingsGoalContribution `gorm:"foreignKey:SavingsGoalID" json:"contributions,omitempty"`
+}
+
+// TableName overrides the table name
+func (SavingsGoal) TableName() string {
+	return "savings_goals"
+}
+
+// Validate performs business rule validation on the SavingsGoal
+func (g *SavingsGoal) Validate() error {
+	if g.UserID == 0 {
+		return errors.New("user_id is required")
+	}
+
+	if g.Name == "" {
+		return errors.New("savings goal name is required")
+	}
+
+	if !g.TargetAmount.IsPositive() {
+		return fmt.Errorf("target_amount must be positive, got: %s", g.TargetAmount.String())
+	}
+
+	return nil
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// SavingsGoalAccount links a SAVINGS account in full to a savings goal.
+// The unique index on AccountID keeps an account from counting towards two goals; links are
+// hard-deleted when removed so the account can be linked again.
+type SavingsGoalAccount struct {
+	gorm.Model
+	SavingsGoalID uint `gorm:"not null;index" json:"savings_goal_id"`
+	AccountID     uint `gorm:"not null;uniqueIndex" json:"account_id"`
+
+	// Relationships
+	Account *Account `gorm:"foreignKey:AccountID" json:"account,omitempty"`
+}
+
+// TableName overrides the table name
+func (SavingsGoalAccount) TableName() string {
+	return "savings_goal_accounts"
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// SavingsGoalContribution earmarks part of an asset account's balance for a savings goal.
+// No money moves: the account keeps its balance and the goal counts the earmarked amount.
+// Negative amounts release a previous earmark.
+type SavingsGoalContribution struct {
+	gorm.Model
+	SavingsGoalID    uint            `gorm:"not null;index" json:"savings_goal_id"`
+	AccountID        uint            `gorm:"not null;index" json:"account_id"`
+	Amount           decimal.Decimal `gorm:"type:decimal(19,4);not null" json:"amount"`
+	ContributionDate time.Time       `gorm:"not null" json:"contribution_date"`
+	Notes            string          `gorm:"type:text" json:"notes"`
+
+	// Relationships
+	Account *Account `gorm:"foreignKey:AccountID" json:"account,omitempty"`
+}
+
+// TableName overrides the table name
+func (SavingsGoalContribution) TableName() string {
+	return "savings_goal_contributions"
+}
+
+// Validate performs business rule validation on the SavingsGoalContribution
+func (c *SavingsGoalContribution) Validate() error {
+	if c.SavingsGoalID == 0 {
+		return errors.New("savings_goal_id is required")
+	}
+
+	if c.AccountID == 0 {
+		return errors.New("account_id is required")
+	}
+
+	if c.Amount.IsZero() {
+		return errors.New("amount cannot be zero")
+	}
+
+	if c.ContributionDate.IsZero() {
+		return errors.New("contribution_date is required")
+	}
+
+	return nil
+}
@@ -13,20 +13,40 @@ import (
 type Transaction struct {
 	gorm.Model
 	UserID          uint            `gorm:"not null;index" json:"user_id"`
-	Type            string          `gorm:"type:varchar(20);not null;check:type IN ('INCOME', 'EXPENSE', 'TRANSFER')" json:"type"`
+	Type            string          `gorm:"type:varchar(20);not null;check:type IN ('INCOME', 'EXPENSE', 'TRANSFER', 'DEBT_PAYMENT', 'OPENING_BALANCE', 'ADJUSTMENT')" json:"type"`
 	Description     string          `gorm:"size:255;not null" json:"description"`
 	Amount          decimal.Decimal `gorm:"type:decimal(19,4);not null" json:"amount"`
 	AmountInUSD     decimal.Decimal `gorm:"type:decimal(19,4);default:0" json:"amount_in_usd"`
 	ExchangeRate    decimal.Decimal `gorm:"type:decimal(19,6);default:1" json:"exchange_rate"`
 	AccountFromID   uint            `gorm:"index;not null" json:"account_from_id"`
-	AccountToID     *uint           `gorm:"index" json:"account_to_id"` // Puntero porque es opcional (solo TRANSFER)
+	AccountToID     *uint           `gorm:"index" json:"account_to_id"` // Puntero porque es opcional (solo TRANSFER/DEBT_PAYMENT/OPENING_BALANCE/ADJUSTMENT)
 	CategoryID      *uint           `gorm:"index" json:"category_id"`   // Puntero porque es opcional (solo INCOME/EXPENSE)
 	TransactionDate time.Time       `gorm:"index;not null" json:"transaction_date"`
 	Notes           string          `gorm:"type:text" json:"notes"`
 	IsReconciled    bool            `gorm:"default:false" json:"is_reconciled"`
-	AccountFrom     Account         `gorm:"foreignKey:AccountFromID" json:"account_from,omitempty"`
-	AccountTo       *Account        `gorm:"foreignKey:AccountToID" json:"account_to,omitempty"`
-	Category        *Category       `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+
+	// Ciclo de vida: PENDING (futura o sin confirmar, sin asientos), POSTED (vigente),
+	// REVERSED (revertida al editarse), VOIDED (anulada al eliminarse)
+	Status string `gorm:"size:20;not null;default:'POSTED';check:status IN ('PENDING', 'POSTED', 'REVERSED', 'VOIDED')" json:"status"`
+
+	// Pendiente de confirmación: no se contabiliza automáticamente al llegar su fecha, solo al confirmarla
+	RequiresConfirmation bool `gorm:"not null;default:false" json:"requires_confirmation"`
+
+	// Reversión: la transacción revertida apunta al registro que la revierte y viceversa
+	ReversedByTransactionID *uint `gorm:"index" json:"reversed_by_transaction_id"` // Registro de reversión de esta transacción
+	ReversesTransactionID   *uint `gorm:"index" json:"reverses_transaction_id"`    // Transacción que este registro revierte
+
+	// Conciliación bancaria: primera conciliación completada que incluyó esta transacción (queda bloqueada)
+	ReconciliationID *uint `gorm:"index" json:"reconciliation_id"`
+
+	// Historial de ediciones: editar revierte la original y contabiliza una versión corregida
+	ReplacesTransactionID   *uint `gorm:"index" json:"replaces_transaction_id"`    // Transacción que esta versión corrige
+	ReplacedByTransactionID *uint `gorm:"index" json:"replaced_by_transaction_id"` // Versión corregida que reemplaza a esta
+
+	AccountFrom Account            `gorm:"foreignKey:AccountFromID" json:"account_from,omitempty"`
+	AccountTo   *Account           `gorm:"foreignKey:AccountToID" json:"account_to,omitempty"`
+	Category    *Category          `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+	Splits      []TransactionSplit `gorm:"foreignKey:TransactionID" json:"splits,omitempty"` // Solo para transacciones divididas (INCOME/EXPENSE)
 }
 
 // TableName define el nombre de la tabla
@@ -46,7 +66,7 @@ func (t *Transaction) Validate() error {
 	}
 
 	// Basic validation - detailed type validation should be done at service layer with SystemValue
-	// Valid types are: INCOME, EXPENSE, TRANSFER, DEBT_PAYMENT
+	// Valid types are: INCOME, EXPENSE, TRANSFER, DEBT_PAYMENT, OPENING_BALANCE, ADJUSTMENT
 	// (These should match SystemValue.Category='TRANSACTION_TYPE')
 
 	// Type-specific validation
@@ -58,8 +78,50 @@ func (t *Transaction) Validate() error {
 		if t.CategoryID != nil {
 			return errors.New("category_id should not be set for TRANSFER transactions")
 		}
+		if t.IsSplit() {
+			return errors.New("splits are only supported for INCOME and EXPENSE transactions")
+		}
+	case "DEBT_PAYMENT":
+		if t.AccountToID == nil {
+			return errors.New("account_to_id (the liability being paid) is required for DEBT_PAYMENT transactions")
+		}
+		if *t.AccountToID == t.AccountFromID {
+			return errors.New("account_from_id and account_to_id must be different for DEBT_PAYMENT transactions")
+		}
+		if t.CategoryID != nil {
+			return errors.New("category_id should not be set for DEBT_PAYMENT transactions")
+		}
+		if t.IsSplit() {
+			return errors.New("splits are only supported for INCOME and EXPENSE transactions")
+		}
+	case "OPENING_BALANCE", "ADJUSTMENT":
+		// Posted by the Accounting Engine against the user's equity account (debit AccountTo, credit AccountFrom)
+		if t.AccountToID == nil {
+			return fmt.Errorf("account_to_id is required for %s transactions", t.Type)
+		}
+		if *t.AccountToID == t.AccountFromID {
+			return fmt.Errorf("account_from_id and account_to_id must be different for %s transactions", t.Type)
+		}
+		if t.CategoryID != nil {
+			return fmt.Errorf("category_id should not be set for %s transactions", t.Type)
+		}
+		if t.IsSplit() {
+			return errors.New("splits are only supported for INCOME and EXPENSE transactions")
+		}
 	case "INCOME", "EXPENSE":
-		if t.CategoryID == nil {
+		if t.IsSplit() {
+			if t.CategoryID != nil {
+				return fmt.Errorf("category_id should not be set for split %s transactions (use the legs instead)", t.Type)
+			}
+			for i := range t.Splits {
+				if err := t.Splits[i].Validate(); err != nil {
+					return fmt.Errorf("split leg %d: %w", i+1, err)
+				}
+				if t.Splits[i].AccountID != nil && *t.Splits[i].AccountID == t.AccountFromID {
+					return fmt.Errorf("split leg %d: account_id cannot be the same as account_from_id", i+1)
+				}
+			}
+		} else if t.CategoryID == nil {
 			return fmt.Errorf("category_id is required for %s transactions", t.Type)
 		}
 		if t.AccountToID != nil {
@@ -85,6 +147,44 @@ func (t *Transaction) Validate() error {
 	return nil
 }
 
+// IsPosted returns true while the transaction is in effect (neither reversed nor voided)
+func (t *Transaction) IsPosted() bool {
+	return t.Status == "" || t.Status == "POSTED"
+}
+
+// IsPending returns true while the transaction waits for its date or its confirmation.
+// Pending transactions have no journal entries and do not change any balance.
+func (t *Transaction) IsPending() bool {
+	return t.Status == "PENDING"
+}
+
+// IsDue returns true if a pending transaction can be posted automatically by asOf
+func (t *Transaction) IsDue(asOf time.Time) bool {
+	return t.IsPending() && !t.RequiresConfirmation && !t.TransactionDate.After(asOf)
+}
+
+// IsReversal returns true for the record that carries the reversing entries of another transaction
+func (t *Transaction) IsReversal() bool {
+	return t.ReversesTransactionID != nil
+}
+
+// IsLocked returns true once the transaction was cleared in a completed reconciliation;
+// locked transactions can no longer be edited or reversed
+func (t *Transaction) IsLocked() bool {
+	return t.ReconciliationID != nil
+}
+
+// IsSplit returns true if this transaction carries multiple legs instead of a single category
+func (t *Transaction) IsSplit() bool {
+	return len(t.Splits) > 0
+}
+
+// IsEngineOnlyType returns true for the types the Accounting Engine posts itself against
+// the user's equity account (OPENING_BALANCE and ADJUSTMENT)
+func (t *Transaction) IsEngineOnlyType() bool {
+	return t.Type == "OPENING_BALANCE" || t.Type == "ADJUSTMENT"
+}
+
 // CalculateAmountInUSD calculates the USD equivalent amount using the exchange rate
 // If exchange rate is not set (0 or 1), assumes the amount is already in the base currency
 func (t *Transaction) CalculateAmountInUSD() decimal.Decimal {
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// TransactionSplit represents one leg of a split (multi-leg) transaction.
+// A split INCOME/EXPENSE transaction carries N legs instead of a single CategoryID;
+// each leg points either to a category or to a real account and carries its own amount.
+// The sum of all legs must equal Transaction.Amount (enforced by the Accounting Engine).
+type TransactionSplit struct {
+	gorm.Model
+	TransactionID uint            `gorm:"index;not null" json:"transaction_id"`
+	CategoryID    *uint           `gorm:"index" json:"category_id"` // Set for category legs
+	AccountID     *uint           `gorm:"index" json:"account_id"`  // Set for account legs
+	Amount        decimal.Decimal `gorm:"type:decimal(19,4);not null" json:"amount"`
+	Description   string          `gorm:"size:255" json:"description"`
+
+	// Relationships
+	Category *Category `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+	Account  *Account  `gorm:"foreignKey:AccountID" json:"account,omitempty"`
+}
+
+// TableName overrides the table name
+func (TransactionSplit) TableName() string {
+	return "transaction_splits"
+}
+
+// Validate performs business rule validation on a single split leg
+func (s *TransactionSplit) Validate() error {
+	if s.CategoryID == nil && s.AccountID == nil {
+		return errors.New("split leg requires either category_id or account_id")
+	}
+
+	if s.CategoryID != nil && s.AccountID != nil {
+		return errors.New("split leg cannot have both category_id and account_id")
+	}
+
+	if s.Amount.IsZero() || s.Amount.IsNegative() {
+		return fmt.Errorf("split amount must be positive, got: %s", s.Amount.String())
+	}
+
+	return nil
+}
+
+// IsCategoryLeg returns true if this leg is posted against a category
+func (s *TransactionSplit) IsCategoryLeg() bool {
+	return s.CategoryID != nil
+}
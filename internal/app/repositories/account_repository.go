@@ -3,9 +3,11 @@ package repositories
 import (
 	"arabella-api/internal/app/models"
 	"errors"
+	"fmt"
 
 	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // AccountRepository defines the interface for account data access
@@ -14,14 +16,20 @@ type AccountRepository interface {
 	FindByID(id uint) (*models.Account, error)
 	FindByUserID(userID uint) ([]*models.Account, error)
 	FindByUserAndType(userID uint, accountType string) ([]*models.Account, error)
+	FindByIDs(ids []uint) ([]*models.Account, error)
 	Update(account *models.Account) error
 	Delete(id uint) error
 	UpdateBalance(accountID uint, amount decimal.Decimal) error
 	GetTotalAssets(userID uint) (decimal.Decimal, error)
 	GetTotalLiabilities(userID uint) (decimal.Decimal, error)
+	GetShortTermLiabilities(userID uint) (decimal.Decimal, error)
 	GetLiquidAssets(userID uint) (decimal.Decimal, error)
+	SaveCreditCard(card *models.CreditCard) error
 }
 
+// ErrAccountVersionConflict is returned when an account was modified since it was read
+var ErrAccountVersionConflict = errors.New("account was modified by another request; reload it and retry")
+
 // accountRepositoryImpl implements AccountRepository using GORM
 type accountRepositoryImpl struct {
 	db *gorm.DB
@@ -41,11 +49,11 @@ func (r *accountRepositoryImpl) Create(account *models.Account) error {
 	return r.db.Create(account).Error
 }
 
-// FindByID finds an account by ID with currency preloaded
+// FindByID finds an account by ID with currency (and loan or credit card terms) preloaded
 func (r *accountRepositoryImpl) FindByID(id uint) (*models.Account, error) {
 	var account models.Account
 
-	err := r.db.Preload("Currency").First(&account, id).Error
+	err := r.db.Preload("Currency").Preload("Loan").Preload("CreditCard").First(&account, id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("account not found")
@@ -57,12 +65,15 @@ func (r *accountRepositoryImpl) FindByID(id uint) (*models.Account, error) {
 }
 
 // FindByUserID finds all accounts for a user
+// System-managed ledger accounts (CATEGORY and EQUITY) are excluded
 func (r *accountRepositoryImpl) FindByUserID(userID uint) ([]*models.Account, error) {
 	var accounts []*models.Account
 
 	err := r.db.
 		Preload("Currency").
-		Where("user_id = ? AND is_active = ?", userID, true).
+		Preload("Loan").
+		Preload("CreditCard").
+		Where("user_id = ? AND is_active = ? AND account_type NOT IN (?, ?)", userID, true, "CATEGORY", "EQUITY").
 		Order("created_at DESC").
 		Find(&accounts).Error
 
@@ -79,6 +90,8 @@ func (r *accountRepositoryImpl) FindByUserAndType(userID uint, accountType strin
 
 	err := r.db.
 		Preload("Currency").
+		Preload("Loan").
+		Preload("CreditCard").
 		Where("user_id = ? AND account_type = ? AND is_active = ?", userID, accountType, true).
 		Order("created_at DESC").
 		Find(&accounts).Error
@@ -90,13 +103,58 @@ func (r *accountRepositoryImpl) FindByUserAndType(userID uint, accountType strin
 	return accounts, nil
 }
 
-// Update updates an existing account
+// FindByIDs finds accounts by ID, including inactive and soft-deleted ones
+// Used by reports, where every account with journal entries must be listed
+func (r *accountRepositoryImpl) FindByIDs(ids []uint) ([]*models.Account, error) {
+	var accounts []*models.Account
+
+	if len(ids) == 0 {
+		return accounts, nil
+	}
+
+	err := r.db.Unscoped().
+		Where("id IN ?", ids).
+		Order("id ASC").
+		Find(&accounts).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return accounts, nil
+}
+
+// Update saves the editable fields of an account with optimistic locking: the write only applies if the
+// stored version is still account.Version, otherwise ErrAccountVersionConflict is returned.
+// The cached balance is written back as it was read, which the version check guarantees is still current;
+// only a change of normal side (asset <-> liability) flips its sign here. Amounts only move through
+// journal entries (Accounting Engine).
 func (r *accountRepositoryImpl) Update(account *models.Account) error {
 	if err := account.Validate(); err != nil {
 		return err
 	}
 
-	return r.db.Save(account).Error
+	result := r.db.Model(account).
+		Where("version = ?", account.Version).
+		Select("name", "account_type", "classification", "balance", "currency_id", "is_active", "version").
+		Updates(&models.Account{
+			Name:           account.Name,
+			AccountType:    account.AccountType,
+			Classification: account.Classification,
+			Balance:        account.Balance,
+			CurrencyID:     account.CurrencyID,
+			IsActive:       account.IsActive,
+			Version:        account.Version + 1,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAccountVersionConflict
+	}
+
+	account.Version++
+	return nil
 }
 
 // Delete soft deletes an account
@@ -104,22 +162,22 @@ func (r *accountRepositoryImpl) Delete(id uint) error {
 	return r.db.Delete(&models.Account{}, id).Error
 }
 
-// UpdateBalance updates the account balance atomically using optimistic locking
+// UpdateBalance adds amount to the account balance atomically (balance = balance + ?) and bumps its version
 func (r *accountRepositoryImpl) UpdateBalance(accountID uint, amount decimal.Decimal) error {
-	return r.db.Transaction(func(tx *gorm.DB) error {
-		var account models.Account
-
-		// Find the account within the transaction
-		if err := tx.Where("id = ?", accountID).First(&account).Error; err != nil {
-			return err
-		}
-
-		// Update balance
-		account.UpdateBalance(amount)
+	result := r.db.Model(&models.Account{}).
+		Where("id = ?", accountID).
+		Updates(map[string]interface{}{
+			"balance": gorm.Expr("balance + ?", amount),
+			"version": gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
 
-		// Save the updated balance
-		return tx.Save(&account).Error
-	})
+	return nil
 }
 
 // GetTotalAssets calculates total assets (BANK + CASH + SAVINGS + INVESTMENT)
@@ -139,10 +197,29 @@ func (r *accountRepositoryImpl) GetTotalAssets(userID uint) (decimal.Decimal, er
 	return total, nil
 }
 
-// GetTotalLiabilities calculates total liabilities (CREDIT_CARD)
+// GetTotalLiabilities calculates total liabilities (CREDIT_CARD + LOAN + MORTGAGE)
 func (r *accountRepositoryImpl) GetTotalLiabilities(userID uint) (decimal.Decimal, error) {
 	var total decimal.Decimal
 
+	err := r.db.Model(&models.Account{}).
+		Select("COALESCE(SUM(balance), 0)").
+		Where("user_id = ? AND account_type IN (?, ?, ?) AND is_active = ?",
+			userID, "CREDIT_CARD", "LOAN", "MORTGAGE", true).
+		Scan(&total).Error
+
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	// Liability balances are positive (we owe that amount), so return as is
+	return total, nil
+}
+
+// GetShortTermLiabilities calculates the liabilities due in full in the short term (CREDIT_CARD).
+// Loans are repaid in installments over their term and are left out.
+func (r *accountRepositoryImpl) GetShortTermLiabilities(userID uint) (decimal.Decimal, error) {
+	var total decimal.Decimal
+
 	err := r.db.Model(&models.Account{}).
 		Select("COALESCE(SUM(balance), 0)").
 		Where("user_id = ? AND account_type = ? AND is_active = ?",
@@ -153,15 +230,20 @@ func (r *accountRepositoryImpl) GetTotalLiabilities(userID uint) (decimal.Decima
 		return decimal.Zero, err
 	}
 
-	// Credit card balances are positive (we owe that amount), so return as is
 	return total, nil
 }
 
 // GetLiquidAssets calculates liquid assets (BANK + CASH only)
 func (r *accountRepositoryImpl) GetLiquidAssets(userID uint) (decimal.Decimal, error) {
+	return LiquidAssets(r.db, userID)
+}
+
+// LiquidAssets sums the balances of the user's active BANK and CASH accounts.
+// It accepts the caller's *gorm.DB so it can run inside an existing database transaction.
+func LiquidAssets(db *gorm.DB, userID uint) (decimal.Decimal, error) {
 	var total decimal.Decimal
 
-	err := r.db.Model(&models.Account{}).
+	err := db.Model(&models.Account{}).
 		Select("COALESCE(SUM(balance), 0)").
 		Where("user_id = ? AND account_type IN (?, ?) AND is_active = ?",
 			userID, "BANK", "CASH", true).
@@ -173,3 +255,66 @@ func (r *accountRepositoryImpl) GetLiquidAssets(userID uint) (decimal.Decimal, e
 
 	return total, nil
 }
+
+// SaveCreditCard creates or updates the terms of a CREDIT_CARD account (updated when card.ID is set)
+func (r *accountRepositoryImpl) SaveCreditCard(card *models.CreditCard) error {
+	if err := card.Validate(); err != nil {
+		return err
+	}
+
+	return r.db.Save(card).Error
+}
+
+// EnsureEquityAccount returns the user's equity account (ACCOUNT_TYPE=EQUITY), creating it on first use.
+// It is the counterpart of every OPENING_BALANCE and ADJUSTMENT transaction.
+// It accepts the caller's *gorm.DB so it can run inside an existing database transaction.
+// A user has at most one live equity account (idx_accounts_user_equity): when concurrent first
+// postings race to create it, the losers skip the insert and read the winner's account.
+func EnsureEquityAccount(db *gorm.DB, userID uint) (*models.Account, error) {
+	account, err := findEquityAccount(db, userID)
+	if err == nil {
+		return account, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	currencyID, err := findDefaultCurrencyID(db, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	account = &models.Account{
+		UserID:         userID,
+		Name:           "Opening Balance Equity",
+		AccountType:    "EQUITY",
+		Classification: "EQUITY",
+		CurrencyID:     &currencyID,
+		IsActive:       true,
+	}
+	result := db.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "user_id"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "account_type = 'EQUITY' AND deleted_at IS NULL"}}},
+		DoNothing:   true,
+	}).Create(account)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to create equity account for user %d: %w", userID, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return findEquityAccount(db, userID)
+	}
+
+	return account, nil
+}
+
+// findEquityAccount finds the user's live equity account
+func findEquityAccount(db *gorm.DB, userID uint) (*models.Account, error) {
+	var account models.Account
+
+	err := db.Where("user_id = ? AND account_type = ?", userID, "EQUITY").Order("id ASC").First(&account).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &account, nil
+}
@@ -0,0 +1,151 @@
+package repositories
+
+import (
+	"arabella-api/internal/app/models"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AccountingPeriodRepository defines the interface for accounting period data access
+type AccountingPeriodRepository interface {
+	FindByID(id uint) (*models.AccountingPeriod, error)
+	FindByUserAndMonth(userID uint, year, month int) (*models.AccountingPeriod, error)
+	FindByUser(userID uint) ([]*models.AccountingPeriod, error)
+	FindEvents(periodID uint) ([]*models.AccountingPeriodEvent, error)
+	Transition(userID uint, year, month int, apply func(period *models.AccountingPeriod) (*models.AccountingPeriodEvent, error)) (*models.AccountingPeriod, error)
+}
+
+// accountingPeriodRepositoryImpl implements AccountingPeriodRepository using GORM
+type accountingPeriodRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAccountingPeriodRepository creates a new accounting period repository
+func NewAccountingPeriodRepository(db *gorm.DB) AccountingPeriodRepository {
+	return &accountingPeriodRepositoryImpl{db: db}
+}
+
+// FindByID finds an accounting period by ID
+func (r *accountingPeriodRepositoryImpl) FindByID(id uint) (*models.AccountingPeriod, error) {
+	var period models.AccountingPeriod
+
+	err := r.db.First(&period, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("accounting period not found")
+		}
+		return nil, err
+	}
+
+	return &period, nil
+}
+
+// FindByUserAndMonth finds the accounting period of a user for a given month.
+// Returns gorm.ErrRecordNotFound if the period was never closed.
+func (r *accountingPeriodRepositoryImpl) FindByUserAndMonth(userID uint, year, month int) (*models.AccountingPeriod, error) {
+	var period models.AccountingPeriod
+
+	err := r.db.
+		Where("user_id = ? AND year = ? AND month = ?", userID, year, month).
+		First(&period).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &period, nil
+}
+
+// FindByUser finds all accounting periods of a user, most recent first
+func (r *accountingPeriodRepositoryImpl) FindByUser(userID uint) ([]*models.AccountingPeriod, error) {
+	var periods []*models.AccountingPeriod
+
+	err := r.db.
+		Where("user_id = ?", userID).
+		Order("year DESC, month DESC").
+		Find(&periods).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return periods, nil
+}
+
+// FindEvents finds the audit trail of an accounting period in chronological order
+func (r *accountingPeriodRepositoryImpl) FindEvents(periodID uint) ([]*models.AccountingPeriodEvent, error) {
+	var events []*models.AccountingPeriodEvent
+
+	err := r.db.
+		Where("accounting_period_id = ?", periodID).
+		Order("created_at ASC, id ASC").
+		Find(&events).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// Transition loads the user's period for the month under lock, lets apply change its status
+// and saves it together with the audit event apply returns, all in one database transaction.
+// A month without a row is handed to apply as a new OPEN period.
+func (r *accountingPeriodRepositoryImpl) Transition(userID uint, year, month int, apply func(period *models.AccountingPeriod) (*models.AccountingPeriodEvent, error)) (*models.AccountingPeriod, error) {
+	var period models.AccountingPeriod
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := LockUserPeriods(tx, userID, "NO KEY UPDATE"); err != nil {
+			return err
+		}
+
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND year = ? AND month = ?", userID, year, month).
+			First(&period).Error
+		if err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			period = models.AccountingPeriod{UserID: userID, Year: year, Month: month, Status: "OPEN"}
+		}
+
+		event, err := apply(&period)
+		if err != nil {
+			return err
+		}
+
+		if err := period.Validate(); err != nil {
+			return err
+		}
+
+		if err := tx.Omit("Events").Save(&period).Error; err != nil {
+			return err
+		}
+
+		event.AccountingPeriodID = period.ID
+		return tx.Create(event).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &period, nil
+}
+
+// LockUserPeriods locks the user's row as the guard for all of the user's accounting periods.
+// Posting takes it in SHARE mode while checking that a month is open; closing and reopening
+// take it in NO KEY UPDATE mode. Locking the user row rather than the period row also covers
+// months that have no period row yet, which a concurrent close would otherwise insert unseen.
+func LockUserPeriods(db *gorm.DB, userID uint, strength string) error {
+	var user models.User
+	err := db.Clauses(clause.Locking{Strength: strength}).
+		Select("id").
+		First(&user, userID).Error
+	if err != nil {
+		return fmt.Errorf("failed to lock accounting periods of user %d: %w", userID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"arabella-api/internal/app/models"
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// BudgetRepository defines the interface for budget data access
+type BudgetRepository interface {
+	Create(budget *models.Budget) error
+	FindByID(id uint) (*models.Budget, error)
+	FindByUser(userID uint) ([]*models.Budget, error)
+	FindByCategory(userID, categoryID uint) ([]*models.Budget, error)
+	Update(budget *models.Budget) error
+	Delete(id uint) error
+	FindExpenses(userID uint, categoryIDs []uint, from, to time.Time) ([]BudgetExpense, error)
+}
+
+// BudgetExpense is the amount one EXPENSE transaction posted to a category
+type BudgetExpense struct {
+	CategoryID      uint
+	TransactionID   uint
+	TransactionDate time.Time
+	Amount          decimal.Decimal
+}
+
+// budgetRepositoryImpl implements BudgetRepository using GORM
+type budgetRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewBudgetRepository creates a new budget repository
+func NewBudgetRepository(db *gorm.DB) BudgetRepository {
+	return &budgetRepositoryImpl{db: db}
+}
+
+// Create creates a new budget
+func (r *budgetRepositoryImpl) Create(budget *models.Budget) error {
+	if err := budget.Validate(); err != nil {
+		return err
+	}
+
+	return r.db.Omit("Category").Create(budget).Error
+}
+
+// FindByID finds a budget by ID with its category
+func (r *budgetRepositoryImpl) FindByID(id uint) (*models.Budget, error) {
+	var budget models.Budget
+
+	err := r.db.Preload("Category").First(&budget, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("budget not found")
+		}
+		return nil, err
+	}
+
+	return &budget, nil
+}
+
+// FindByUser finds all budgets of a user with their categories, oldest first
+func (r *budgetRepositoryImpl) FindByUser(userID uint) ([]*models.Budget, error) {
+	var budgets []*models.Budget
+
+	err := r.db.
+		Preload("Category").
+		Where("user_id = ?", userID).
+		Order("start_date ASC, id ASC").
+		Find(&budgets).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return budgets, nil
+}
+
+// FindByCategory finds all budgets of a user on one category
+func (r *budgetRepositoryImpl) FindByCategory(userID, categoryID uint) ([]*models.Budget, error) {
+	var budgets []*models.Budget
+
+	err := r.db.
+		Where("user_id = ? AND category_id = ?", userID, categoryID).
+		Order("start_date ASC, id ASC").
+		Find(&budgets).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return budgets, nil
+}
+
+// Update updates an existing budget
+func (r *budgetRepositoryImpl) Update(budget *models.Budget) error {
+	if err := budget.Validate(); err != nil {
+		return err
+	}
+
+	return r.db.Omit("Category").Save(budget).Error
+}
+
+// Delete soft deletes a budget
+func (r *budgetRepositoryImpl) Delete(id uint) error {
+	return r.db.Delete(&models.Budget{}, id).Error
+}
+
+// FindExpenses returns what every EXPENSE transaction dated in [from, to) posted to the given categories.
+// Amounts come from the journal entries on each category's nominal ledger account, so split legs count
+// towards their own category; reversed and voided transactions (and their reversal records) are left out.
+func (r *budgetRepositoryImpl) FindExpenses(userID uint, categoryIDs []uint, from, to time.Time) ([]BudgetExpense, error) {
+	var expenses []BudgetExpense
+	if len(categoryIDs) == 0 {
+		return expenses, nil
+	}
+
+	err := r.db.Model(&models.JournalEntry{}).
+		Select(`categories.id AS category_id,
+			transactions.id AS transaction_id,
+			transactions.transaction_date,
+			SUM(CASE WHEN journal_entries.debit_or_credit = 'DEBIT' THEN journal_entries.amount ELSE -journal_entries.amount END) AS amount`).
+		Joins("JOIN transactions ON transactions.id = journal_entries.transaction_id AND transactions.deleted_at IS NULL").
+		Joins("JOIN categories ON categories.ledger_account_id = journal_entries.account_id").
+		Where("journal_entries.user_id = ? AND categories.id IN ? AND transactions.type = ?", userID, categoryIDs, "EXPENSE").
+		Where("transactions.transaction_date >= ? AND transactions.transaction_date < ?", from, to).
+		Scopes(activeTransactions).
+		Group("categories.id, transactions.id, transactions.transaction_date").
+		Order("transactions.transaction_date ASC, transactions.id ASC").
+		Scan(&expenses).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return expenses, nil
+}
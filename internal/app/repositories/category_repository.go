@@ -3,8 +3,11 @@ package repositories
 import (
 	"arabella-api/internal/app/models"
 	"errors"
+	"fmt"
+	"strings"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // CategoryRepository defines the interface for category data access
@@ -13,6 +16,7 @@ type CategoryRepository interface {
 	FindByID(id uint) (*models.Category, error)
 	FindByUserID(userID uint) ([]*models.Category, error)
 	FindByUserAndType(userID uint, categoryType string) ([]*models.Category, error)
+	FindByLedgerAccountIDs(accountIDs []uint) ([]*models.Category, error)
 	Update(category *models.Category) error
 	Delete(id uint) error
 }
@@ -27,9 +31,16 @@ func NewCategoryRepository(db *gorm.DB) CategoryRepository {
 	return &categoryRepositoryImpl{db: db}
 }
 
-// Create creates a new category
+// Create creates a new category together with its nominal ledger account
 func (r *categoryRepositoryImpl) Create(category *models.Category) error {
-	return r.db.Create(category).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(category).Error; err != nil {
+			return err
+		}
+
+		_, err := EnsureCategoryLedgerAccount(tx, category)
+		return err
+	})
 }
 
 // FindByID finds a category by ID
@@ -63,6 +74,26 @@ func (r *categoryRepositoryImpl) FindByUserID(userID uint) ([]*models.Category,
 	return categories, nil
 }
 
+// FindByLedgerAccountIDs finds the categories backed by the given nominal ledger accounts,
+// including inactive and soft-deleted ones (their journal entries still appear in reports)
+func (r *categoryRepositoryImpl) FindByLedgerAccountIDs(accountIDs []uint) ([]*models.Category, error) {
+	var categories []*models.Category
+
+	if len(accountIDs) == 0 {
+		return categories, nil
+	}
+
+	err := r.db.Unscoped().
+		Where("ledger_account_id IN ?", accountIDs).
+		Find(&categories).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
 // FindByUserAndType finds categories by user ID and type (INCOME or EXPENSE)
 func (r *categoryRepositoryImpl) FindByUserAndType(userID uint, categoryType string) ([]*models.Category, error) {
 	var categories []*models.Category
@@ -79,12 +110,190 @@ func (r *categoryRepositoryImpl) FindByUserAndType(userID uint, categoryType str
 	return categories, nil
 }
 
-// Update updates an existing category
+// Update updates an existing category and keeps its ledger account name in sync
 func (r *categoryRepositoryImpl) Update(category *models.Category) error {
-	return r.db.Save(category).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("LedgerAccount").Save(category).Error; err != nil {
+			return err
+		}
+
+		if category.LedgerAccountID == nil {
+			return nil
+		}
+
+		var account models.Account
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&account, *category.LedgerAccountID).Error; err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{"name": category.Name}
+		classification := strings.ToUpper(category.Type)
+		if account.Classification != classification {
+			// INCOME and EXPENSE sit on opposite normal sides, so the cached
+			// balance flips sign to keep agreeing with the journal.
+			updates["classification"] = classification
+			updates["balance"] = account.Balance.Neg()
+		}
+
+		return tx.Model(&account).Updates(updates).Error
+	})
 }
 
 // Delete soft deletes a category
 func (r *categoryRepositoryImpl) Delete(id uint) error {
 	return r.db.Delete(&models.Category{}, id).Error
 }
+
+// EnsureCategoryLedgerAccount returns the nominal ledger account backing a category,
+// creating it (ACCOUNT_TYPE=CATEGORY, classified as the category's INCOME/EXPENSE type)
+// when the category does not have one yet. The account uses the user's default currency.
+// It accepts the caller's *gorm.DB so it can run inside an existing database transaction.
+// The category row is locked and re-read first, so concurrent first postings to the same
+// category share one ledger account instead of each creating their own.
+func EnsureCategoryLedgerAccount(db *gorm.DB, category *models.Category) (uint, error) {
+	if category.LedgerAccountID != nil {
+		return *category.LedgerAccountID, nil
+	}
+
+	var ledgerAccountID uint
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var locked models.Category
+		if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "ledger_account_id").
+			First(&locked, category.ID).Error; err != nil {
+			return fmt.Errorf("failed to lock category %d: %w", category.ID, err)
+		}
+		if locked.LedgerAccountID != nil {
+			ledgerAccountID = *locked.LedgerAccountID
+			return nil
+		}
+
+		id, err := createCategoryLedgerAccount(tx, category)
+		ledgerAccountID = id
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	category.LedgerAccountID = &ledgerAccountID
+	return ledgerAccountID, nil
+}
+
+// createCategoryLedgerAccount creates the ledger account for a category and links it.
+// Callers must hold the lock on the category row.
+func createCategoryLedgerAccount(db *gorm.DB, category *models.Category) (uint, error) {
+	currencyID, err := findDefaultCurrencyID(db, category.UserID)
+	if err != nil {
+		return 0, err
+	}
+
+	account := &models.Account{
+		UserID:         category.UserID,
+		Name:           category.Name,
+		AccountType:    "CATEGORY",
+		Classification: strings.ToUpper(category.Type),
+		CurrencyID:     &currencyID,
+		IsActive:       true,
+	}
+	if err := db.Create(account).Error; err != nil {
+		return 0, fmt.Errorf("failed to create ledger account for category %d: %w", category.ID, err)
+	}
+
+	if err := db.Model(&models.Category{}).Unscoped().
+		Where("id = ?", category.ID).
+		Update("ledger_account_id", account.ID).Error; err != nil {
+		return 0, fmt.Errorf("failed to link ledger account to category %d: %w", category.ID, err)
+	}
+
+	return account.ID, nil
+}
+
+// findDefaultCurrencyID resolves the currency ID of the user's default currency, falling back to USD
+func findDefaultCurrencyID(db *gorm.DB, userID uint) (uint, error) {
+	var currencyIDs []uint
+
+	err := db.Model(&models.Currency{}).
+		Joins("JOIN users ON users.default_currency = currencies.code").
+		Where("users.id = ?", userID).
+		Limit(1).
+		Pluck("currencies.id", &currencyIDs).Error
+	if err != nil {
+		return 0, err
+	}
+
+	if len(currencyIDs) == 0 {
+		err = db.Model(&models.Currency{}).
+			Where("code = ?", "USD").
+			Limit(1).
+			Pluck("id", &currencyIDs).Error
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if len(currencyIDs) == 0 {
+		return 0, fmt.Errorf("no currency available for user %d", userID)
+	}
+
+	return currencyIDs[0], nil
+}
+
+// loanInterestCategoryName is the expense category that receives loan interest when a loan has none of its own
+const loanInterestCategoryName = "Loan Interest"
+
+// EnsureLoanInterestCategory returns the user's "Loan Interest" expense category, creating it on first use.
+// It is the default category of the interest legs of loan payments.
+// It accepts the caller's *gorm.DB so it can run inside an existing database transaction.
+// A user has at most one live "Loan Interest" expense category (idx_categories_user_loan_interest):
+// when concurrent loan payments race to create it, the losers skip the insert and read the winner's.
+func EnsureLoanInterestCategory(db *gorm.DB, userID uint) (*models.Category, error) {
+	category, err := findLoanInterestCategory(db, userID)
+	if err == nil {
+		return category, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	category = &models.Category{
+		UserID:   userID,
+		Name:     loanInterestCategoryName,
+		Type:     "expense",
+		IsActive: true,
+	}
+	result := db.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "user_id"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "name = 'Loan Interest' AND type = 'expense' AND deleted_at IS NULL"}}},
+		DoNothing:   true,
+	}).Create(category)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to create loan interest category for user %d: %w", userID, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		if category, err = findLoanInterestCategory(db, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := EnsureCategoryLedgerAccount(db, category); err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// findLoanInterestCategory finds the user's live "Loan Interest" expense category
+func findLoanInterestCategory(db *gorm.DB, userID uint) (*models.Category, error) {
+	var category models.Category
+
+	err := db.Where("user_id = ? AND name = ? AND LOWER(type) = ?", userID, loanInterestCategoryName, "expense").
+		Order("id ASC").
+		First(&category).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
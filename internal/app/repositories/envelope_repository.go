@@ -0,0 +1,204 @@
+package repositories
+
+import (
+	"arabella-api/internal/app/models"
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EnvelopeRepository defines the interface for envelope data access
+type EnvelopeRepository interface {
+	WithUserLock(userID uint, fn func(repo EnvelopeRepository) error) error
+	Create(envelope *models.Envelope) error
+	FindByID(id uint) (*models.Envelope, error)
+	FindByUser(userID uint) ([]*models.Envelope, error)
+	FindByCategory(userID, categoryID uint) (*models.Envelope, error)
+	Delete(id uint) error
+	CreateMove(move *models.EnvelopeMove) error
+	FindMoves(userID uint, envelopeID *uint) ([]*models.EnvelopeMove, error)
+	GetMovedTotals(userID uint) ([]EnvelopeTotal, error)
+	GetSpentTotals(userID uint) ([]EnvelopeTotal, error)
+	GetLiquidAssets(userID uint) (decimal.Decimal, error)
+}
+
+// EnvelopeTotal is an amount aggregated per envelope
+type EnvelopeTotal struct {
+	EnvelopeID uint
+	Amount     decimal.Decimal
+}
+
+// envelopeRepositoryImpl implements EnvelopeRepository using GORM
+type envelopeRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewEnvelopeRepository creates a new envelope repository
+func NewEnvelopeRepository(db *gorm.DB) EnvelopeRepository {
+	return &envelopeRepositoryImpl{db: db}
+}
+
+// WithUserLock runs fn in a database transaction that holds the lock on the user's row, so
+// checks against the user's envelopes and unassigned funds are not interleaved with another
+// create or move of the same user. Writes through the repository passed to fn join the
+// transaction; they commit together once fn returns nil.
+func (r *envelopeRepositoryImpl) WithUserLock(userID uint, fn func(repo EnvelopeRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		err := tx.Clauses(clause.Locking{Strength: "NO KEY UPDATE"}).
+			Select("id").
+			First(&user, userID).Error
+		if err != nil {
+			return fmt.Errorf("failed to lock envelopes of user %d: %w", userID, err)
+		}
+
+		return fn(&envelopeRepositoryImpl{db: tx})
+	})
+}
+
+// Create creates a new envelope
+func (r *envelopeRepositoryImpl) Create(envelope *models.Envelope) error {
+	if err := envelope.Validate(); err != nil {
+		return err
+	}
+
+	return r.db.Omit("Category").Create(envelope).Error
+}
+
+// FindByID finds an envelope by ID with its category
+func (r *envelopeRepositoryImpl) FindByID(id uint) (*models.Envelope, error) {
+	var envelope models.Envelope
+
+	err := r.db.Preload("Category").First(&envelope, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("envelope not found")
+		}
+		return nil, err
+	}
+
+	return &envelope, nil
+}
+
+// FindByUser finds all envelopes of a user with their categories, oldest first
+func (r *envelopeRepositoryImpl) FindByUser(userID uint) ([]*models.Envelope, error) {
+	var envelopes []*models.Envelope
+
+	err := r.db.
+		Preload("Category").
+		Where("user_id = ?", userID).
+		Order("id ASC").
+		Find(&envelopes).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return envelopes, nil
+}
+
+// FindByCategory finds the envelope of a category.
+// Returns gorm.ErrRecordNotFound if the category has none.
+func (r *envelopeRepositoryImpl) FindByCategory(userID, categoryID uint) (*models.Envelope, error) {
+	var envelope models.Envelope
+
+	err := r.db.Where("user_id = ? AND category_id = ?", userID, categoryID).First(&envelope).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &envelope, nil
+}
+
+// Delete soft deletes an envelope; its balance goes back to the unassigned funds
+func (r *envelopeRepositoryImpl) Delete(id uint) error {
+	return r.db.Delete(&models.Envelope{}, id).Error
+}
+
+// CreateMove records a move of funds between envelopes or the unassigned funds
+func (r *envelopeRepositoryImpl) CreateMove(move *models.EnvelopeMove) error {
+	if err := move.Validate(); err != nil {
+		return err
+	}
+
+	return r.db.Create(move).Error
+}
+
+// FindMoves finds the moves of a user, most recent first; with envelopeID, only those into or out of it
+func (r *envelopeRepositoryImpl) FindMoves(userID uint, envelopeID *uint) ([]*models.EnvelopeMove, error) {
+	var moves []*models.EnvelopeMove
+
+	query := r.db.Where("user_id = ?", userID)
+	if envelopeID != nil {
+		query = query.Where("from_envelope_id = ? OR to_envelope_id = ?", *envelopeID, *envelopeID)
+	}
+
+	err := query.Order("move_date DESC, id DESC").Find(&moves).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return moves, nil
+}
+
+// GetMovedTotals returns, per envelope, the funds moved into it minus the funds moved out of it
+func (r *envelopeRepositoryImpl) GetMovedTotals(userID uint) ([]EnvelopeTotal, error) {
+	var in, out []EnvelopeTotal
+
+	err := r.db.Model(&models.EnvelopeMove{}).
+		Select("to_envelope_id AS envelope_id, SUM(amount) AS amount").
+		Where("user_id = ? AND to_envelope_id IS NOT NULL", userID).
+		Group("to_envelope_id").
+		Scan(&in).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.Model(&models.EnvelopeMove{}).
+		Select("from_envelope_id AS envelope_id, SUM(amount) AS amount").
+		Where("user_id = ? AND from_envelope_id IS NOT NULL", userID).
+		Group("from_envelope_id").
+		Scan(&out).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, total := range out {
+		in = append(in, EnvelopeTotal{EnvelopeID: total.EnvelopeID, Amount: total.Amount.Neg()})
+	}
+
+	return in, nil
+}
+
+// GetSpentTotals returns, per envelope, what the EXPENSE transactions dated from its start date on
+// posted to its category. Amounts come from the journal entries on the category's nominal ledger
+// account (debits - credits), so split legs count and refunds put funds back; reversed and voided
+// transactions are left out.
+func (r *envelopeRepositoryImpl) GetSpentTotals(userID uint) ([]EnvelopeTotal, error) {
+	var totals []EnvelopeTotal
+
+	err := r.db.Model(&models.JournalEntry{}).
+		Select(`envelopes.id AS envelope_id,
+			SUM(CASE WHEN journal_entries.debit_or_credit = 'DEBIT' THEN journal_entries.amount ELSE -journal_entries.amount END) AS amount`).
+		Joins("JOIN transactions ON transactions.id = journal_entries.transaction_id AND transactions.deleted_at IS NULL").
+		Joins("JOIN categories ON categories.ledger_account_id = journal_entries.account_id").
+		Joins("JOIN envelopes ON envelopes.category_id = categories.id AND envelopes.deleted_at IS NULL").
+		Where("envelopes.user_id = ? AND transactions.type = ?", userID, "EXPENSE").
+		Where("transactions.transaction_date >= envelopes.start_date").
+		Scopes(activeTransactions).
+		Group("envelopes.id").
+		Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return totals, nil
+}
+
+// GetLiquidAssets returns the user's liquid funds (BANK + CASH), the pool envelopes are allocated from
+func (r *envelopeRepositoryImpl) GetLiquidAssets(userID uint) (decimal.Decimal, error) {
+	return LiquidAssets(r.db, userID)
+}
@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"arabella-api/internal/app/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IdempotencyKeyRepository defines the interface for idempotency key data access
+type IdempotencyKeyRepository interface {
+	Reserve(record *models.IdempotencyKey, staleBefore time.Time) (*models.IdempotencyKey, error)
+	Complete(record *models.IdempotencyKey) error
+	Release(record *models.IdempotencyKey) error
+	DeleteExpired(now time.Time) (int64, error)
+}
+
+// idempotencyKeyRepositoryImpl implements IdempotencyKeyRepository using GORM
+type idempotencyKeyRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewIdempotencyKeyRepository creates a new idempotency key repository
+func NewIdempotencyKeyRepository(db *gorm.DB) IdempotencyKeyRepository {
+	return &idempotencyKeyRepositoryImpl{db: db}
+}
+
+// Reserve claims the user's key for a new request. An expired record of the same key is discarded first,
+// and so is a reservation created before staleBefore that never got a response (its request died).
+// If the key is already held, nothing is written and the existing record is returned; otherwise it returns nil.
+// The unique index makes the claim atomic: of two concurrent requests with the same key only one wins.
+func (r *idempotencyKeyRepositoryImpl) Reserve(record *models.IdempotencyKey, staleBefore time.Time) (*models.IdempotencyKey, error) {
+	var existing *models.IdempotencyKey
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().
+			Where("user_id = ? AND key = ?", record.UserID, record.Key).
+			Where("expires_at <= ? OR (status_code = 0 AND created_at <= ?)", time.Now(), staleBefore).
+			Delete(&models.IdempotencyKey{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			return nil
+		}
+
+		existing = &models.IdempotencyKey{}
+		return tx.Unscoped().
+			Where("user_id = ? AND key = ?", record.UserID, record.Key).
+			First(existing).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return existing, nil
+}
+
+// Complete stores the response of the original request
+func (r *idempotencyKeyRepositoryImpl) Complete(record *models.IdempotencyKey) error {
+	return r.db.Model(record).
+		Select("status_code", "content_type", "response_body").
+		Updates(record).Error
+}
+
+// Release gives up a reservation (hard delete) so the key can be retried, e.g. after a server error
+func (r *idempotencyKeyRepositoryImpl) Release(record *models.IdempotencyKey) error {
+	return r.db.Unscoped().Delete(record).Error
+}
+
+// DeleteExpired purges every key past its retention window and returns how many were removed
+func (r *idempotencyKeyRepositoryImpl) DeleteExpired(now time.Time) (int64, error) {
+	result := r.db.Unscoped().
+		Where("expires_at <= ?", now).
+		Delete(&models.IdempotencyKey{})
+
+	return result.RowsAffected, result.Error
+}
@@ -4,6 +4,7 @@ import (
 	"arabella-api/internal/app/dtos"
 	"arabella-api/internal/app/models"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -19,7 +20,35 @@ type JournalEntryRepository interface {
 	FindByUser(userID uint, filters dtos.JournalEntryFilters) ([]*models.JournalEntry, int64, error)
 	VerifyTransactionBalance(transactionID uint) (totalDebit, totalCredit decimal.Decimal, err error)
 	GetAccountBalance(accountID uint, asOf *time.Time) (decimal.Decimal, error)
+	GetAccountLeadingBalance(accountID uint, filters dtos.JournalEntryFilters, count int) (decimal.Decimal, error)
 	GetBalanceSheet(userID uint, asOf time.Time) (map[uint]decimal.Decimal, error)
+	GetAccountTotals(userID uint, from *time.Time, to time.Time) ([]AccountEntryTotals, error)
+	GetAccountActivitySince(accountID uint, from time.Time) (AccountEntryTotals, error)
+	GetAccountPeriodTotals(userID uint, classifications []string, from, to time.Time, bucket string) ([]AccountPeriodTotals, error)
+	GetAccountFlowTotals(userID uint, from, to time.Time) ([]AccountFlowTotals, error)
+}
+
+// AccountEntryTotals holds the debit and credit totals posted to one account
+type AccountEntryTotals struct {
+	AccountID    uint
+	TotalDebits  decimal.Decimal
+	TotalCredits decimal.Decimal
+}
+
+// AccountPeriodTotals holds the debit and credit totals posted to one account within one sub-period
+type AccountPeriodTotals struct {
+	AccountID    uint
+	PeriodStart  time.Time // Start of the bucket (UTC)
+	TotalDebits  decimal.Decimal
+	TotalCredits decimal.Decimal
+}
+
+// AccountFlowTotals holds the debit and credit totals posted to one account by one transaction type
+type AccountFlowTotals struct {
+	AccountID       uint
+	TransactionType string
+	TotalDebits     decimal.Decimal
+	TotalCredits    decimal.Decimal
 }
 
 // journalEntryRepositoryImpl implements JournalEntryRepository using GORM
@@ -125,11 +154,17 @@ func (r *journalEntryRepositoryImpl) FindByAccount(accountID uint, filters dtos.
 
 	offset := (page - 1) * pageSize
 
+	order := "entry_date DESC, created_at DESC"
+	if filters.Ascending {
+		// id breaks ties between entries of the same date (same order as GetAccountLeadingBalance)
+		order = "entry_date ASC, id ASC"
+	}
+
 	// Get entries
 	err := query.
 		Preload("Account.Currency").
 		Preload("Transaction").
-		Order("entry_date DESC, created_at DESC").
+		Order(order).
 		Limit(pageSize).
 		Offset(offset).
 		Find(&entries).Error
@@ -233,11 +268,13 @@ func (r *journalEntryRepositoryImpl) VerifyTransactionBalance(transactionID uint
 
 // GetAccountBalance calculates account balance from journal entries up to a point in time
 func (r *journalEntryRepositoryImpl) GetAccountBalance(accountID uint, asOf *time.Time) (decimal.Decimal, error) {
+	// Session makes the base query reusable: without it the DEBIT condition would leak into the CREDIT query
 	query := r.db.Model(&models.JournalEntry{}).Where("account_id = ?", accountID)
 
 	if asOf != nil {
 		query = query.Where("entry_date <= ?", *asOf)
 	}
+	query = query.Session(&gorm.Session{})
 
 	// Get total debits
 	var totalDebits decimal.Decimal
@@ -269,6 +306,39 @@ func (r *journalEntryRepositoryImpl) GetAccountBalance(accountID uint, asOf *tim
 	return balance, nil
 }
 
+// GetAccountLeadingBalance returns Debits - Credits of the first count entries of an account in
+// chronological order within the filters' date range, i.e. the entries that precede a given page
+// of FindByAccount with filters.Ascending
+func (r *journalEntryRepositoryImpl) GetAccountLeadingBalance(accountID uint, filters dtos.JournalEntryFilters, count int) (decimal.Decimal, error) {
+	if count < 1 {
+		return decimal.Zero, nil
+	}
+
+	leading := r.db.Model(&models.JournalEntry{}).
+		Select("debit_or_credit, amount").
+		Where("account_id = ?", accountID)
+
+	if filters.StartDate != nil {
+		leading = leading.Where("entry_date >= ?", *filters.StartDate)
+	}
+
+	if filters.EndDate != nil {
+		leading = leading.Where("entry_date <= ?", *filters.EndDate)
+	}
+
+	leading = leading.Order("entry_date ASC, id ASC").Limit(count)
+
+	var balance decimal.Decimal
+	err := r.db.Table("(?) AS leading_entries", leading).
+		Select("COALESCE(SUM(CASE WHEN debit_or_credit = 'DEBIT' THEN amount ELSE -amount END), 0)").
+		Scan(&balance).Error
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	return balance, nil
+}
+
 // GetBalanceSheet returns balances for all accounts at a point in time
 func (r *journalEntryRepositoryImpl) GetBalanceSheet(userID uint, asOf time.Time) (map[uint]decimal.Decimal, error) {
 	type AccountBalance struct {
@@ -301,3 +371,114 @@ func (r *journalEntryRepositoryImpl) GetBalanceSheet(userID uint, asOf time.Time
 
 	return balances, nil
 }
+
+// GetAccountTotals returns the debit and credit totals of every account the user posted to
+// with entry dates in [from, to]. A nil from includes everything up to to.
+func (r *journalEntryRepositoryImpl) GetAccountTotals(userID uint, from *time.Time, to time.Time) ([]AccountEntryTotals, error) {
+	query := r.db.Model(&models.JournalEntry{}).
+		Select(`account_id,
+			COALESCE(SUM(CASE WHEN debit_or_credit = 'DEBIT' THEN amount ELSE 0 END), 0) AS total_debits,
+			COALESCE(SUM(CASE WHEN debit_or_credit = 'CREDIT' THEN amount ELSE 0 END), 0) AS total_credits`).
+		Where("user_id = ? AND entry_date <= ?", userID, to)
+
+	if from != nil {
+		query = query.Where("entry_date >= ?", *from)
+	}
+
+	var totals []AccountEntryTotals
+	err := query.Group("account_id").Order("account_id ASC").Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return totals, nil
+}
+
+// GetAccountActivitySince returns the debit and credit totals posted to one account with entry dates
+// on or after from (future-dated entries included)
+func (r *journalEntryRepositoryImpl) GetAccountActivitySince(accountID uint, from time.Time) (AccountEntryTotals, error) {
+	totals := AccountEntryTotals{AccountID: accountID}
+
+	err := r.db.Model(&models.JournalEntry{}).
+		Select(`COALESCE(SUM(CASE WHEN debit_or_credit = 'DEBIT' THEN amount ELSE 0 END), 0) AS total_debits,
+			COALESCE(SUM(CASE WHEN debit_or_credit = 'CREDIT' THEN amount ELSE 0 END), 0) AS total_credits`).
+		Where("account_id = ? AND entry_date >= ?", accountID, from).
+		Scan(&totals).Error
+	if err != nil {
+		return AccountEntryTotals{}, err
+	}
+
+	return totals, nil
+}
+
+// GetAccountPeriodTotals returns the debit and credit totals per account and per sub-period
+// (bucket: "month", "quarter" or "year", truncated in UTC) for entries dated in [from, to],
+// limited to accounts with the given ACCOUNT_CLASSIFICATIONs
+func (r *journalEntryRepositoryImpl) GetAccountPeriodTotals(userID uint, classifications []string, from, to time.Time, bucket string) ([]AccountPeriodTotals, error) {
+	switch bucket {
+	case "month", "quarter", "year":
+	default:
+		return nil, fmt.Errorf("unsupported period: %s", bucket)
+	}
+
+	var totals []AccountPeriodTotals
+	err := r.db.Model(&models.JournalEntry{}).
+		Select(`journal_entries.account_id,
+			date_trunc(?, journal_entries.entry_date AT TIME ZONE 'UTC') AS period_start,
+			COALESCE(SUM(CASE WHEN journal_entries.debit_or_credit = 'DEBIT' THEN journal_entries.amount ELSE 0 END), 0) AS total_debits,
+			COALESCE(SUM(CASE WHEN journal_entries.debit_or_credit = 'CREDIT' THEN journal_entries.amount ELSE 0 END), 0) AS total_credits`, bucket).
+		Joins("JOIN accounts ON accounts.id = journal_entries.account_id").
+		Where("journal_entries.user_id = ? AND journal_entries.entry_date >= ? AND journal_entries.entry_date <= ?", userID, from, to).
+		Where("accounts.classification IN ?", classifications).
+		Group("journal_entries.account_id, period_start").
+		Order("period_start ASC, journal_entries.account_id ASC").
+		Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return totals, nil
+}
+
+// GetAccountFlowTotals returns the debit and credit totals per account and per type of the
+// originating transaction, for entries dated in [from, to]. Reversing entries belong to a
+// reversal record that mirrors the original's type, so they land in the same type and net
+// out against the original when both fall inside the range.
+func (r *journalEntryRepositoryImpl) GetAccountFlowTotals(userID uint, from, to time.Time) ([]AccountFlowTotals, error) {
+	var totals []AccountFlowTotals
+	err := r.db.Model(&models.JournalEntry{}).
+		Select(`journal_entries.account_id,
+			transactions.type AS transaction_type,
+			COALESCE(SUM(CASE WHEN journal_entries.debit_or_credit = 'DEBIT' THEN journal_entries.amount ELSE 0 END), 0) AS total_debits,
+			COALESCE(SUM(CASE WHEN journal_entries.debit_or_credit = 'CREDIT' THEN journal_entries.amount ELSE 0 END), 0) AS total_credits`).
+		Joins("JOIN transactions ON transactions.id = journal_entries.transaction_id").
+		Where("journal_entries.user_id = ? AND journal_entries.entry_date >= ? AND journal_entries.entry_date <= ?", userID, from, to).
+		Group("journal_entries.account_id, transactions.type").
+		Order("journal_entries.account_id ASC, transactions.type ASC").
+		Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return totals, nil
+}
+
+// PostedAccountBalance returns Debits - Credits of an account over the entries dated up to asOf that
+// belong to active transactions (POSTED, not reversal records), so a transaction being reversed or
+// replaced no longer counts. It accepts the caller's *gorm.DB so it can run inside an existing
+// database transaction and see its uncommitted changes.
+func PostedAccountBalance(db *gorm.DB, accountID uint, asOf time.Time) (decimal.Decimal, error) {
+	var balance decimal.Decimal
+
+	err := db.Model(&models.JournalEntry{}).
+		Select("COALESCE(SUM(CASE WHEN journal_entries.debit_or_credit = 'DEBIT' THEN journal_entries.amount ELSE -journal_entries.amount END), 0)").
+		Joins("JOIN transactions ON transactions.id = journal_entries.transaction_id AND transactions.deleted_at IS NULL").
+		Where("journal_entries.account_id = ? AND journal_entries.entry_date <= ?", accountID, asOf).
+		Scopes(activeTransactions).
+		Scan(&balance).Error
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	return balance, nil
+}
@@ -0,0 +1,232 @@
+package repositories
+
+import (
+	"arabella-api/internal/app/models"
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrReconciliationInProgress is returned when the account already has a reconciliation in progress
+var ErrReconciliationInProgress = errors.New("the account already has a reconciliation in progress")
+
+// ReconciliationRepository defines the interface for reconciliation data access
+type ReconciliationRepository interface {
+	Create(reconciliation *models.Reconciliation) error
+	FindByID(id uint) (*models.Reconciliation, error)
+	FindByAccount(accountID uint) ([]*models.Reconciliation, error)
+	FindInProgress(accountID uint) (*models.Reconciliation, error)
+	FindLastCompleted(accountID uint) (*models.Reconciliation, error)
+	FindUnclearedTransactions(accountID uint, asOf time.Time) ([]ReconciliationCandidate, error)
+	AddItems(items []*models.ReconciliationItem) error
+	RemoveItems(reconciliationID uint, transactionIDs []uint) error
+	Complete(reconciliation *models.Reconciliation) error
+	Delete(reconciliation *models.Reconciliation) error
+}
+
+// ReconciliationCandidate is a transaction that touches an account and was not yet cleared on it
+type ReconciliationCandidate struct {
+	TransactionID   uint
+	TransactionDate time.Time
+	Description     string
+	Net             decimal.Decimal // Debits - Credits posted by the transaction on the account
+}
+
+// reconciliationRepositoryImpl implements ReconciliationRepository using GORM
+type reconciliationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewReconciliationRepository creates a new reconciliation repository
+func NewReconciliationRepository(db *gorm.DB) ReconciliationRepository {
+	return &reconciliationRepositoryImpl{db: db}
+}
+
+// Create creates a new reconciliation
+func (r *reconciliationRepositoryImpl) Create(reconciliation *models.Reconciliation) error {
+	if err := reconciliation.Validate(); err != nil {
+		return err
+	}
+
+	// A concurrent start on the same account loses on idx_reconciliations_account_in_progress
+	result := r.db.Omit("Account", "Items").Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "account_id"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "status = 'IN_PROGRESS' AND deleted_at IS NULL"}}},
+		DoNothing:   true,
+	}).Create(reconciliation)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrReconciliationInProgress
+	}
+
+	return nil
+}
+
+// FindByID finds a reconciliation by ID with its cleared items and their transactions
+func (r *reconciliationRepositoryImpl) FindByID(id uint) (*models.Reconciliation, error) {
+	var reconciliation models.Reconciliation
+
+	err := r.db.
+		Preload("Account").
+		Preload("Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id ASC")
+		}).
+		Preload("Items.Transaction").
+		First(&reconciliation, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("reconciliation not found")
+		}
+		return nil, err
+	}
+
+	return &reconciliation, nil
+}
+
+// FindByAccount finds every reconciliation of an account, most recent statement first
+func (r *reconciliationRepositoryImpl) FindByAccount(accountID uint) ([]*models.Reconciliation, error) {
+	var reconciliations []*models.Reconciliation
+
+	err := r.db.
+		Preload("Account").
+		Preload("Items").
+		Where("account_id = ?", accountID).
+		Order("statement_date DESC, id DESC").
+		Find(&reconciliations).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return reconciliations, nil
+}
+
+// FindInProgress finds the open reconciliation of an account.
+// Returns gorm.ErrRecordNotFound if there is none.
+func (r *reconciliationRepositoryImpl) FindInProgress(accountID uint) (*models.Reconciliation, error) {
+	var reconciliation models.Reconciliation
+
+	err := r.db.
+		Where("account_id = ? AND status = ?", accountID, "IN_PROGRESS").
+		First(&reconciliation).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &reconciliation, nil
+}
+
+// FindLastCompleted finds the completed reconciliation with the latest statement date.
+// Returns gorm.ErrRecordNotFound if the account was never reconciled.
+func (r *reconciliationRepositoryImpl) FindLastCompleted(accountID uint) (*models.Reconciliation, error) {
+	var reconciliation models.Reconciliation
+
+	err := r.db.
+		Where("account_id = ? AND status = ?", accountID, "COMPLETED").
+		Order("statement_date DESC, id DESC").
+		First(&reconciliation).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &reconciliation, nil
+}
+
+// FindUnclearedTransactions finds the transactions dated up to asOf that posted to the account and
+// were not cleared by a completed reconciliation of that account. A transfer can still be cleared on
+// its other account after being reconciled on one of them. Reversed and voided transactions and
+// their reversal records never reach the bank, so they are left out.
+func (r *reconciliationRepositoryImpl) FindUnclearedTransactions(accountID uint, asOf time.Time) ([]ReconciliationCandidate, error) {
+	cleared := r.db.Table("reconciliation_items").
+		Select("reconciliation_items.transaction_id").
+		Joins("JOIN reconciliations ON reconciliations.id = reconciliation_items.reconciliation_id").
+		Where("reconciliations.account_id = ? AND reconciliations.status = ?", accountID, "COMPLETED").
+		Where("reconciliations.deleted_at IS NULL AND reconciliation_items.deleted_at IS NULL")
+
+	var candidates []ReconciliationCandidate
+	err := r.db.Model(&models.JournalEntry{}).
+		Select(`transactions.id AS transaction_id,
+			transactions.transaction_date,
+			transactions.description,
+			SUM(CASE WHEN journal_entries.debit_or_credit = 'DEBIT' THEN journal_entries.amount ELSE -journal_entries.amount END) AS net`).
+		Joins("JOIN transactions ON transactions.id = journal_entries.transaction_id AND transactions.deleted_at IS NULL").
+		Where("journal_entries.account_id = ? AND transactions.transaction_date <= ?", accountID, asOf).
+		Scopes(activeTransactions).
+		Where("transactions.id NOT IN (?)", cleared).
+		Group("transactions.id, transactions.transaction_date, transactions.description").
+		Having("SUM(CASE WHEN journal_entries.debit_or_credit = 'DEBIT' THEN journal_entries.amount ELSE -journal_entries.amount END) <> 0").
+		Order("transactions.transaction_date ASC, transactions.id ASC").
+		Scan(&candidates).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return candidates, nil
+}
+
+// AddItems ticks transactions as cleared
+func (r *reconciliationRepositoryImpl) AddItems(items []*models.ReconciliationItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	return r.db.Omit("Transaction").Create(&items).Error
+}
+
+// RemoveItems unticks transactions (hard delete: the unique index must allow ticking them again)
+func (r *reconciliationRepositoryImpl) RemoveItems(reconciliationID uint, transactionIDs []uint) error {
+	if len(transactionIDs) == 0 {
+		return nil
+	}
+
+	return r.db.Unscoped().
+		Where("reconciliation_id = ? AND transaction_id IN ?", reconciliationID, transactionIDs).
+		Delete(&models.ReconciliationItem{}).Error
+}
+
+// Complete marks the reconciliation as completed and locks its cleared transactions atomically.
+// Transactions already locked by an earlier reconciliation (of the other account of a transfer)
+// keep their original lock.
+func (r *reconciliationRepositoryImpl) Complete(reconciliation *models.Reconciliation) error {
+	if err := reconciliation.Validate(); err != nil {
+		return err
+	}
+
+	transactionIDs := make([]uint, len(reconciliation.Items))
+	for i, item := range reconciliation.Items {
+		transactionIDs[i] = item.TransactionID
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Account", "Items").Save(reconciliation).Error; err != nil {
+			return err
+		}
+
+		if len(transactionIDs) == 0 {
+			return nil
+		}
+
+		return tx.Model(&models.Transaction{}).
+			Where("id IN ? AND reconciliation_id IS NULL", transactionIDs).
+			Updates(map[string]interface{}{
+				"reconciliation_id": reconciliation.ID,
+				"is_reconciled":     true,
+			}).Error
+	})
+}
+
+// Delete discards a reconciliation and its items
+func (r *reconciliationRepositoryImpl) Delete(reconciliation *models.Reconciliation) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("reconciliation_id = ?", reconciliation.ID).Delete(&models.ReconciliationItem{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(reconciliation).Error
+	})
+}
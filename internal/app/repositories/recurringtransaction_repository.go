@@ -0,0 +1,170 @@
+package repositories
+
+import (
+	"arabella-api/internal/app/models"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RecurringTransactionRepository defines the interface for recurring transaction data access
+type RecurringTransactionRepository interface {
+	Create(template *models.RecurringTransaction) error
+	FindByID(id uint) (*models.RecurringTransaction, error)
+	FindByUser(userID uint) ([]*models.RecurringTransaction, error)
+	FindDue(asOf time.Time, userID *uint) ([]*models.RecurringTransaction, error)
+	Update(template *models.RecurringTransaction, rebase func(current *models.RecurringTransaction) error) error
+	Delete(id uint) error
+	FindOccurrences(templateID uint) ([]*models.RecurringOccurrence, error)
+	SaveOverride(override *models.RecurringTransactionOverride) error
+	DeleteOverride(templateID, overrideID uint) error
+}
+
+// recurringTransactionRepositoryImpl implements RecurringTransactionRepository using GORM
+type recurringTransactionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewRecurringTransactionRepository creates a new recurring transaction repository
+func NewRecurringTransactionRepository(db *gorm.DB) RecurringTransactionRepository {
+	return &recurringTransactionRepositoryImpl{db: db}
+}
+
+// Create creates a new recurring transaction
+func (r *recurringTransactionRepositoryImpl) Create(template *models.RecurringTransaction) error {
+	if err := template.Validate(); err != nil {
+		return err
+	}
+
+	return r.db.Omit("AccountFrom", "AccountTo", "Category", "Overrides").Create(template).Error
+}
+
+// FindByID finds a recurring transaction by ID with its accounts, category and overrides
+func (r *recurringTransactionRepositoryImpl) FindByID(id uint) (*models.RecurringTransaction, error) {
+	var template models.RecurringTransaction
+
+	err := r.withRelationships(r.db).First(&template, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("recurring transaction not found")
+		}
+		return nil, err
+	}
+
+	return &template, nil
+}
+
+// FindByUser finds all recurring transactions of a user, next due first (ended schedules last)
+func (r *recurringTransactionRepositoryImpl) FindByUser(userID uint) ([]*models.RecurringTransaction, error) {
+	var templates []*models.RecurringTransaction
+
+	err := r.withRelationships(r.db).
+		Where("user_id = ?", userID).
+		Order("next_occurrence_date ASC NULLS LAST, id ASC").
+		Find(&templates).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+// FindDue finds the active recurring transactions with an occurrence due by asOf,
+// optionally limited to one user
+func (r *recurringTransactionRepositoryImpl) FindDue(asOf time.Time, userID *uint) ([]*models.RecurringTransaction, error) {
+	var templates []*models.RecurringTransaction
+
+	query := r.db.
+		Preload("Overrides").
+		Where("is_active = ? AND next_occurrence_date IS NOT NULL AND next_occurrence_date <= ?", true, asOf)
+	if userID != nil {
+		query = query.Where("user_id = ?", *userID)
+	}
+
+	err := query.Order("next_occurrence_date ASC, id ASC").Find(&templates).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+// Update updates an existing recurring transaction. The stored row is locked and re-read first and
+// handed to rebase, so the template is saved on top of the occurrences a scheduler run posted since
+// it was read (next_occurrence) instead of writing a stale counter back.
+func (r *recurringTransactionRepositoryImpl) Update(template *models.RecurringTransaction, rebase func(current *models.RecurringTransaction) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var current models.RecurringTransaction
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, template.ID).Error; err != nil {
+			return err
+		}
+
+		if err := rebase(&current); err != nil {
+			return err
+		}
+
+		if err := template.Validate(); err != nil {
+			return err
+		}
+
+		return tx.Omit("AccountFrom", "AccountTo", "Category", "Overrides").Save(template).Error
+	})
+}
+
+// Delete soft deletes a recurring transaction; its occurrences and posted transactions are kept
+func (r *recurringTransactionRepositoryImpl) Delete(id uint) error {
+	return r.db.Delete(&models.RecurringTransaction{}, id).Error
+}
+
+// FindOccurrences finds the materialized occurrences of a recurring transaction in schedule order
+func (r *recurringTransactionRepositoryImpl) FindOccurrences(templateID uint) ([]*models.RecurringOccurrence, error) {
+	var occurrences []*models.RecurringOccurrence
+
+	err := r.db.
+		Where("recurring_transaction_id = ?", templateID).
+		Order("occurrence_index ASC").
+		Find(&occurrences).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return occurrences, nil
+}
+
+// SaveOverride creates or replaces the override of an occurrence (one per template and day)
+func (r *recurringTransactionRepositoryImpl) SaveOverride(override *models.RecurringTransactionOverride) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "recurring_transaction_id"}, {Name: "occurrence_date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"amount", "skip", "updated_at"}),
+	}).Create(override).Error
+}
+
+// DeleteOverride removes an override of a recurring transaction (hard delete: the day can be overridden again)
+func (r *recurringTransactionRepositoryImpl) DeleteOverride(templateID, overrideID uint) error {
+	result := r.db.Unscoped().
+		Where("id = ? AND recurring_transaction_id = ?", overrideID, templateID).
+		Delete(&models.RecurringTransactionOverride{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("override not found")
+	}
+
+	return nil
+}
+
+// withRelationships preloads the accounts, category and overrides of recurring transactions
+func (r *recurringTransactionRepositoryImpl) withRelationships(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("AccountFrom.Currency").
+		Preload("AccountTo.Currency").
+		Preload("Category").
+		Preload("Overrides", func(db *gorm.DB) *gorm.DB {
+			return db.Order("occurrence_date ASC")
+		})
+}
@@ -0,0 +1,197 @@
+package repositories
+
+import (
+	"arabella-api/internal/app/models"
+	"errors"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// SavingsGoalRepository defines the interface for savings goal data access
+type SavingsGoalRepository interface {
+	WithUserLock(userID uint, fn func(repo SavingsGoalRepository) error) error
+	Create(goal *models.SavingsGoal, accountIDs []uint) error
+	FindByID(id uint) (*models.SavingsGoal, error)
+	FindByUser(userID uint) ([]*models.SavingsGoal, error)
+	Update(goal *models.SavingsGoal) error
+	Delete(id uint) error
+	FindAccountLink(accountID uint) (*models.SavingsGoalAccount, error)
+	LinkAccount(goalID, accountID uint) error
+	UnlinkAccount(goalID, accountID uint) error
+	AddContribution(contribution *models.SavingsGoalContribution) error
+	GetEarmarked(accountID uint, goalID *uint) (decimal.Decimal, error)
+}
+
+// savingsGoalRepositoryImpl implements SavingsGoalRepository using GORM
+type savingsGoalRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewSavingsGoalRepository creates a new savings goal repository
+func NewSavingsGoalRepository(db *gorm.DB) SavingsGoalRepository {
+	return &savingsGoalRepositoryImpl{db: db}
+}
+
+// WithUserLock runs fn in a database transaction that holds the lock on the user's row, so checks
+// against the earmarks of the user's accounts are not interleaved with another contribution of the
+// same user. Reads and writes through the repository passed to fn join the transaction.
+func (r *savingsGoalRepositoryImpl) WithUserLock(userID uint, fn func(repo SavingsGoalRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockUser(tx, userID); err != nil {
+			return err
+		}
+
+		return fn(&savingsGoalRepositoryImpl{db: tx})
+	})
+}
+
+// Create creates a new savings goal and links the given accounts to it in one database transaction
+func (r *savingsGoalRepositoryImpl) Create(goal *models.SavingsGoal, accountIDs []uint) error {
+	if err := goal.Validate(); err != nil {
+		return err
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Accounts", "Contributions").Create(goal).Error; err != nil {
+			return err
+		}
+
+		for _, accountID := range accountIDs {
+			link := &models.SavingsGoalAccount{SavingsGoalID: goal.ID, AccountID: accountID}
+			if err := tx.Omit("Account").Create(link).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// FindByID finds a savings goal by ID with its linked accounts and contributions
+func (r *savingsGoalRepositoryImpl) FindByID(id uint) (*models.SavingsGoal, error) {
+	var goal models.SavingsGoal
+
+	err := r.preloadAll(r.db).First(&goal, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("savings goal not found")
+		}
+		return nil, err
+	}
+
+	return &goal, nil
+}
+
+// FindByUser finds all savings goals of a user with their linked accounts and contributions,
+// the closest target date first (goals without one last)
+func (r *savingsGoalRepositoryImpl) FindByUser(userID uint) ([]*models.SavingsGoal, error) {
+	var goals []*models.SavingsGoal
+
+	err := r.preloadAll(r.db).
+		Where("user_id = ?", userID).
+		Order("target_date ASC NULLS LAST, id ASC").
+		Find(&goals).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return goals, nil
+}
+
+// Update updates an existing savings goal (links and contributions are managed separately)
+func (r *savingsGoalRepositoryImpl) Update(goal *models.SavingsGoal) error {
+	if err := goal.Validate(); err != nil {
+		return err
+	}
+
+	return r.db.Omit("Accounts", "Contributions").Save(goal).Error
+}
+
+// Delete soft deletes a savings goal with its contributions, releasing every earmark,
+// and removes its account links so the accounts can be linked to another goal
+func (r *savingsGoalRepositoryImpl) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("savings_goal_id = ?", id).Delete(&models.SavingsGoalAccount{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("savings_goal_id = ?", id).Delete(&models.SavingsGoalContribution{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&models.SavingsGoal{}, id).Error
+	})
+}
+
+// FindAccountLink finds the goal link of an account.
+// Returns gorm.ErrRecordNotFound if the account is not linked to any goal.
+func (r *savingsGoalRepositoryImpl) FindAccountLink(accountID uint) (*models.SavingsGoalAccount, error) {
+	var link models.SavingsGoalAccount
+
+	err := r.db.Where("account_id = ?", accountID).First(&link).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &link, nil
+}
+
+// LinkAccount links an account in full to a savings goal
+func (r *savingsGoalRepositoryImpl) LinkAccount(goalID, accountID uint) error {
+	link := &models.SavingsGoalAccount{SavingsGoalID: goalID, AccountID: accountID}
+	return r.db.Omit("Account").Create(link).Error
+}
+
+// UnlinkAccount removes the link between an account and a savings goal
+func (r *savingsGoalRepositoryImpl) UnlinkAccount(goalID, accountID uint) error {
+	result := r.db.Unscoped().
+		Where("savings_goal_id = ? AND account_id = ?", goalID, accountID).
+		Delete(&models.SavingsGoalAccount{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("account is not linked to the savings goal")
+	}
+
+	return nil
+}
+
+// AddContribution records an earmark (or a release, when negative)
+func (r *savingsGoalRepositoryImpl) AddContribution(contribution *models.SavingsGoalContribution) error {
+	if err := contribution.Validate(); err != nil {
+		return err
+	}
+
+	return r.db.Omit("Account").Create(contribution).Error
+}
+
+// GetEarmarked returns the amount of an account's balance earmarked by savings goals:
+// every goal when goalID is nil, otherwise only that goal
+func (r *savingsGoalRepositoryImpl) GetEarmarked(accountID uint, goalID *uint) (decimal.Decimal, error) {
+	query := r.db.Model(&models.SavingsGoalContribution{}).Where("account_id = ?", accountID)
+	if goalID != nil {
+		query = query.Where("savings_goal_id = ?", *goalID)
+	}
+
+	var total decimal.Decimal
+	err := query.Select("COALESCE(SUM(amount), 0)").Scan(&total).Error
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	return total, nil
+}
+
+// preloadAll preloads the linked accounts and the contributions (oldest first) with their accounts
+func (r *savingsGoalRepositoryImpl) preloadAll(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Accounts.Account").
+		Preload("Contributions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("contribution_date ASC, id ASC")
+		}).
+		Preload("Contributions.Account")
+}
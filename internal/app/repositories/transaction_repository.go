@@ -14,12 +14,27 @@ import (
 type TransactionRepository interface {
 	Create(tx *models.Transaction) error
 	FindByID(id uint) (*models.Transaction, error)
+	FindByIDs(ids []uint) ([]*models.Transaction, error)
 	FindByUser(userID uint, filters dtos.TransactionFilters) ([]*models.Transaction, int64, error)
 	FindByAccount(accountID uint) ([]*models.Transaction, error)
 	FindByDateRange(userID uint, startDate, endDate time.Time) ([]*models.Transaction, error)
+	FindPending(userID uint, until time.Time) ([]*models.Transaction, error)
+	FindDuePending(asOf time.Time, userID *uint) ([]*models.Transaction, error)
 	Update(tx *models.Transaction) error
+	UpdateDetails(id uint, description, notes string) error
 	Delete(id uint) error
 	GetMonthlyStats(userID uint, month, year int) (income, expenses decimal.Decimal, count int64, err error)
+	GetMonthlyDebtPayments(userID uint, month, year int) (decimal.Decimal, error)
+}
+
+// ErrTransactionNotEditable is returned when a transaction stopped being editable in place
+// (reversed, replaced or reconciled) between reading it and writing its details
+var ErrTransactionNotEditable = errors.New("transaction was reversed, replaced or reconciled by another request; reload it and retry")
+
+// activeTransactions limits a query to transactions in effect: reversed and voided transactions,
+// as well as the reversal records that cancel them, are left out of listings and statistics
+func activeTransactions(db *gorm.DB) *gorm.DB {
+	return db.Where("transactions.status = ? AND transactions.reverses_transaction_id IS NULL", "POSTED")
 }
 
 // transactionRepositoryImpl implements TransactionRepository using GORM
@@ -49,6 +64,8 @@ func (r *transactionRepositoryImpl) FindByID(id uint) (*models.Transaction, erro
 		Preload("AccountFrom.Currency").
 		Preload("AccountTo.Currency").
 		Preload("Category").
+		Preload("Splits.Category").
+		Preload("Splits.Account").
 		First(&tx, id).Error
 
 	if err != nil {
@@ -61,6 +78,30 @@ func (r *transactionRepositoryImpl) FindByID(id uint) (*models.Transaction, erro
 	return &tx, nil
 }
 
+// FindByIDs finds several transactions by ID with all relationships preloaded, in ID order
+func (r *transactionRepositoryImpl) FindByIDs(ids []uint) ([]*models.Transaction, error) {
+	var transactions []*models.Transaction
+	if len(ids) == 0 {
+		return transactions, nil
+	}
+
+	err := r.db.
+		Preload("AccountFrom.Currency").
+		Preload("AccountTo.Currency").
+		Preload("Category").
+		Preload("Splits.Category").
+		Preload("Splits.Account").
+		Where("id IN ?", ids).
+		Order("id ASC").
+		Find(&transactions).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
 // FindByUser finds transactions for a user with filters and pagination
 func (r *transactionRepositoryImpl) FindByUser(userID uint, filters dtos.TransactionFilters) ([]*models.Transaction, int64, error) {
 	var transactions []*models.Transaction
@@ -69,6 +110,12 @@ func (r *transactionRepositoryImpl) FindByUser(userID uint, filters dtos.Transac
 	query := r.db.Model(&models.Transaction{}).Where("user_id = ?", userID)
 
 	// Apply filters
+	if filters.Status != "" {
+		query = query.Where("status = ? AND reverses_transaction_id IS NULL", filters.Status)
+	} else {
+		query = query.Scopes(activeTransactions)
+	}
+
 	if filters.Type != "" {
 		query = query.Where("type = ?", filters.Type)
 	}
@@ -119,6 +166,8 @@ func (r *transactionRepositoryImpl) FindByUser(userID uint, filters dtos.Transac
 		Preload("AccountFrom.Currency").
 		Preload("AccountTo.Currency").
 		Preload("Category").
+		Preload("Splits.Category").
+		Preload("Splits.Account").
 		Order("transaction_date DESC, created_at DESC").
 		Limit(pageSize).
 		Offset(offset).
@@ -139,6 +188,9 @@ func (r *transactionRepositoryImpl) FindByAccount(accountID uint) ([]*models.Tra
 		Preload("AccountFrom.Currency").
 		Preload("AccountTo.Currency").
 		Preload("Category").
+		Preload("Splits.Category").
+		Preload("Splits.Account").
+		Scopes(activeTransactions).
 		Where("account_from_id = ? OR account_to_id = ?", accountID, accountID).
 		Order("transaction_date DESC").
 		Find(&transactions).Error
@@ -156,9 +208,12 @@ func (r *transactionRepositoryImpl) FindByDateRange(userID uint, startDate, endD
 
 	err := r.db.
 		Where("user_id = ? AND transaction_date >= ? AND transaction_date <= ?", userID, startDate, endDate).
+		Scopes(activeTransactions).
 		Preload("AccountFrom.Currency").
 		Preload("AccountTo.Currency").
 		Preload("Category").
+		Preload("Splits.Category").
+		Preload("Splits.Account").
 		Order("transaction_date ASC").
 		Find(&transactions).Error
 
@@ -169,6 +224,46 @@ func (r *transactionRepositoryImpl) FindByDateRange(userID uint, startDate, endD
 	return transactions, nil
 }
 
+// FindPending finds the pending transactions of a user dated up to until (confirmation-bound ones included),
+// oldest first
+func (r *transactionRepositoryImpl) FindPending(userID uint, until time.Time) ([]*models.Transaction, error) {
+	var transactions []*models.Transaction
+
+	err := r.db.
+		Preload("AccountFrom.Currency").
+		Preload("AccountTo.Currency").
+		Preload("Category").
+		Preload("Splits.Category").
+		Preload("Splits.Account").
+		Where("user_id = ? AND status = ? AND transaction_date <= ?", userID, "PENDING", until).
+		Order("transaction_date ASC, id ASC").
+		Find(&transactions).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// FindDuePending finds the pending transactions dated up to asOf that do not wait for a confirmation,
+// optionally limited to one user
+func (r *transactionRepositoryImpl) FindDuePending(asOf time.Time, userID *uint) ([]*models.Transaction, error) {
+	var transactions []*models.Transaction
+
+	query := r.db.Where("status = ? AND requires_confirmation = ? AND transaction_date <= ?", "PENDING", false, asOf)
+	if userID != nil {
+		query = query.Where("user_id = ?", *userID)
+	}
+
+	err := query.Order("transaction_date ASC, id ASC").Find(&transactions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
 // Update updates an existing transaction
 func (r *transactionRepositoryImpl) Update(tx *models.Transaction) error {
 	if err := tx.Validate(); err != nil {
@@ -178,6 +273,26 @@ func (r *transactionRepositoryImpl) Update(tx *models.Transaction) error {
 	return r.db.Save(tx).Error
 }
 
+// UpdateDetails writes the description and notes of a posted transaction, leaving every other
+// column alone. The write only applies while the transaction is still POSTED, not replaced and
+// not reconciled, so it cannot undo a reversal, replacement or reconciliation that won the race.
+func (r *transactionRepositoryImpl) UpdateDetails(id uint, description, notes string) error {
+	result := r.db.Model(&models.Transaction{}).
+		Where("id = ? AND status = ? AND replaced_by_transaction_id IS NULL AND reconciliation_id IS NULL", id, "POSTED").
+		Updates(map[string]interface{}{
+			"description": description,
+			"notes":       notes,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTransactionNotEditable
+	}
+
+	return nil
+}
+
 // Delete soft deletes a transaction
 func (r *transactionRepositoryImpl) Delete(id uint) error {
 	return r.db.Delete(&models.Transaction{}, id).Error
@@ -192,6 +307,7 @@ func (r *transactionRepositoryImpl) GetMonthlyStats(userID uint, month, year int
 	// Get total income
 	var incomeTotal decimal.Decimal
 	err = r.db.Model(&models.Transaction{}).
+		Scopes(activeTransactions).
 		Select("COALESCE(SUM(amount), 0)").
 		Where("user_id = ? AND type = ? AND transaction_date >= ? AND transaction_date <= ?",
 			userID, "INCOME", startDate, endDate).
@@ -204,6 +320,7 @@ func (r *transactionRepositoryImpl) GetMonthlyStats(userID uint, month, year int
 	// Get total expenses
 	var expensesTotal decimal.Decimal
 	err = r.db.Model(&models.Transaction{}).
+		Scopes(activeTransactions).
 		Select("COALESCE(SUM(amount), 0)").
 		Where("user_id = ? AND type = ? AND transaction_date >= ? AND transaction_date <= ?",
 			userID, "EXPENSE", startDate, endDate).
@@ -215,6 +332,7 @@ func (r *transactionRepositoryImpl) GetMonthlyStats(userID uint, month, year int
 
 	// Get transaction count
 	err = r.db.Model(&models.Transaction{}).
+		Scopes(activeTransactions).
 		Where("user_id = ? AND transaction_date >= ? AND transaction_date <= ?",
 			userID, startDate, endDate).
 		Count(&count).Error
@@ -225,3 +343,24 @@ func (r *transactionRepositoryImpl) GetMonthlyStats(userID uint, month, year int
 
 	return incomeTotal, expensesTotal, count, nil
 }
+
+// GetMonthlyDebtPayments calculates the total paid towards liabilities (DEBT_PAYMENT) in a month.
+// Debt payments are kept out of GetMonthlyStats so they are never counted as expenses.
+func (r *transactionRepositoryImpl) GetMonthlyDebtPayments(userID uint, month, year int) (decimal.Decimal, error) {
+	startDate := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	endDate := startDate.AddDate(0, 1, 0).Add(-time.Second)
+
+	var total decimal.Decimal
+	err := r.db.Model(&models.Transaction{}).
+		Scopes(activeTransactions).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("user_id = ? AND type = ? AND transaction_date >= ? AND transaction_date <= ?",
+			userID, "DEBT_PAYMENT", startDate, endDate).
+		Scan(&total).Error
+
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	return total, nil
+}
@@ -3,8 +3,10 @@ package repositories
 import (
 	"arabella-api/internal/app/models"
 	"errors"
+	"fmt"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // UserRepository defines the interface for user data access
@@ -114,3 +116,18 @@ func (r *userRepositoryImpl) Delete(id uint) error {
 func (r *userRepositoryImpl) UpdateLastLogin(id uint) error {
 	return r.db.Model(&models.User{}).Where("id = ?", id).Update("last_login_at", gorm.Expr("NOW()")).Error
 }
+
+// lockUser locks the user's row in NO KEY UPDATE mode until the end of the caller's database
+// transaction. It serializes the read-check-write sequences that must not interleave for one user
+// (e.g. allocating funds) without blocking inserts that only reference the user.
+func lockUser(db *gorm.DB, userID uint) error {
+	var user models.User
+	err := db.Clauses(clause.Locking{Strength: "NO KEY UPDATE"}).
+		Select("id").
+		First(&user, userID).Error
+	if err != nil {
+		return fmt.Errorf("failed to lock user %d: %w", userID, err)
+	}
+
+	return nil
+}
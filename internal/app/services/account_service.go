@@ -4,47 +4,69 @@ import (
 	"arabella-api/internal/app/dtos"
 	"arabella-api/internal/app/models"
 	"arabella-api/internal/app/repositories"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/shopspring/decimal"
 )
 
+// ErrDirectBalanceWrite is returned when a client tries to write Account.Balance directly
+var ErrDirectBalanceWrite = errors.New("balance cannot be set directly; use POST /accounts/{id}/balance-adjustment")
+
+// ErrAccountVersionConflict is returned when an update carries a stale account version
+var ErrAccountVersionConflict = repositories.ErrAccountVersionConflict
+
 // AccountService handles account-related business logic
 type AccountService interface {
 	Create(req *dtos.CreateAccountDTO) (*models.Account, error)
 	GetByID(id uint) (*dtos.AccountResponseDTO, error)
 	GetByUserID(userID uint) ([]dtos.AccountResponseDTO, error)
 	Update(id uint, req *dtos.UpdateAccountDTO) error
+	AdjustBalance(userID, id uint, req *dtos.BalanceAdjustmentDTO) (*models.Transaction, error)
+	GetAmortizationSchedule(userID, id uint) (*dtos.AmortizationScheduleResponse, error)
+	SetCreditCardTerms(userID, id uint, req *dtos.CreditCardTermsDTO) (*dtos.AccountResponseDTO, error)
+	GetStatement(userID, id uint) (*dtos.CreditCardStatementResponse, error)
 	Delete(id uint) error
 }
 
 type accountService struct {
-	accountRepo     repositories.AccountRepository
-	systemValueRepo repositories.SystemValueRepository
+	accountRepo      repositories.AccountRepository
+	systemValueRepo  repositories.SystemValueRepository
+	journalEntryRepo repositories.JournalEntryRepository
+	accountingEngine AccountingEngineService
 }
 
 // NewAccountService creates a new account service
 func NewAccountService(
 	accountRepo repositories.AccountRepository,
 	systemValueRepo repositories.SystemValueRepository,
+	journalEntryRepo repositories.JournalEntryRepository,
+	accountingEngine AccountingEngineService,
 ) AccountService {
 	return &accountService{
-		accountRepo:     accountRepo,
-		systemValueRepo: systemValueRepo,
+		accountRepo:      accountRepo,
+		systemValueRepo:  systemValueRepo,
+		journalEntryRepo: journalEntryRepo,
+		accountingEngine: accountingEngine,
 	}
 }
 
 // Create creates a new account
+// A non-zero balance is posted as an OPENING_BALANCE transaction against the user's equity account
 func (s *accountService) Create(req *dtos.CreateAccountDTO) (*models.Account, error) {
 	// Validate account type against SystemValue
 	if err := s.validateAccountType(req.AccountType); err != nil {
 		return nil, err
 	}
 
-	// Set default balance if not provided
-	balance := req.Balance
-	if balance.IsZero() {
-		balance = decimal.Zero
+	openingDate := time.Now()
+	if req.OpeningDate != "" {
+		parsed, err := time.Parse(time.RFC3339, req.OpeningDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid opening_date: %w", err)
+		}
+		openingDate = parsed
 	}
 
 	account := &models.Account{
@@ -52,11 +74,38 @@ func (s *accountService) Create(req *dtos.CreateAccountDTO) (*models.Account, er
 		Name:        req.Name,
 		AccountType: req.AccountType,
 		CurrencyID:  req.CurrencyID,
-		Balance:     balance,
+		Balance:     decimal.Zero,
 		IsActive:    true,
 	}
 
-	if err := s.accountRepo.Create(account); err != nil {
+	// A new loan is owed in full: its opening balance defaults to the principal
+	openingBalance := req.Balance
+	if req.Loan != nil {
+		loan, err := newLoanFromRequest(req.Loan, openingDate)
+		if err != nil {
+			return nil, err
+		}
+		account.Loan = loan
+
+		if openingBalance.IsZero() {
+			openingBalance = loan.Principal
+		}
+	}
+
+	if req.CreditCard != nil {
+		if req.AccountType != "CREDIT_CARD" {
+			return nil, fmt.Errorf("credit card terms are only supported for CREDIT_CARD accounts, got %s", req.AccountType)
+		}
+
+		card := newCreditCardFromRequest(req.CreditCard)
+		if err := card.Validate(); err != nil {
+			return nil, err
+		}
+		account.CreditCard = card
+	}
+
+	// Create the account (with its loan or card terms) and post its opening balance atomically
+	if err := s.accountingEngine.OpenAccount(account, openingBalance, openingDate); err != nil {
 		return nil, err
 	}
 
@@ -64,8 +113,52 @@ func (s *accountService) Create(req *dtos.CreateAccountDTO) (*models.Account, er
 	return s.accountRepo.FindByID(account.ID)
 }
 
+// newLoanFromRequest builds the Loan terms of a new LOAN or MORTGAGE account.
+// The loan starts on openingDate unless the request sets its own start date.
+func newLoanFromRequest(req *dtos.CreateLoanDTO, openingDate time.Time) (*models.Loan, error) {
+	loan := &models.Loan{
+		Principal:          req.Principal,
+		InterestRate:       req.InterestRate,
+		TermMonths:         req.TermMonths,
+		PaymentDay:         req.PaymentDay,
+		StartDate:          openingDate,
+		InterestCategoryID: req.InterestCategoryID,
+	}
+
+	if req.StartDate != "" {
+		startDate, err := parseScheduleDate(req.StartDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid loan start_date: %w", err)
+		}
+		loan.StartDate = startDate
+	}
+
+	if err := loan.Validate(); err != nil {
+		return nil, err
+	}
+
+	return loan, nil
+}
+
+// newCreditCardFromRequest builds the statement terms of a CREDIT_CARD account
+func newCreditCardFromRequest(req *dtos.CreditCardTermsDTO) *models.CreditCard {
+	return &models.CreditCard{
+		CreditLimit:           req.CreditLimit,
+		StatementClosingDay:   req.StatementClosingDay,
+		PaymentDueDay:         req.PaymentDueDay,
+		MinimumPaymentPercent: req.MinimumPaymentPercent,
+		MinimumPaymentAmount:  req.MinimumPaymentAmount,
+		InterestRate:          req.InterestRate,
+	}
+}
+
 // validateAccountType validates the account type against system values
+// CATEGORY (nominal accounts of categories) and EQUITY accounts are system-managed, never created directly
 func (s *accountService) validateAccountType(accountType string) error {
+	if accountType == "CATEGORY" || accountType == "EQUITY" {
+		return fmt.Errorf("account type %s is managed automatically by the ledger", accountType)
+	}
+
 	_, err := s.systemValueRepo.FindByCatalogTypeAndValue("ACCOUNT_TYPE", accountType)
 	if err != nil {
 		return fmt.Errorf("invalid account type: %s", accountType)
@@ -106,19 +199,49 @@ func (s *accountService) Update(id uint, req *dtos.UpdateAccountDTO) error {
 		return err
 	}
 
+	if account.IsSystemManaged() {
+		return errors.New("system ledger accounts cannot be modified")
+	}
+
+	if req.Balance != nil {
+		return ErrDirectBalanceWrite
+	}
+
+	// The client's copy is stale: the account (or its balance) changed since it was read.
+	// The version is optional on purpose, so clients written before it existed keep working: without it
+	// the update is last-write-wins on the fields it sends, but the repository still rejects it if the
+	// account changes between this read and the write.
+	if req.Version != nil && *req.Version != account.Version {
+		return ErrAccountVersionConflict
+	}
+
 	// Apply updates
 	if req.Name != nil {
 		account.Name = *req.Name
 	}
 	if req.AccountType != nil {
+		if err := s.validateAccountType(*req.AccountType); err != nil {
+			return err
+		}
+		// Loan terms are set when the account is opened and belong to it for good
+		if *req.AccountType != account.AccountType && (account.IsLoan() || models.IsLoanType(*req.AccountType)) {
+			return fmt.Errorf("account type cannot be changed from %s to %s; open a new account instead", account.AccountType, *req.AccountType)
+		}
+		if *req.AccountType != account.AccountType && account.CreditCard != nil {
+			return fmt.Errorf("account type cannot be changed from %s while it has credit card terms", account.AccountType)
+		}
+		wasDebitNormal := account.IsDebitNormal()
 		account.AccountType = *req.AccountType
+		account.Classification = models.DefaultClassification(account.AccountType)
+		// Moving between an asset and a liability flips the normal side, so the cached
+		// balance flips sign to keep agreeing with the journal
+		if account.IsDebitNormal() != wasDebitNormal {
+			account.Balance = account.Balance.Neg()
+		}
 	}
 	if req.CurrencyID != nil {
 		account.CurrencyID = req.CurrencyID
 	}
-	if req.Balance != nil {
-		account.Balance = *req.Balance
-	}
 	if req.IsActive != nil {
 		account.IsActive = *req.IsActive
 	}
@@ -126,6 +249,193 @@ func (s *accountService) Update(id uint, req *dtos.UpdateAccountDTO) error {
 	return s.accountRepo.Update(account)
 }
 
+// AdjustBalance sets an account owned by the user to a new balance by posting an ADJUSTMENT
+// transaction for the difference against equity. Returns nil if the balance is unchanged.
+func (s *accountService) AdjustBalance(userID, id uint, req *dtos.BalanceAdjustmentDTO) (*models.Transaction, error) {
+	account, err := s.accountRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if account.UserID != userID {
+		return nil, errors.New("account not found")
+	}
+
+	date := time.Now()
+	if req.Date != "" {
+		parsed, err := time.Parse(time.RFC3339, req.Date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date: %w", err)
+		}
+		date = parsed
+	}
+
+	return s.accountingEngine.AdjustAccountBalance(account.ID, req.Balance, date, req.Notes)
+}
+
+// GetAmortizationSchedule returns the full amortization schedule of a LOAN or MORTGAGE account owned by the user
+func (s *accountService) GetAmortizationSchedule(userID, id uint) (*dtos.AmortizationScheduleResponse, error) {
+	account, err := s.accountRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if account.UserID != userID {
+		return nil, errors.New("account not found")
+	}
+
+	if !account.IsLoan() || account.Loan == nil {
+		return nil, fmt.Errorf("account %q is not a loan (type %s)", account.Name, account.AccountType)
+	}
+
+	loan := account.Loan
+	schedule := &dtos.AmortizationScheduleResponse{
+		AccountID:      account.ID,
+		AccountName:    account.Name,
+		AccountType:    account.AccountType,
+		Loan:           *dtos.ToLoanResponse(loan),
+		CurrentBalance: account.Balance,
+		Installments:   make([]dtos.AmortizationInstallment, 0, loan.TermMonths),
+	}
+
+	if _, due, ok := loan.NextDueDate(time.Now()); ok {
+		schedule.NextDueDate = &due
+	}
+
+	// Fixed installment; the last one settles whatever rounding left over
+	payment := loan.Installment()
+	balance := loan.Principal
+	for n := 1; n <= loan.TermMonths; n++ {
+		interest := loan.InterestFor(balance)
+		principal := payment.Sub(interest)
+		if n == loan.TermMonths || principal.GreaterThan(balance) {
+			principal = balance
+		}
+		balance = balance.Sub(principal)
+
+		schedule.Installments = append(schedule.Installments, dtos.AmortizationInstallment{
+			Number:    n,
+			DueDate:   loan.DueDate(n),
+			Payment:   principal.Add(interest),
+			Principal: principal,
+			Interest:  interest,
+			Balance:   balance,
+		})
+		schedule.TotalPayments = schedule.TotalPayments.Add(principal.Add(interest))
+		schedule.TotalInterest = schedule.TotalInterest.Add(interest)
+	}
+
+	return schedule, nil
+}
+
+// SetCreditCardTerms sets (or replaces) the statement terms of a CREDIT_CARD account owned by the user
+func (s *accountService) SetCreditCardTerms(userID, id uint, req *dtos.CreditCardTermsDTO) (*dtos.AccountResponseDTO, error) {
+	account, err := s.accountRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if account.UserID != userID {
+		return nil, errors.New("account not found")
+	}
+
+	if account.AccountType != "CREDIT_CARD" {
+		return nil, fmt.Errorf("account %q is not a credit card (type %s)", account.Name, account.AccountType)
+	}
+
+	card := newCreditCardFromRequest(req)
+	card.AccountID = account.ID
+	if account.CreditCard != nil {
+		card.Model = account.CreditCard.Model
+	}
+
+	if err := s.accountRepo.SaveCreditCard(card); err != nil {
+		return nil, err
+	}
+
+	account.CreditCard = card
+	return dtos.ToAccountResponse(account), nil
+}
+
+// GetStatement returns the current statement cycle of a CREDIT_CARD account owned by the user
+func (s *accountService) GetStatement(userID, id uint) (*dtos.CreditCardStatementResponse, error) {
+	account, err := s.accountRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if account.UserID != userID {
+		return nil, errors.New("account not found")
+	}
+
+	if account.AccountType != "CREDIT_CARD" || account.CreditCard == nil {
+		return nil, fmt.Errorf("account %q has no credit card terms; set them with PUT /accounts/{id}/credit-card", account.Name)
+	}
+
+	return buildCardStatement(s.journalEntryRepo, account, time.Now())
+}
+
+// buildCardStatement computes the statement cycle of a credit card as of date from its journal entries.
+// The last statement billed the balance at the end of its closing day; everything debited to the card
+// since (payments, refunds, reversals) reduces what is left of it before the unbilled charges.
+func buildCardStatement(journalEntryRepo repositories.JournalEntryRepository, account *models.Account, date time.Time) (*dtos.CreditCardStatementResponse, error) {
+	card := account.CreditCard
+	today := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+
+	closing := card.LastClosingDate(today)
+	cutoff := closing.AddDate(0, 0, 1)
+
+	// Microsecond precision matches PostgreSQL timestamps
+	endOfClosingDay := cutoff.Add(-time.Microsecond)
+	net, err := journalEntryRepo.GetAccountBalance(account.ID, &endOfClosingDay)
+	if err != nil {
+		return nil, err
+	}
+	statementBalance := account.BalanceDelta("DEBIT", net)
+
+	activity, err := journalEntryRepo.GetAccountActivitySince(account.ID, cutoff)
+	if err != nil {
+		return nil, err
+	}
+
+	balance := statementBalance.Add(activity.TotalCredits).Sub(activity.TotalDebits)
+	statementDue := decimal.Max(statementBalance.Sub(activity.TotalDebits), decimal.Zero)
+	minimumDue := decimal.Max(card.MinimumPayment(statementBalance).Sub(activity.TotalDebits), decimal.Zero)
+
+	statement := &dtos.CreditCardStatementResponse{
+		AccountID:              account.ID,
+		AccountName:            account.Name,
+		CreditCard:             *dtos.ToCreditCardResponse(card),
+		Balance:                balance,
+		AvailableCredit:        decimal.Max(card.CreditLimit.Sub(balance), decimal.Zero),
+		OverLimit:              balance.GreaterThan(card.CreditLimit),
+		StatementDate:          closing,
+		StatementBalance:       statementBalance,
+		PaymentsSinceStatement: activity.TotalDebits,
+		StatementDue:           statementDue,
+		MinimumPaymentDue:      minimumDue,
+		PaymentDueDate:         card.DueDateFor(closing),
+		UnbilledBalance:        balance.Sub(statementDue),
+		NextStatementDate:      card.NextClosingDate(closing),
+	}
+
+	statement.NextDueDate = statement.PaymentDueDate
+	if statement.NextDueDate.Before(today) {
+		statement.NextDueDate = card.DueDateFor(statement.NextStatementDate)
+	}
+
+	switch {
+	case !statementDue.IsPositive():
+		statement.Status = "PAID"
+	case minimumDue.IsPositive() && today.After(statement.PaymentDueDate):
+		statement.Status = "OVERDUE"
+	default:
+		statement.Status = "DUE"
+	}
+
+	return statement, nil
+}
+
 // Delete soft deletes an account
 func (s *accountService) Delete(id uint) error {
 	// TODO: Check if account has transactions before deleting
@@ -135,6 +445,10 @@ func (s *accountService) Delete(id uint) error {
 		return err
 	}
 
+	if account.IsSystemManaged() {
+		return errors.New("system ledger accounts cannot be deleted")
+	}
+
 	account.IsActive = false
 	return s.accountRepo.Update(account)
 }
@@ -5,10 +5,13 @@ import (
 	"arabella-api/internal/app/repositories"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // AccountingEngineService is the CORE of the entire system
@@ -16,7 +19,16 @@ import (
 // Every transaction MUST go through this engine to maintain data integrity
 type AccountingEngineService interface {
 	ProcessTransaction(tx *models.Transaction) error
+	ProcessTransactions(txs []*models.Transaction, allOrNothing bool) ([]error, error)
 	ReverseTransaction(transactionID uint) error
+	ReplaceTransaction(originalID uint, replacement *models.Transaction) error
+	PostPendingTransaction(transactionID uint, transactionDate *time.Time) (*models.Transaction, error)
+	UpdatePendingTransaction(tx *models.Transaction) error
+	PreviewBalanceChanges(txs []*models.Transaction) (map[uint]decimal.Decimal, error)
+	PostRecurringOccurrence(template *models.RecurringTransaction, occurrence *models.RecurringOccurrence, tx *models.Transaction) error
+	OpenAccount(account *models.Account, openingBalance decimal.Decimal, openingDate time.Time) error
+	AdjustAccountBalance(accountID uint, newBalance decimal.Decimal, date time.Time, notes string) (*models.Transaction, error)
+	BackfillOpeningBalance(accountID uint) (*models.Transaction, error)
 	VerifyTransactionBalance(transactionID uint) (bool, error)
 }
 
@@ -57,40 +69,90 @@ func (s *accountingEngineService) ProcessTransaction(tx *models.Transaction) err
 
 	// Step 2: Start a database transaction (everything or nothing)
 	return s.db.Transaction(func(dbTx *gorm.DB) error {
-		// Step 2a: Save the transaction first to get its ID
-		if err := dbTx.Create(tx).Error; err != nil {
-			return fmt.Errorf("failed to create transaction: %w", err)
-		}
+		return s.processTransaction(dbTx, tx)
+	})
+}
 
-		// Step 3: Generate journal entries based on transaction type
-		entries, err := s.generateJournalEntries(tx)
-		if err != nil {
-			return fmt.Errorf("failed to generate journal entries: %w", err)
-		}
+// processTransaction posts an already validated transaction inside an open database transaction.
+// It is shared by ProcessTransaction and ReplaceTransaction so both run the exact same posting rules.
+// A PENDING transaction is saved and checked against the same rules, but its journal entries are only
+// written (and balances changed) once it is posted by PostPendingTransaction.
+func (s *accountingEngineService) processTransaction(dbTx *gorm.DB, tx *models.Transaction) error {
+	if tx.Status == "" {
+		tx.Status = "POSTED"
+	}
 
-		// Step 4: Validate that debits = credits
-		if err := s.validateBalance(entries); err != nil {
-			return fmt.Errorf("balance validation failed: %w", err)
+	// Step 2a: Entries cannot be dated inside a closed accounting period
+	// (pending transactions are checked when they are posted)
+	if !tx.IsPending() {
+		if err := s.ensurePeriodOpen(dbTx, tx.UserID, tx.TransactionDate); err != nil {
+			return err
 		}
+	}
 
-		// Step 5: Save journal entries
-		if err := dbTx.Create(&entries).Error; err != nil {
-			return fmt.Errorf("failed to save journal entries: %w", err)
+	// Step 2b: Checks that require the involved accounts. Only OPENING_BALANCE and ADJUSTMENT,
+	// which the engine builds itself, may post to a system-managed (equity) account.
+	if !tx.IsEngineOnlyType() {
+		if err := s.validateTransactionAccounts(dbTx, tx); err != nil {
+			return err
 		}
-
-		// Step 6: Update real account balances directly from the transaction.
-		// This avoids the CategoryID/AccountID ambiguity that exists in journal entries,
-		// where INCOME/EXPENSE entries use CategoryID as a virtual "AccountID".
-		if err := s.updateRealAccountBalances(dbTx, tx); err != nil {
-			return fmt.Errorf("failed to update account balances: %w", err)
+	}
+	if tx.Type == "DEBT_PAYMENT" {
+		if err := s.validateDebtPayment(dbTx, tx); err != nil {
+			return err
 		}
+	}
+
+	// Step 2c: Save the transaction first to get its ID
+	if err := dbTx.Create(tx).Error; err != nil {
+		return fmt.Errorf("failed to create transaction: %w", err)
+	}
+
+	// Steps 3-4: Generate the journal entries and validate that debits = credits
+	entries, err := s.buildJournalEntries(dbTx, tx, true)
+	if err != nil {
+		return err
+	}
 
+	if tx.IsPending() {
 		return nil
-	})
+	}
+
+	return s.postJournalEntries(dbTx, entries)
+}
+
+// buildJournalEntries generates the balanced journal entries of a transaction without saving them.
+// lock takes the row locks a posting needs (see generateLoanPaymentEntries); previews pass false.
+func (s *accountingEngineService) buildJournalEntries(dbTx *gorm.DB, tx *models.Transaction, lock bool) ([]*models.JournalEntry, error) {
+	entries, err := s.generateJournalEntries(dbTx, tx, lock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate journal entries: %w", err)
+	}
+
+	if err := s.validateBalance(entries); err != nil {
+		return nil, fmt.Errorf("balance validation failed: %w", err)
+	}
+
+	return entries, nil
+}
+
+// postJournalEntries saves the journal entries and updates the cached balance of every account
+// they touch. Every entry references a real ledger account (categories post to their nominal account).
+func (s *accountingEngineService) postJournalEntries(dbTx *gorm.DB, entries []*models.JournalEntry) error {
+	if err := dbTx.Create(&entries).Error; err != nil {
+		return fmt.Errorf("failed to save journal entries: %w", err)
+	}
+
+	if err := s.applyJournalEntries(dbTx, entries); err != nil {
+		return fmt.Errorf("failed to update account balances: %w", err)
+	}
+
+	return nil
 }
 
 // generateJournalEntries creates the debit and credit entries based on transaction type
-func (s *accountingEngineService) generateJournalEntries(tx *models.Transaction) ([]*models.JournalEntry, error) {
+// Category sides are posted to the category's nominal ledger account (ACCOUNT_TYPE=CATEGORY)
+func (s *accountingEngineService) generateJournalEntries(dbTx *gorm.DB, tx *models.Transaction, lock bool) ([]*models.JournalEntry, error) {
 	entries := []*models.JournalEntry{}
 
 	switch tx.Type {
@@ -99,15 +161,24 @@ func (s *accountingEngineService) generateJournalEntries(tx *models.Transaction)
 		// Debit: Expense Category (increases expense)
 		// Credit: Account (decreases asset)
 
+		if tx.IsSplit() {
+			return s.generateSplitJournalEntries(dbTx, tx)
+		}
+
 		if tx.CategoryID == nil {
 			return nil, errors.New("category_id is required for EXPENSE transactions")
 		}
 
+		categoryAccountID, err := s.categoryLedgerAccountID(dbTx, tx.UserID, *tx.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+
 		// Expense category receives a DEBIT (expense increases)
 		entries = append(entries, &models.JournalEntry{
 			UserID:        tx.UserID,
 			TransactionID: tx.ID,
-			AccountID:     *tx.CategoryID, // Using CategoryID as a virtual account
+			AccountID:     categoryAccountID,
 			DebitOrCredit: "DEBIT",
 			Amount:        tx.Amount,
 			EntryDate:     tx.TransactionDate,
@@ -130,10 +201,19 @@ func (s *accountingEngineService) generateJournalEntries(tx *models.Transaction)
 		// Debit: Account (increases asset)
 		// Credit: Income Category (increases income)
 
+		if tx.IsSplit() {
+			return s.generateSplitJournalEntries(dbTx, tx)
+		}
+
 		if tx.CategoryID == nil {
 			return nil, errors.New("category_id is required for INCOME transactions")
 		}
 
+		categoryAccountID, err := s.categoryLedgerAccountID(dbTx, tx.UserID, *tx.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+
 		// Bank/Cash account receives a DEBIT (asset increases)
 		entries = append(entries, &models.JournalEntry{
 			UserID:        tx.UserID,
@@ -149,7 +229,7 @@ func (s *accountingEngineService) generateJournalEntries(tx *models.Transaction)
 		entries = append(entries, &models.JournalEntry{
 			UserID:        tx.UserID,
 			TransactionID: tx.ID,
-			AccountID:     *tx.CategoryID,
+			AccountID:     categoryAccountID,
 			DebitOrCredit: "CREDIT",
 			Amount:        tx.Amount,
 			EntryDate:     tx.TransactionDate,
@@ -187,6 +267,81 @@ func (s *accountingEngineService) generateJournalEntries(tx *models.Transaction)
 			Description:   fmt.Sprintf("Transfer out: %s", tx.Description),
 		})
 
+	case "DEBT_PAYMENT":
+		// DEBT_PAYMENT: Money leaves an asset account to pay down a liability
+		// Debit: Account To (liability decreases)
+		// Credit: Account From (asset decreases)
+		// Payments to LOAN/MORTGAGE accounts are split into principal and interest (see generateLoanPaymentEntries)
+
+		if tx.AccountToID == nil {
+			return nil, errors.New("account_to_id is required for DEBT_PAYMENT transactions")
+		}
+
+		var liability models.Account
+		if err := dbTx.Preload("Loan").First(&liability, *tx.AccountToID).Error; err != nil {
+			return nil, fmt.Errorf("account %d not found: %w", *tx.AccountToID, err)
+		}
+		if liability.IsLoan() && liability.Loan != nil {
+			return s.generateLoanPaymentEntries(dbTx, tx, &liability, lock)
+		}
+
+		// Liability account receives a DEBIT (debt decreases)
+		entries = append(entries, &models.JournalEntry{
+			UserID:        tx.UserID,
+			TransactionID: tx.ID,
+			AccountID:     *tx.AccountToID,
+			DebitOrCredit: "DEBIT",
+			Amount:        tx.Amount,
+			EntryDate:     tx.TransactionDate,
+			Description:   fmt.Sprintf("Debt payment: %s", tx.Description),
+		})
+
+		// Bank/Cash account receives a CREDIT (asset decreases)
+		entries = append(entries, &models.JournalEntry{
+			UserID:        tx.UserID,
+			TransactionID: tx.ID,
+			AccountID:     tx.AccountFromID,
+			DebitOrCredit: "CREDIT",
+			Amount:        tx.Amount,
+			EntryDate:     tx.TransactionDate,
+			Description:   fmt.Sprintf("Payment: %s", tx.Description),
+		})
+
+	case "OPENING_BALANCE", "ADJUSTMENT":
+		// OPENING_BALANCE / ADJUSTMENT: An account balance is set against the user's equity account
+		// Debit: Account To (the side whose balance grows with a debit)
+		// Credit: Account From
+		// The engine builds these transactions itself (see postBalanceChange), so one side is always equity.
+
+		if tx.AccountToID == nil {
+			return nil, fmt.Errorf("account_to_id is required for %s transactions", tx.Type)
+		}
+
+		label := "Opening balance"
+		if tx.Type == "ADJUSTMENT" {
+			label = "Balance adjustment"
+		}
+
+		entries = append(entries, &models.JournalEntry{
+			UserID:        tx.UserID,
+			TransactionID: tx.ID,
+			AccountID:     *tx.AccountToID,
+			DebitOrCredit: "DEBIT",
+			Amount:        tx.Amount,
+			EntryDate:     tx.TransactionDate,
+			Description:   fmt.Sprintf("%s: %s", label, tx.Description),
+		})
+
+		entries = append(entries, &models.JournalEntry{
+			UserID:        tx.UserID,
+			TransactionID: tx.ID,
+			AccountID:     tx.AccountFromID,
+			DebitOrCredit: "CREDIT",
+			Amount:        tx.Amount,
+			EntryDate:     tx.TransactionDate,
+			Description:   fmt.Sprintf("%s: %s", label, tx.Description),
+		})
+
 	default:
 		return nil, fmt.Errorf("unsupported transaction type: %s", tx.Type)
 	}
@@ -194,154 +349,1071 @@ func (s *accountingEngineService) generateJournalEntries(tx *models.Transaction)
 	return entries, nil
 }
 
-// validateBalance ensures that SUM(Debits) = SUM(Credits)
-// This is the FUNDAMENTAL rule of double-entry bookkeeping
-func (s *accountingEngineService) validateBalance(entries []*models.JournalEntry) error {
-	var totalDebits decimal.Decimal
-	var totalCredits decimal.Decimal
+// ensurePeriodOpen rejects entries dated inside an accounting period the user has closed
+func (s *accountingEngineService) ensurePeriodOpen(dbTx *gorm.DB, userID uint, date time.Time) error {
+	year, month := models.PeriodOf(date)
 
-	for _, entry := range entries {
-		if entry.DebitOrCredit == "DEBIT" {
-			totalDebits = totalDebits.Add(entry.Amount)
-		} else if entry.DebitOrCredit == "CREDIT" {
-			totalCredits = totalCredits.Add(entry.Amount)
-		}
+	// Hold the periods lock in share mode until the entries commit, so a concurrent
+	// close of this month waits for them instead of closing around them.
+	if err := repositories.LockUserPeriods(dbTx, userID, "SHARE"); err != nil {
+		return err
 	}
 
-	if !totalDebits.Equal(totalCredits) {
-		return fmt.Errorf(
-			"debits and credits must be equal: debits=%s, credits=%s, difference=%s",
-			totalDebits.String(),
-			totalCredits.String(),
-			totalDebits.Sub(totalCredits).String(),
-		)
+	var periods []models.AccountingPeriod
+	err := dbTx.Clauses(clause.Locking{Strength: "SHARE"}).
+		Where("user_id = ? AND year = ? AND month = ? AND status = ?", userID, year, month, "CLOSED").
+		Limit(1).
+		Find(&periods).Error
+	if err != nil {
+		return fmt.Errorf("failed to check accounting period: %w", err)
+	}
+
+	if len(periods) > 0 {
+		return fmt.Errorf("accounting period %04d-%02d is closed; reopen it before posting entries dated %s", year, month, date.Format("2006-01-02"))
 	}
 
 	return nil
 }
 
-// updateRealAccountBalances updates only real account balances based on transaction type.
-// It operates directly on the transaction object to avoid the CategoryID/AccountID ambiguity
-// that arises in journal entries, where INCOME/EXPENSE entries reference CategoryID as a
-// virtual account placeholder.
-//
-//   - EXPENSE:  AccountFrom balance decreases (money leaves the account)
-//   - INCOME:   AccountFrom balance increases (money arrives into the account)
-//   - TRANSFER: AccountFrom decreases, AccountTo increases
-func (s *accountingEngineService) updateRealAccountBalances(dbTx *gorm.DB, tx *models.Transaction) error {
-	switch tx.Type {
-	case "EXPENSE":
-		return s.applyBalanceChange(dbTx, tx.AccountFromID, tx.Amount.Neg())
-	case "INCOME":
-		return s.applyBalanceChange(dbTx, tx.AccountFromID, tx.Amount)
-	case "TRANSFER":
-		if tx.AccountToID == nil {
-			return fmt.Errorf("account_to_id is required for TRANSFER balance update")
-		}
-		if err := s.applyBalanceChange(dbTx, tx.AccountFromID, tx.Amount.Neg()); err != nil {
-			return err
-		}
-		return s.applyBalanceChange(dbTx, *tx.AccountToID, tx.Amount)
-	default:
-		return fmt.Errorf("unsupported transaction type for balance update: %s", tx.Type)
+// validateDebtPayment ensures a DEBT_PAYMENT moves money from a liquid asset (BANK/CASH)
+// owned by the user to one of the user's liability accounts (CREDIT_CARD, LOAN or MORTGAGE)
+func (s *accountingEngineService) validateDebtPayment(dbTx *gorm.DB, tx *models.Transaction) error {
+	var from, to models.Account
+	if err := dbTx.First(&from, tx.AccountFromID).Error; err != nil {
+		return fmt.Errorf("account %d not found: %w", tx.AccountFromID, err)
 	}
+	if err := dbTx.First(&to, *tx.AccountToID).Error; err != nil {
+		return fmt.Errorf("account %d not found: %w", *tx.AccountToID, err)
+	}
+
+	if from.UserID != tx.UserID || to.UserID != tx.UserID {
+		return errors.New("both accounts of a DEBT_PAYMENT must belong to the user")
+	}
+	if !from.IsLiquidAsset() {
+		return fmt.Errorf("DEBT_PAYMENT must be paid from a BANK or CASH account, got %s", from.AccountType)
+	}
+	if !to.IsLiability() {
+		return fmt.Errorf("DEBT_PAYMENT must be applied to a liability account, got %s", to.AccountType)
+	}
+
+	return nil
 }
 
-// reverseRealAccountBalances applies the inverse balance changes to cancel a transaction.
-// Used by ReverseTransaction to undo the original account balance movements.
-//
-//   - EXPENSE reversal:  AccountFrom balance increases (money is returned)
-//   - INCOME reversal:   AccountFrom balance decreases (income is cancelled)
-//   - TRANSFER reversal: AccountFrom increases, AccountTo decreases
-func (s *accountingEngineService) reverseRealAccountBalances(dbTx *gorm.DB, tx *models.Transaction) error {
-	switch tx.Type {
-	case "EXPENSE":
-		return s.applyBalanceChange(dbTx, tx.AccountFromID, tx.Amount)
-	case "INCOME":
-		return s.applyBalanceChange(dbTx, tx.AccountFromID, tx.Amount.Neg())
-	case "TRANSFER":
-		if tx.AccountToID == nil {
-			return fmt.Errorf("account_to_id is required for TRANSFER reversal")
-		}
-		if err := s.applyBalanceChange(dbTx, tx.AccountFromID, tx.Amount); err != nil {
+// validateTransactionAccounts checks that AccountFrom and AccountTo are accounts of the
+// transaction's user and not system-managed ledger accounts
+func (s *accountingEngineService) validateTransactionAccounts(dbTx *gorm.DB, tx *models.Transaction) error {
+	if err := s.validatePostableAccount(dbTx, tx.UserID, tx.AccountFromID); err != nil {
+		return err
+	}
+	if tx.AccountToID != nil {
+		return s.validatePostableAccount(dbTx, tx.UserID, *tx.AccountToID)
+	}
+
+	return nil
+}
+
+// validateMovedAccounts checks the accounts an edit moves a transaction to: AccountFrom and AccountTo,
+// when they differ from the previous version, must be accounts of the transaction's user and not
+// system-managed ledger accounts (the equity side of an OPENING_BALANCE is kept as it was)
+func (s *accountingEngineService) validateMovedAccounts(dbTx *gorm.DB, tx, previous *models.Transaction) error {
+	if tx.AccountFromID != previous.AccountFromID {
+		if err := s.validatePostableAccount(dbTx, tx.UserID, tx.AccountFromID); err != nil {
 			return err
 		}
-		return s.applyBalanceChange(dbTx, *tx.AccountToID, tx.Amount.Neg())
-	default:
-		return fmt.Errorf("unsupported transaction type for balance reversal: %s", tx.Type)
 	}
+	if tx.AccountToID != nil && (previous.AccountToID == nil || *tx.AccountToID != *previous.AccountToID) {
+		return s.validatePostableAccount(dbTx, tx.UserID, *tx.AccountToID)
+	}
+
+	return nil
 }
 
-// applyBalanceChange fetches an account by ID and atomically applies a balance delta.
-// A positive change increases the balance; a negative change decreases it.
-func (s *accountingEngineService) applyBalanceChange(dbTx *gorm.DB, accountID uint, change decimal.Decimal) error {
+// validatePostableAccount checks that a transaction of the user can post to the account:
+// it must belong to the user and must not be a system-managed ledger account
+func (s *accountingEngineService) validatePostableAccount(dbTx *gorm.DB, userID, accountID uint) error {
 	var account models.Account
 	if err := dbTx.First(&account, accountID).Error; err != nil {
 		return fmt.Errorf("account %d not found: %w", accountID, err)
 	}
-	account.UpdateBalance(change)
-	if err := dbTx.Save(&account).Error; err != nil {
-		return fmt.Errorf("failed to update balance for account %d: %w", accountID, err)
+
+	if account.UserID != userID {
+		return fmt.Errorf("account %d does not belong to the user", accountID)
+	}
+	if account.IsSystemManaged() {
+		return fmt.Errorf("transactions cannot post directly to system ledger account %d", accountID)
 	}
+
 	return nil
 }
 
-// ReverseTransaction creates reversing entries to cancel out a transaction
-// This is used when a transaction needs to be "deleted" (we never truly delete in accounting)
-func (s *accountingEngineService) ReverseTransaction(transactionID uint) error {
-	return s.db.Transaction(func(dbTx *gorm.DB) error {
-		// Get original transaction
-		tx, err := s.transactionRepository.FindByID(transactionID)
-		if err != nil {
-			return fmt.Errorf("transaction not found: %w", err)
-		}
+// generateLoanPaymentEntries creates the entries for a DEBT_PAYMENT to a LOAN or MORTGAGE account.
+// The first payment dated in a month charges that month's interest (principal outstanding on the payment
+// date * monthly rate) and the rest reduces the principal; further payments in the same month go
+// entirely to principal:
+//
+//   - Debit the loan account (principal, liability decreases)
+//   - Debit the loan's interest category (interest, expense increases)
+//   - Credit AccountFrom (full amount, asset decreases)
+//
+// When the payment does not cover the interest, the whole interest is still charged and the unpaid
+// part is accrued onto the loan (Credit the loan account, liability increases), so it is owed as
+// principal from then on instead of being dropped. A payment cannot exceed the interest due plus
+// the outstanding principal.
+//
+// The source and loan accounts are locked first (in ID order, as lockAccounts does), so concurrent
+// payments to the same loan are split one after the other and only one of them charges the interest.
+// Previews pass lock=false: they persist nothing, so they neither need nor take the locks.
+// The outstanding principal comes from the journal as of the payment date, which keeps backdated
+// payments and reposts (the replaced payment no longer counts) right.
+func (s *accountingEngineService) generateLoanPaymentEntries(dbTx *gorm.DB, tx *models.Transaction, account *models.Account, lock bool) ([]*models.JournalEntry, error) {
+	loan := account.Loan
 
-		// Get original journal entries
-		originalEntries, err := s.journalEntryRepository.FindByTransaction(transactionID)
-		if err != nil {
-			return fmt.Errorf("failed to find journal entries: %w", err)
+	if lock {
+		var locked []models.Account
+		if err := dbTx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ?", []uint{tx.AccountFromID, account.ID}).
+			Order("id ASC").
+			Find(&locked).Error; err != nil {
+			return nil, fmt.Errorf("failed to lock accounts: %w", err)
 		}
+	}
+
+	net, err := repositories.PostedAccountBalance(dbTx, account.ID, tx.TransactionDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute the outstanding principal: %w", err)
+	}
+	outstanding := account.BalanceDelta("DEBIT", net)
+
+	date := tx.TransactionDate.UTC()
+	monthStart := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	var earlierPayments int64
+	err = dbTx.Model(&models.Transaction{}).
+		Where("type = ? AND account_to_id = ? AND id <> ?", "DEBT_PAYMENT", account.ID, tx.ID).
+		Where("transaction_date >= ? AND transaction_date < ?", monthStart, monthStart.AddDate(0, 1, 0)).
+		Where("status = ? AND reverses_transaction_id IS NULL", "POSTED").
+		Count(&earlierPayments).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to check earlier loan payments: %w", err)
+	}
+
+	interest := decimal.Zero
+	if earlierPayments == 0 {
+		interest = loan.InterestFor(decimal.Max(outstanding, decimal.Zero))
+	}
+	paidInterest := decimal.Min(interest, tx.Amount)
+	accruedInterest := interest.Sub(paidInterest)
+	principal := tx.Amount.Sub(paidInterest)
+
+	if principal.GreaterThan(outstanding) {
+		return nil, fmt.Errorf("payment of %s exceeds the %s owed on %q on %s (principal %s + interest %s)",
+			tx.Amount.String(), outstanding.Add(interest).String(), account.Name, date.Format("2006-01-02"), outstanding.String(), interest.String())
+	}
+
+	entries := []*models.JournalEntry{}
+
+	// Loan account receives a DEBIT for the principal (debt decreases)
+	if principal.IsPositive() {
+		entries = append(entries, &models.JournalEntry{
+			UserID:        tx.UserID,
+			TransactionID: tx.ID,
+			AccountID:     account.ID,
+			DebitOrCredit: "DEBIT",
+			Amount:        principal,
+			EntryDate:     tx.TransactionDate,
+			Description:   fmt.Sprintf("Loan principal: %s", tx.Description),
+		})
+	}
 
-		// Create reversing entries (swap DEBIT <-> CREDIT)
-		reversingEntries := []*models.JournalEntry{}
-		now := time.Now()
+	// Loan account receives a CREDIT for the interest the payment did not cover (debt increases)
+	if accruedInterest.IsPositive() {
+		entries = append(entries, &models.JournalEntry{
+			UserID:        tx.UserID,
+			TransactionID: tx.ID,
+			AccountID:     account.ID,
+			DebitOrCredit: "CREDIT",
+			Amount:        accruedInterest,
+			EntryDate:     tx.TransactionDate,
+			Description:   fmt.Sprintf("Loan interest accrued: %s", tx.Description),
+		})
+	}
 
-		for _, original := range originalEntries {
-			reversedType := "DEBIT"
-			if original.DebitOrCredit == "DEBIT" {
-				reversedType = "CREDIT"
+	// Interest category receives a DEBIT for the interest (expense increases)
+	if interest.IsPositive() {
+		categoryID := loan.InterestCategoryID
+		if categoryID == nil {
+			category, err := repositories.EnsureLoanInterestCategory(dbTx, tx.UserID)
+			if err != nil {
+				return nil, err
 			}
+			categoryID = &category.ID
+		}
 
-			reversingEntries = append(reversingEntries, &models.JournalEntry{
-				UserID:        original.UserID,
-				TransactionID: transactionID,
-				AccountID:     original.AccountID,
-				DebitOrCredit: reversedType,
-				Amount:        original.Amount,
-				EntryDate:     now,
-				Description:   fmt.Sprintf("REVERSAL: %s", original.Description),
-			})
+		interestAccountID, err := s.categoryLedgerAccountID(dbTx, tx.UserID, *categoryID)
+		if err != nil {
+			return nil, err
 		}
 
-		// Save reversing entries
-		if err := dbTx.Create(&reversingEntries).Error; err != nil {
-			return fmt.Errorf("failed to create reversing entries: %w", err)
+		entries = append(entries, &models.JournalEntry{
+			UserID:        tx.UserID,
+			TransactionID: tx.ID,
+			AccountID:     interestAccountID,
+			DebitOrCredit: "DEBIT",
+			Amount:        interest,
+			EntryDate:     tx.TransactionDate,
+			Description:   fmt.Sprintf("Loan interest: %s", tx.Description),
+		})
+	}
+
+	// Bank/Cash account receives a CREDIT for the full payment (asset decreases)
+	entries = append(entries, &models.JournalEntry{
+		UserID:        tx.UserID,
+		TransactionID: tx.ID,
+		AccountID:     tx.AccountFromID,
+		DebitOrCredit: "CREDIT",
+		Amount:        tx.Amount,
+		EntryDate:     tx.TransactionDate,
+		Description:   fmt.Sprintf("Payment: %s", tx.Description),
+	})
+
+	return entries, nil
+}
+
+// generateSplitJournalEntries creates the entries for a split (multi-leg) INCOME or EXPENSE transaction.
+// AccountFrom receives a single entry for the full amount, and every leg receives the opposite
+// entry for its own amount:
+//
+//   - EXPENSE: Credit AccountFrom (total), Debit each leg (category or account)
+//   - INCOME:  Debit AccountFrom (total), Credit each leg (category or account)
+//
+// The legs are NOT checked against the total here; validateBalance rejects the entries
+// if SUM(legs) != Amount, since the debits and credits will not match.
+func (s *accountingEngineService) generateSplitJournalEntries(dbTx *gorm.DB, tx *models.Transaction) ([]*models.JournalEntry, error) {
+	mainSide, legSide := "CREDIT", "DEBIT"
+	mainLabel, categoryLabel, accountLabel := "Payment", "Expense", "Transfer in"
+	if tx.Type == "INCOME" {
+		mainSide, legSide = "DEBIT", "CREDIT"
+		mainLabel, categoryLabel, accountLabel = "Income", "Revenue", "Transfer out"
+	}
+
+	entries := []*models.JournalEntry{{
+		UserID:        tx.UserID,
+		TransactionID: tx.ID,
+		AccountID:     tx.AccountFromID,
+		DebitOrCredit: mainSide,
+		Amount:        tx.Amount,
+		EntryDate:     tx.TransactionDate,
+		Description:   fmt.Sprintf("%s: %s", mainLabel, tx.Description),
+	}}
+
+	for _, leg := range tx.Splits {
+		description := leg.Description
+		if description == "" {
+			description = tx.Description
 		}
 
-		// Reverse the original account balance changes using the transaction directly.
-		// This guarantees only real accounts are touched (no CategoryID confusion).
-		if err := s.reverseRealAccountBalances(dbTx, tx); err != nil {
-			return fmt.Errorf("failed to update balances during reversal: %w", err)
+		entry := &models.JournalEntry{
+			UserID:        tx.UserID,
+			TransactionID: tx.ID,
+			DebitOrCredit: legSide,
+			Amount:        leg.Amount,
+			EntryDate:     tx.TransactionDate,
+		}
+		if leg.IsCategoryLeg() {
+			categoryAccountID, err := s.categoryLedgerAccountID(dbTx, tx.UserID, *leg.CategoryID)
+			if err != nil {
+				return nil, err
+			}
+			entry.AccountID = categoryAccountID
+			entry.Description = fmt.Sprintf("%s: %s", categoryLabel, description)
+		} else {
+			if err := s.validateSplitLegAccount(dbTx, tx, *leg.AccountID); err != nil {
+				return nil, err
+			}
+			entry.AccountID = *leg.AccountID
+			entry.Description = fmt.Sprintf("%s: %s", accountLabel, description)
 		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// validateSplitLegAccount checks that an account leg of a split posts to one of the user's own accounts:
+// not a system-managed ledger account, and not AccountFrom itself
+func (s *accountingEngineService) validateSplitLegAccount(dbTx *gorm.DB, tx *models.Transaction, accountID uint) error {
+	if err := s.validatePostableAccount(dbTx, tx.UserID, accountID); err != nil {
+		return err
+	}
+	if accountID == tx.AccountFromID {
+		return errors.New("a split leg cannot post to the transaction's own account")
+	}
+
+	return nil
+}
+
+// categoryLedgerAccountID resolves the nominal ledger account of a category owned by the user.
+// Categories created before ledger accounts existed get one on first use.
+func (s *accountingEngineService) categoryLedgerAccountID(dbTx *gorm.DB, userID, categoryID uint) (uint, error) {
+	var category models.Category
+	if err := dbTx.First(&category, categoryID).Error; err != nil {
+		return 0, fmt.Errorf("category %d not found: %w", categoryID, err)
+	}
+
+	if category.UserID != userID {
+		return 0, fmt.Errorf("category %d does not belong to the user", categoryID)
+	}
+
+	return repositories.EnsureCategoryLedgerAccount(dbTx, &category)
+}
+
+// validateBalance ensures that SUM(Debits) = SUM(Credits)
+// This is the FUNDAMENTAL rule of double-entry bookkeeping
+func (s *accountingEngineService) validateBalance(entries []*models.JournalEntry) error {
+	var totalDebits decimal.Decimal
+	var totalCredits decimal.Decimal
 
-		// Mark original transaction as reconciled (archived)
-		tx.IsReconciled = true
-		if err := dbTx.Save(tx).Error; err != nil {
-			return fmt.Errorf("failed to mark transaction as reversed: %w", err)
+	for _, entry := range entries {
+		if entry.DebitOrCredit == "DEBIT" {
+			totalDebits = totalDebits.Add(entry.Amount)
+		} else if entry.DebitOrCredit == "CREDIT" {
+			totalCredits = totalCredits.Add(entry.Amount)
 		}
+	}
 
-		return nil
-	})
+	if !totalDebits.Equal(totalCredits) {
+		return fmt.Errorf(
+			"debits and credits must be equal: debits=%s, credits=%s, difference=%s",
+			totalDebits.String(),
+			totalCredits.String(),
+			totalDebits.Sub(totalCredits).String(),
+		)
+	}
+
+	return nil
+}
+
+// applyJournalEntries updates the cached Account.Balance of every account referenced by the entries.
+// Each entry moves the balance according to the account's normal side (see Account.BalanceDelta):
+//
+//   - ASSET / EXPENSE accounts:            DEBIT increases, CREDIT decreases
+//   - LIABILITY / EQUITY / INCOME accounts: CREDIT increases, DEBIT decreases
+//
+// Liability balances therefore represent the amount owed, and nominal category accounts
+// accumulate the total income/expense posted to them.
+//
+// The touched accounts are locked (SELECT ... FOR UPDATE) in ID order before any change, so
+// concurrent postings on the same account queue up instead of losing updates or deadlocking.
+func (s *accountingEngineService) applyJournalEntries(dbTx *gorm.DB, entries []*models.JournalEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	accounts, err := lockAccounts(dbTx, entries)
+	if err != nil {
+		return err
+	}
+
+	// Net the entries per account: one atomic update per account
+	deltas := make(map[uint]decimal.Decimal, len(accounts))
+	for _, entry := range entries {
+		account, ok := accounts[entry.AccountID]
+		if !ok {
+			return fmt.Errorf("account %d not found", entry.AccountID)
+		}
+		deltas[account.ID] = deltas[account.ID].Add(account.BalanceDelta(entry.DebitOrCredit, entry.Amount))
+	}
+
+	ids := make([]uint, 0, len(deltas))
+	for id := range deltas {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	for _, id := range ids {
+		if err := s.applyBalanceChange(dbTx, id, deltas[id]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lockAccounts loads the accounts referenced by the entries with a row lock, in ascending ID order
+func lockAccounts(dbTx *gorm.DB, entries []*models.JournalEntry) (map[uint]*models.Account, error) {
+	seen := make(map[uint]bool, len(entries))
+	ids := make([]uint, 0, len(entries))
+	for _, entry := range entries {
+		if !seen[entry.AccountID] {
+			seen[entry.AccountID] = true
+			ids = append(ids, entry.AccountID)
+		}
+	}
+
+	var list []*models.Account
+	if err := dbTx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", ids).
+		Order("id ASC").
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to lock accounts: %w", err)
+	}
+
+	accounts := make(map[uint]*models.Account, len(list))
+	for _, account := range list {
+		accounts[account.ID] = account
+	}
+	return accounts, nil
+}
+
+// applyBalanceChange adds delta to an account's cached balance with an atomic
+// balance = balance + ? update (never a read-modify-write) and bumps its version
+func (s *accountingEngineService) applyBalanceChange(dbTx *gorm.DB, accountID uint, delta decimal.Decimal) error {
+	result := dbTx.Model(&models.Account{}).
+		Where("id = ?", accountID).
+		Updates(map[string]interface{}{
+			"balance": gorm.Expr("balance + ?", delta),
+			"version": gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update balance for account %d: %w", accountID, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("account %d not found", accountID)
+	}
+	return nil
+}
+
+// ReverseTransaction creates reversing entries to cancel out a transaction and marks it VOIDED
+// This is used when a transaction needs to be "deleted" (we never truly delete in accounting)
+// A pending transaction never touched the ledger, so it is voided without reversing entries
+func (s *accountingEngineService) ReverseTransaction(transactionID uint) error {
+	return s.db.Transaction(func(dbTx *gorm.DB) error {
+		_, err := s.reverseTransaction(dbTx, transactionID, "VOIDED")
+		return err
+	})
+}
+
+// reverseTransaction posts the reversing entries of a transaction inside an open database transaction
+// and returns the original transaction, left with the given status (REVERSED or VOIDED).
+// The reversing entries belong to a separate reversal record linked to the original
+// (ReversedByTransactionID / ReversesTransactionID), dated now.
+func (s *accountingEngineService) reverseTransaction(dbTx *gorm.DB, transactionID uint, status string) (*models.Transaction, error) {
+	// Get original transaction, locked so that two concurrent reversals cannot both see it POSTED
+	var tx models.Transaction
+	if err := dbTx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Splits").First(&tx, transactionID).Error; err != nil {
+		return nil, fmt.Errorf("transaction not found: %w", err)
+	}
+
+	if tx.IsReversal() {
+		return nil, fmt.Errorf("transaction %d is the reversal of transaction %d and cannot be reversed", tx.ID, *tx.ReversesTransactionID)
+	}
+
+	// A pending transaction has no journal entries yet: voiding it is all it takes
+	if tx.IsPending() {
+		if status != "VOIDED" {
+			return nil, fmt.Errorf("transaction %d is pending and cannot be replaced; edit it in place", tx.ID)
+		}
+		tx.Status = status
+		if err := dbTx.Model(&tx).Update("status", status).Error; err != nil {
+			return nil, fmt.Errorf("failed to void pending transaction: %w", err)
+		}
+		return &tx, nil
+	}
+
+	if !tx.IsPosted() {
+		return nil, fmt.Errorf("transaction %d is already %s", tx.ID, strings.ToLower(tx.Status))
+	}
+
+	if tx.IsLocked() {
+		return nil, fmt.Errorf("transaction %d is locked by reconciliation %d", tx.ID, *tx.ReconciliationID)
+	}
+
+	// Neither the original (dated in its period) nor the reversing entries (dated now)
+	// may touch a closed accounting period
+	now := time.Now()
+	if err := s.ensurePeriodOpen(dbTx, tx.UserID, tx.TransactionDate); err != nil {
+		return nil, err
+	}
+	if err := s.ensurePeriodOpen(dbTx, tx.UserID, now); err != nil {
+		return nil, err
+	}
+
+	// Get original journal entries
+	var originalEntries []*models.JournalEntry
+	if err := dbTx.Where("transaction_id = ?", transactionID).Order("id ASC").Find(&originalEntries).Error; err != nil {
+		return nil, fmt.Errorf("failed to find journal entries: %w", err)
+	}
+
+	// The reversal record carries the reversing entries
+	reversal := reversalRecordFor(&tx, now)
+	if err := dbTx.Omit("AccountFrom", "AccountTo", "Category", "Splits").Create(reversal).Error; err != nil {
+		return nil, fmt.Errorf("failed to create reversal record: %w", err)
+	}
+
+	// Create reversing entries (swap DEBIT <-> CREDIT)
+	reversingEntries := []*models.JournalEntry{}
+
+	for _, original := range originalEntries {
+		reversedType := "DEBIT"
+		if original.DebitOrCredit == "DEBIT" {
+			reversedType = "CREDIT"
+		}
+
+		reversingEntries = append(reversingEntries, &models.JournalEntry{
+			UserID:        original.UserID,
+			TransactionID: reversal.ID,
+			AccountID:     original.AccountID,
+			DebitOrCredit: reversedType,
+			Amount:        original.Amount,
+			EntryDate:     now,
+			Description:   fmt.Sprintf("REVERSAL: %s", original.Description),
+		})
+	}
+
+	// Save reversing entries
+	if len(reversingEntries) > 0 {
+		if err := dbTx.Create(&reversingEntries).Error; err != nil {
+			return nil, fmt.Errorf("failed to create reversing entries: %w", err)
+		}
+	}
+
+	// Apply the reversing entries to the cached balances (undoes the original movements)
+	if err := s.applyJournalEntries(dbTx, reversingEntries); err != nil {
+		return nil, fmt.Errorf("failed to update balances during reversal: %w", err)
+	}
+
+	// Close the original's lifecycle and link it to its reversal
+	tx.Status = status
+	tx.ReversedByTransactionID = &reversal.ID
+	if err := dbTx.Model(&tx).Updates(map[string]interface{}{
+		"status":                     status,
+		"reversed_by_transaction_id": reversal.ID,
+	}).Error; err != nil {
+		return nil, fmt.Errorf("failed to mark transaction as reversed: %w", err)
+	}
+
+	return &tx, nil
+}
+
+// reversalRecordFor builds the record that carries the reversing entries of a transaction.
+// It mirrors the original (type, amount, accounts, category) so reports that group by those
+// fields net both out, but it is never listed or counted as a transaction of its own.
+func reversalRecordFor(tx *models.Transaction, date time.Time) *models.Transaction {
+	return &models.Transaction{
+		UserID:                tx.UserID,
+		Type:                  tx.Type,
+		Description:           fmt.Sprintf("REVERSAL: %s", tx.Description),
+		Amount:                tx.Amount,
+		AmountInUSD:           tx.AmountInUSD,
+		ExchangeRate:          tx.ExchangeRate,
+		AccountFromID:         tx.AccountFromID,
+		AccountToID:           tx.AccountToID,
+		CategoryID:            tx.CategoryID,
+		TransactionDate:       date,
+		Status:                "POSTED",
+		ReversesTransactionID: &tx.ID,
+	}
+}
+
+// errBatchRolledBack aborts the database transaction of an all-or-nothing batch with a failed item
+var errBatchRolledBack = errors.New("batch rolled back")
+
+// ProcessTransactions posts a batch of transactions inside a single database transaction.
+// Every item runs in its own savepoint, so a failing item neither aborts the others nor hides their
+// errors: the returned slice holds one error per item (nil when it was posted).
+// With allOrNothing, any failed item rolls back the whole batch and no transaction keeps its ID.
+// The second return value is reserved for failures of the batch itself (e.g. the commit).
+func (s *accountingEngineService) ProcessTransactions(txs []*models.Transaction, allOrNothing bool) ([]error, error) {
+	errs := make([]error, len(txs))
+	failed := false
+
+	err := s.db.Transaction(func(dbTx *gorm.DB) error {
+		for i, tx := range txs {
+			if err := tx.Validate(); err != nil {
+				errs[i] = fmt.Errorf("transaction validation failed: %w", err)
+				failed = true
+				continue
+			}
+
+			// Nested transaction = SAVEPOINT: only this item is rolled back on error
+			errs[i] = dbTx.Transaction(func(itemTx *gorm.DB) error {
+				return s.processTransaction(itemTx, tx)
+			})
+			if errs[i] != nil {
+				tx.ID = 0
+				failed = true
+			}
+		}
+
+		if failed && allOrNothing {
+			return errBatchRolledBack
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errBatchRolledBack) {
+		return errs, err
+	}
+
+	if failed && allOrNothing {
+		for _, tx := range txs {
+			tx.ID = 0
+		}
+	}
+
+	return errs, nil
+}
+
+// ReplaceTransaction corrects a posted transaction by reversing it and posting the replacement,
+// both inside a single database transaction. The original and the replacement are linked
+// (ReplacedByTransactionID / ReplacesTransactionID) so the edit history can be followed.
+func (s *accountingEngineService) ReplaceTransaction(originalID uint, replacement *models.Transaction) error {
+	if err := replacement.Validate(); err != nil {
+		return fmt.Errorf("transaction validation failed: %w", err)
+	}
+
+	return s.db.Transaction(func(dbTx *gorm.DB) error {
+		original, err := s.reverseTransaction(dbTx, originalID, "REVERSED")
+		if err != nil {
+			return err
+		}
+
+		if replacement.UserID != original.UserID {
+			return errors.New("replacement must belong to the same user as the original transaction")
+		}
+
+		// An edit can move the transaction to other accounts
+		if err := s.validateMovedAccounts(dbTx, replacement, original); err != nil {
+			return err
+		}
+
+		replacement.ReplacesTransactionID = &original.ID
+		if err := s.processTransaction(dbTx, replacement); err != nil {
+			return err
+		}
+
+		if err := dbTx.Model(original).Update("replaced_by_transaction_id", replacement.ID).Error; err != nil {
+			return fmt.Errorf("failed to link original transaction: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// ErrTransactionNotPending is returned when posting or editing in place a transaction that is not PENDING
+var ErrTransactionNotPending = errors.New("transaction is not pending")
+
+// PostPendingTransaction posts a PENDING transaction: its journal entries are written and the account
+// balances change, exactly as if it had been created as POSTED. A non-nil transactionDate re-dates the
+// transaction first (e.g. a future payment confirmed early is posted today).
+// The transaction row is locked, so a confirmation and the scheduler cannot both post it.
+func (s *accountingEngineService) PostPendingTransaction(transactionID uint, transactionDate *time.Time) (*models.Transaction, error) {
+	var tx models.Transaction
+
+	err := s.db.Transaction(func(dbTx *gorm.DB) error {
+		if err := dbTx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Splits").First(&tx, transactionID).Error; err != nil {
+			return fmt.Errorf("transaction not found: %w", err)
+		}
+
+		if !tx.IsPending() {
+			return fmt.Errorf("%w: transaction %d is %s", ErrTransactionNotPending, tx.ID, strings.ToLower(tx.Status))
+		}
+
+		if transactionDate != nil {
+			tx.TransactionDate = *transactionDate
+		}
+
+		if err := s.ensurePeriodOpen(dbTx, tx.UserID, tx.TransactionDate); err != nil {
+			return err
+		}
+
+		// Accounts may have changed since the transaction was scheduled
+		if tx.Type == "DEBT_PAYMENT" {
+			if err := s.validateDebtPayment(dbTx, &tx); err != nil {
+				return err
+			}
+		}
+
+		entries, err := s.buildJournalEntries(dbTx, &tx, true)
+		if err != nil {
+			return err
+		}
+
+		if err := s.postJournalEntries(dbTx, entries); err != nil {
+			return err
+		}
+
+		tx.Status = "POSTED"
+		if err := dbTx.Model(&tx).Updates(map[string]interface{}{
+			"status":           tx.Status,
+			"transaction_date": tx.TransactionDate,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to mark transaction as posted: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &tx, nil
+}
+
+// UpdatePendingTransaction saves the changes to a PENDING transaction in place: it has no journal
+// entries to reverse, so it is only checked against the posting rules again.
+// A transaction without splits loses the legs it had (a category replaced them).
+func (s *accountingEngineService) UpdatePendingTransaction(tx *models.Transaction) error {
+	if err := tx.Validate(); err != nil {
+		return fmt.Errorf("transaction validation failed: %w", err)
+	}
+
+	return s.db.Transaction(func(dbTx *gorm.DB) error {
+		var current models.Transaction
+		if err := dbTx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, tx.ID).Error; err != nil {
+			return fmt.Errorf("transaction not found: %w", err)
+		}
+
+		if !current.IsPending() {
+			return fmt.Errorf("%w: transaction %d is %s", ErrTransactionNotPending, current.ID, strings.ToLower(current.Status))
+		}
+
+		if tx.UserID != current.UserID {
+			return errors.New("a pending transaction cannot change owner")
+		}
+		if err := s.validateMovedAccounts(dbTx, tx, &current); err != nil {
+			return err
+		}
+
+		if tx.Type == "DEBT_PAYMENT" {
+			if err := s.validateDebtPayment(dbTx, tx); err != nil {
+				return err
+			}
+		}
+
+		if _, err := s.buildJournalEntries(dbTx, tx, true); err != nil {
+			return err
+		}
+
+		if !tx.IsSplit() {
+			if err := dbTx.Where("transaction_id = ?", tx.ID).Delete(&models.TransactionSplit{}).Error; err != nil {
+				return fmt.Errorf("failed to remove split legs: %w", err)
+			}
+		}
+
+		if err := dbTx.Omit(clause.Associations).Save(tx).Error; err != nil {
+			return fmt.Errorf("failed to update transaction: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// errPreviewRollback ends the database transaction of a preview: returning it rolls back anything
+// building the entries created on the way (category ledger accounts, the "Loan Interest" category)
+var errPreviewRollback = errors.New("preview rollback")
+
+// PreviewBalanceChanges returns the net change the transactions would produce on the cached balance of
+// every account they touch (nominal category accounts included), without persisting anything.
+// It is used to project balances with pending transactions. All of them are previewed in one database
+// transaction that is rolled back, and without the row locks of a posting, so a read-only projection
+// never waits for (or holds up) real postings.
+func (s *accountingEngineService) PreviewBalanceChanges(txs []*models.Transaction) (map[uint]decimal.Decimal, error) {
+	changes := make(map[uint]decimal.Decimal)
+	if len(txs) == 0 {
+		return changes, nil
+	}
+
+	err := s.db.Transaction(func(dbTx *gorm.DB) error {
+		accounts := make(map[uint]*models.Account)
+
+		for _, tx := range txs {
+			entries, err := s.buildJournalEntries(dbTx, tx, false)
+			if err != nil {
+				return fmt.Errorf("failed to project transaction %d: %w", tx.ID, err)
+			}
+
+			for _, entry := range entries {
+				account, ok := accounts[entry.AccountID]
+				if !ok {
+					account = &models.Account{}
+					if err := dbTx.First(account, entry.AccountID).Error; err != nil {
+						return fmt.Errorf("account %d not found: %w", entry.AccountID, err)
+					}
+					accounts[entry.AccountID] = account
+				}
+				changes[account.ID] = changes[account.ID].Add(account.BalanceDelta(entry.DebitOrCredit, entry.Amount))
+			}
+		}
+
+		return errPreviewRollback
+	})
+	if err != nil && !errors.Is(err, errPreviewRollback) {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// ErrOccurrenceAlreadyPosted is returned when a recurring occurrence was already materialized
+// (typically by a concurrent scheduler run)
+var ErrOccurrenceAlreadyPosted = errors.New("recurring occurrence was already posted")
+
+// PostRecurringOccurrence materializes one occurrence of a recurring transaction inside a single
+// database transaction: it claims the occurrence (unique per template and index), posts tx through the
+// regular posting rules (tx is nil for a skipped occurrence) and advances the template past it.
+// Returns ErrOccurrenceAlreadyPosted without posting anything if the template, locked and re-read,
+// already moved past the occurrence or the occurrence was already claimed; in the latter case the
+// stored counter is moved past it so the next run resumes after it.
+func (s *accountingEngineService) PostRecurringOccurrence(template *models.RecurringTransaction, occurrence *models.RecurringOccurrence, tx *models.Transaction) error {
+	if tx != nil {
+		if err := tx.Validate(); err != nil {
+			return fmt.Errorf("transaction validation failed: %w", err)
+		}
+	}
+
+	var claimed bool
+	err := s.db.Transaction(func(dbTx *gorm.DB) error {
+		var current models.RecurringTransaction
+		if err := dbTx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "next_occurrence").
+			First(&current, template.ID).Error; err != nil {
+			return fmt.Errorf("failed to lock recurring transaction %d: %w", template.ID, err)
+		}
+		if current.NextOccurrence > occurrence.OccurrenceIndex {
+			return ErrOccurrenceAlreadyPosted
+		}
+
+		result := dbTx.Clauses(clause.OnConflict{DoNothing: true}).Create(occurrence)
+		if result.Error != nil {
+			return fmt.Errorf("failed to record occurrence: %w", result.Error)
+		}
+		claimed = result.RowsAffected > 0
+
+		if claimed && tx != nil {
+			if err := s.processTransaction(dbTx, tx); err != nil {
+				return err
+			}
+
+			occurrence.TransactionID = &tx.ID
+			if err := dbTx.Model(occurrence).Update("transaction_id", tx.ID).Error; err != nil {
+				return fmt.Errorf("failed to link occurrence to transaction: %w", err)
+			}
+		}
+
+		template.Advance(occurrence.OccurrenceIndex + 1)
+		if err := dbTx.Model(template).
+			Select("next_occurrence", "next_occurrence_date").
+			Updates(template).Error; err != nil {
+			return fmt.Errorf("failed to advance recurring transaction %d: %w", template.ID, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if !claimed {
+		return ErrOccurrenceAlreadyPosted
+	}
+
+	return nil
+}
+
+// OpenAccount creates a new account and posts its opening balance against the user's equity account,
+// both inside a single database transaction. The account always starts at zero; the balance
+// is only reached through the OPENING_BALANCE journal entries, so ledger and cache agree.
+func (s *accountingEngineService) OpenAccount(account *models.Account, openingBalance decimal.Decimal, openingDate time.Time) error {
+	if err := account.Validate(); err != nil {
+		return err
+	}
+
+	return s.db.Transaction(func(dbTx *gorm.DB) error {
+		if err := s.prepareLoan(dbTx, account); err != nil {
+			return err
+		}
+
+		// A LOAN or MORTGAGE account is created together with its Loan terms (a CREDIT_CARD with its card terms, if any)
+		account.Balance = decimal.Zero
+		if err := dbTx.Create(account).Error; err != nil {
+			return fmt.Errorf("failed to create account: %w", err)
+		}
+
+		_, err := s.postBalanceChange(dbTx, account, "OPENING_BALANCE", openingBalance, openingDate, "")
+		return err
+	})
+}
+
+// prepareLoan checks the Loan terms of a LOAN or MORTGAGE account being opened and resolves the
+// category of its interest legs (the user's "Loan Interest" category when none was chosen).
+// Other account types cannot carry loan terms.
+func (s *accountingEngineService) prepareLoan(dbTx *gorm.DB, account *models.Account) error {
+	if !account.IsLoan() {
+		if account.Loan != nil {
+			return fmt.Errorf("loan terms are only supported for LOAN and MORTGAGE accounts, got %s", account.AccountType)
+		}
+		return nil
+	}
+
+	if account.Loan == nil {
+		return fmt.Errorf("loan terms are required for %s accounts", account.AccountType)
+	}
+	if err := account.Loan.Validate(); err != nil {
+		return err
+	}
+
+	if account.Loan.InterestCategoryID == nil {
+		category, err := repositories.EnsureLoanInterestCategory(dbTx, account.UserID)
+		if err != nil {
+			return err
+		}
+		account.Loan.InterestCategoryID = &category.ID
+		return nil
+	}
+
+	var category models.Category
+	if err := dbTx.First(&category, *account.Loan.InterestCategoryID).Error; err != nil {
+		return fmt.Errorf("category %d not found: %w", *account.Loan.InterestCategoryID, err)
+	}
+	if category.UserID != account.UserID || strings.ToUpper(category.Type) != "EXPENSE" {
+		return fmt.Errorf("interest_category_id must be one of the user's expense categories")
+	}
+
+	return nil
+}
+
+// AdjustAccountBalance brings an account to a new balance by posting an ADJUSTMENT transaction
+// for the difference against the user's equity account.
+// Returns nil (and no error) when the account is already at the requested balance.
+func (s *accountingEngineService) AdjustAccountBalance(accountID uint, newBalance decimal.Decimal, date time.Time, notes string) (*models.Transaction, error) {
+	var adjustment *models.Transaction
+
+	err := s.db.Transaction(func(dbTx *gorm.DB) error {
+		// Lock the account: the adjustment is computed from its current balance
+		var account models.Account
+		if err := dbTx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&account, accountID).Error; err != nil {
+			return fmt.Errorf("account %d not found: %w", accountID, err)
+		}
+
+		if account.IsSystemManaged() {
+			return fmt.Errorf("the balance of %s account %d is managed by the ledger", account.AccountType, accountID)
+		}
+
+		tx, err := s.postBalanceChange(dbTx, &account, "ADJUSTMENT", newBalance, date, notes)
+		if err != nil {
+			return err
+		}
+
+		adjustment = tx
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return adjustment, nil
+}
+
+// BackfillOpeningBalance journals a cached balance that was written without journal entries.
+// The cached balance is first reset to what the journal supports, then an OPENING_BALANCE for the
+// difference (dated at the account's creation) brings it back, so the stored balance is unchanged
+// but now fully backed by the ledger. Returns nil when the account already agrees with its journal.
+func (s *accountingEngineService) BackfillOpeningBalance(accountID uint) (*models.Transaction, error) {
+	var opening *models.Transaction
+
+	err := s.db.Transaction(func(dbTx *gorm.DB) error {
+		// Lock the account: the adjustment is computed from its current balance
+		var account models.Account
+		if err := dbTx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&account, accountID).Error; err != nil {
+			return fmt.Errorf("account %d not found: %w", accountID, err)
+		}
+
+		if account.IsSystemManaged() {
+			return nil
+		}
+
+		ledger, err := journalBalance(dbTx, &account)
+		if err != nil {
+			return err
+		}
+
+		stored := account.Balance
+		if ledger.Equal(stored) {
+			return nil
+		}
+
+		if err := dbTx.Model(&account).Updates(map[string]interface{}{
+			"balance": ledger,
+			"version": gorm.Expr("version + 1"),
+		}).Error; err != nil {
+			return fmt.Errorf("failed to reset balance of account %d: %w", accountID, err)
+		}
+		account.Balance = ledger
+
+		tx, err := s.postBalanceChange(dbTx, &account, "OPENING_BALANCE", stored, account.CreatedAt, "Backfilled from the stored account balance")
+		if err != nil {
+			return err
+		}
+
+		opening = tx
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return opening, nil
+}
+
+// postBalanceChange posts an OPENING_BALANCE or ADJUSTMENT transaction that moves the account from
+// its current cached balance to target. The difference is booked against the equity account:
+//
+//   - balance increases: the account receives its normal side (DEBIT for assets, CREDIT for liabilities)
+//   - balance decreases: the account receives the opposite side
+//
+// The equity account always receives the other side, so the entries balance by construction.
+func (s *accountingEngineService) postBalanceChange(dbTx *gorm.DB, account *models.Account, txType string, target decimal.Decimal, date time.Time, notes string) (*models.Transaction, error) {
+	delta := target.Sub(account.Balance)
+	if delta.IsZero() {
+		return nil, nil
+	}
+
+	equity, err := repositories.EnsureEquityAccount(dbTx, account.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve equity account: %w", err)
+	}
+
+	// Debit the account when the change follows its normal side, otherwise debit equity
+	debitID, creditID := account.ID, equity.ID
+	if delta.IsPositive() != account.IsDebitNormal() {
+		debitID, creditID = equity.ID, account.ID
+	}
+
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	tx := &models.Transaction{
+		UserID:          account.UserID,
+		Type:            txType,
+		Description:     account.Name,
+		Amount:          delta.Abs(),
+		AmountInUSD:     delta.Abs(),
+		ExchangeRate:    decimal.NewFromInt(1),
+		AccountFromID:   creditID,
+		AccountToID:     &debitID,
+		TransactionDate: date,
+		Notes:           notes,
+	}
+
+	if err := tx.Validate(); err != nil {
+		return nil, fmt.Errorf("transaction validation failed: %w", err)
+	}
+
+	if err := s.processTransaction(dbTx, tx); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
 }
 
 // VerifyTransactionBalance verifies that a transaction's journal entries balance
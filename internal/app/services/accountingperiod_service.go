@@ -0,0 +1,115 @@
+package services
+
+import (
+	"arabella-api/internal/app/dtos"
+	"arabella-api/internal/app/models"
+	"arabella-api/internal/app/repositories"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// AccountingPeriodService handles closing and reopening of accounting periods.
+// The lock itself is enforced by the AccountingEngineService when posting or reversing.
+type AccountingPeriodService interface {
+	GetByUser(userID uint) ([]dtos.AccountingPeriodResponse, error)
+	Close(userID uint, req *dtos.AccountingPeriodActionRequest) (*dtos.AccountingPeriodResponse, error)
+	Reopen(userID uint, req *dtos.AccountingPeriodActionRequest) (*dtos.AccountingPeriodResponse, error)
+	GetEvents(userID, periodID uint) ([]dtos.AccountingPeriodEventResponse, error)
+}
+
+type accountingPeriodService struct {
+	periodRepo repositories.AccountingPeriodRepository
+}
+
+// NewAccountingPeriodService creates a new accounting period service
+func NewAccountingPeriodService(periodRepo repositories.AccountingPeriodRepository) AccountingPeriodService {
+	return &accountingPeriodService{
+		periodRepo: periodRepo,
+	}
+}
+
+// GetByUser retrieves every period the user has ever closed
+func (s *accountingPeriodService) GetByUser(userID uint) ([]dtos.AccountingPeriodResponse, error) {
+	periods, err := s.periodRepo.FindByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dtos.AccountingPeriodResponse, len(periods))
+	for i, period := range periods {
+		responses[i] = dtos.FromModelToAccountingPeriodResponse(period)
+	}
+
+	return responses, nil
+}
+
+// Close locks a month so no entry dated inside it can be posted or reversed
+func (s *accountingPeriodService) Close(userID uint, req *dtos.AccountingPeriodActionRequest) (*dtos.AccountingPeriodResponse, error) {
+	period, err := s.periodRepo.Transition(userID, req.Year, req.Month, func(period *models.AccountingPeriod) (*models.AccountingPeriodEvent, error) {
+		if period.IsClosed() {
+			return nil, fmt.Errorf("accounting period %04d-%02d is already closed", req.Year, req.Month)
+		}
+
+		now := time.Now()
+		if period.StartDate().After(now) {
+			return nil, fmt.Errorf("accounting period %04d-%02d has not started yet", req.Year, req.Month)
+		}
+
+		period.Status = "CLOSED"
+		period.ClosedAt = &now
+
+		return &models.AccountingPeriodEvent{UserID: userID, Action: "CLOSE", Reason: req.Reason}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := dtos.FromModelToAccountingPeriodResponse(period)
+	return &response, nil
+}
+
+// Reopen unlocks a closed month. Only the owner of the period can reopen it.
+func (s *accountingPeriodService) Reopen(userID uint, req *dtos.AccountingPeriodActionRequest) (*dtos.AccountingPeriodResponse, error) {
+	period, err := s.periodRepo.Transition(userID, req.Year, req.Month, func(period *models.AccountingPeriod) (*models.AccountingPeriodEvent, error) {
+		if !period.IsClosed() {
+			return nil, fmt.Errorf("accounting period %04d-%02d is not closed", req.Year, req.Month)
+		}
+
+		now := time.Now()
+		period.Status = "OPEN"
+		period.ReopenedAt = &now
+
+		return &models.AccountingPeriodEvent{UserID: userID, Action: "REOPEN", Reason: req.Reason}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := dtos.FromModelToAccountingPeriodResponse(period)
+	return &response, nil
+}
+
+// GetEvents retrieves the close/reopen audit trail of a period owned by the user
+func (s *accountingPeriodService) GetEvents(userID, periodID uint) ([]dtos.AccountingPeriodEventResponse, error) {
+	period, err := s.periodRepo.FindByID(periodID)
+	if err != nil {
+		return nil, err
+	}
+
+	if period.UserID != userID {
+		return nil, errors.New("accounting period not found")
+	}
+
+	events, err := s.periodRepo.FindEvents(period.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dtos.AccountingPeriodEventResponse, len(events))
+	for i, event := range events {
+		responses[i] = dtos.FromModelToAccountingPeriodEventResponse(event)
+	}
+
+	return responses, nil
+}
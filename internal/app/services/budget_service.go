@@ -0,0 +1,410 @@
+package services
+
+import (
+	"arabella-api/internal/app/dtos"
+	"arabella-api/internal/app/models"
+	"arabella-api/internal/app/repositories"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrBudgetOverlap is returned when a category would get two budgets covering the same days
+var ErrBudgetOverlap = errors.New("the category already has a budget for that period")
+
+// budgetWarningPercent is the share of the available amount from which a budget is flagged WARNING
+const budgetWarningPercent = 80
+
+// BudgetService manages category budgets and reports their spending.
+// Spending is computed on the fly from the EXPENSE transactions posted to the category,
+// so edits and reversals are always reflected.
+type BudgetService interface {
+	Create(userID uint, req *dtos.CreateBudgetRequest) (*dtos.BudgetResponse, error)
+	GetByUser(userID uint, asOf time.Time) ([]dtos.BudgetResponse, error)
+	GetByID(userID, id uint, asOf time.Time) (*dtos.BudgetResponse, error)
+	GetPeriods(userID, id uint, asOf time.Time) ([]dtos.BudgetPeriodResponse, error)
+	Update(userID, id uint, req *dtos.UpdateBudgetRequest) (*dtos.BudgetResponse, error)
+	Delete(userID, id uint) error
+	GetSummary(userID uint, asOf time.Time) (*dtos.BudgetSummary, error)
+}
+
+type budgetService struct {
+	budgetRepo   repositories.BudgetRepository
+	categoryRepo repositories.CategoryRepository
+}
+
+// NewBudgetService creates a new budget service
+func NewBudgetService(
+	budgetRepo repositories.BudgetRepository,
+	categoryRepo repositories.CategoryRepository,
+) BudgetService {
+	return &budgetService{
+		budgetRepo:   budgetRepo,
+		categoryRepo: categoryRepo,
+	}
+}
+
+// Create creates a budget on one of the user's expense categories
+func (s *budgetService) Create(userID uint, req *dtos.CreateBudgetRequest) (*dtos.BudgetResponse, error) {
+	startDate := time.Now().UTC()
+	if req.StartDate != "" {
+		parsed, err := parseScheduleDate(req.StartDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start_date: %w", err)
+		}
+		startDate = parsed.UTC()
+	}
+
+	// Periods start at midnight UTC; monthly budgets follow calendar months
+	startDate = time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, time.UTC)
+	if req.Period == "MONTHLY" {
+		startDate = startDate.AddDate(0, 0, 1-startDate.Day())
+	}
+
+	budget := &models.Budget{
+		UserID:     userID,
+		CategoryID: req.CategoryID,
+		Amount:     req.Amount,
+		Period:     req.Period,
+		PeriodDays: req.PeriodDays,
+		StartDate:  startDate,
+		Rollover:   req.Rollover,
+		Notes:      req.Notes,
+	}
+	if budget.Rollover == "" {
+		budget.Rollover = "NONE"
+	}
+
+	if req.EndDate != nil && *req.EndDate != "" {
+		endDate, err := parseScheduleDate(*req.EndDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid end_date: %w", err)
+		}
+		budget.EndDate = &endDate
+	}
+
+	if err := s.validateBudget(budget); err != nil {
+		return nil, err
+	}
+
+	if err := s.budgetRepo.Create(budget); err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(userID, budget.ID, time.Now())
+}
+
+// GetByUser returns every budget of the user with the figures of the period containing asOf
+func (s *budgetService) GetByUser(userID uint, asOf time.Time) ([]dtos.BudgetResponse, error) {
+	budgets, err := s.budgetRepo.FindByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	periods, err := s.evaluate(userID, budgets, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dtos.BudgetResponse, len(budgets))
+	for i, budget := range budgets {
+		responses[i] = budgetResponse(budget, periods[budget.ID], asOf)
+	}
+
+	return responses, nil
+}
+
+// GetByID returns a budget with the figures of the period containing asOf
+func (s *budgetService) GetByID(userID, id uint, asOf time.Time) (*dtos.BudgetResponse, error) {
+	budget, err := s.findOwned(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	periods, err := s.evaluate(userID, []*models.Budget{budget}, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	response := budgetResponse(budget, periods[budget.ID], asOf)
+	return &response, nil
+}
+
+// GetPeriods returns every period of a budget up to the one containing asOf, oldest first,
+// showing how the rollover built up
+func (s *budgetService) GetPeriods(userID, id uint, asOf time.Time) ([]dtos.BudgetPeriodResponse, error) {
+	budget, err := s.findOwned(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	periods, err := s.evaluate(userID, []*models.Budget{budget}, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	if periods[budget.ID] == nil {
+		return []dtos.BudgetPeriodResponse{}, nil
+	}
+	return periods[budget.ID], nil
+}
+
+// Update updates the amount, end date, rollover or notes of a budget
+func (s *budgetService) Update(userID, id uint, req *dtos.UpdateBudgetRequest) (*dtos.BudgetResponse, error) {
+	budget, err := s.findOwned(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Amount != nil {
+		budget.Amount = *req.Amount
+	}
+	if req.EndDate != nil {
+		budget.EndDate = nil
+		if *req.EndDate != "" {
+			endDate, err := parseScheduleDate(*req.EndDate)
+			if err != nil {
+				return nil, fmt.Errorf("invalid end_date: %w", err)
+			}
+			budget.EndDate = &endDate
+		}
+	}
+	if req.Rollover != nil {
+		budget.Rollover = *req.Rollover
+	}
+	if req.Notes != nil {
+		budget.Notes = *req.Notes
+	}
+
+	if err := s.validateBudget(budget); err != nil {
+		return nil, err
+	}
+
+	if err := s.budgetRepo.Update(budget); err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(userID, budget.ID, time.Now())
+}
+
+// Delete soft deletes a budget; transactions are not affected
+func (s *budgetService) Delete(userID, id uint) error {
+	budget, err := s.findOwned(userID, id)
+	if err != nil {
+		return err
+	}
+
+	return s.budgetRepo.Delete(budget.ID)
+}
+
+// GetSummary aggregates the budgets with a period in progress at asOf
+func (s *budgetService) GetSummary(userID uint, asOf time.Time) (*dtos.BudgetSummary, error) {
+	budgets, err := s.GetByUser(userID, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &dtos.BudgetSummary{
+		AsOf:    asOf,
+		Budgets: []dtos.BudgetResponse{},
+	}
+
+	for _, budget := range budgets {
+		if budget.Current == nil {
+			continue
+		}
+
+		summary.Budgets = append(summary.Budgets, budget)
+		summary.TotalAvailable = summary.TotalAvailable.Add(budget.Current.Available)
+		summary.TotalSpent = summary.TotalSpent.Add(budget.Current.Spent)
+		summary.TotalRemaining = summary.TotalRemaining.Add(budget.Current.Remaining)
+
+		switch budget.Current.Status {
+		case "OVER":
+			summary.Over++
+		case "WARNING":
+			summary.Warning++
+		default:
+			summary.OnTrack++
+		}
+	}
+
+	summary.PercentUsed = percentUsed(summary.TotalSpent, summary.TotalAvailable)
+
+	return summary, nil
+}
+
+// evaluate computes the periods of every budget up to the one containing asOf (or up to its last
+// period if it already ended), with a single query for the expenses of all of them
+func (s *budgetService) evaluate(userID uint, budgets []*models.Budget, asOf time.Time) (map[uint][]dtos.BudgetPeriodResponse, error) {
+	lastPeriods := make(map[uint]int, len(budgets))
+	categoryIDs := []uint{}
+	var from, to time.Time
+
+	for _, budget := range budgets {
+		last, ok := budget.PeriodIndex(asOf)
+		if !ok {
+			if asOf.Before(budget.PeriodStart(0)) {
+				continue
+			}
+			// Ended: report every period up to the last one
+			last, _ = budget.PeriodIndex(*budget.EndDate)
+		}
+		lastPeriods[budget.ID] = last
+
+		start, end := budget.PeriodStart(0), budget.PeriodStart(last+1)
+		if len(categoryIDs) == 0 || start.Before(from) {
+			from = start
+		}
+		if end.After(to) {
+			to = end
+		}
+		categoryIDs = append(categoryIDs, budget.CategoryID)
+	}
+
+	periods := make(map[uint][]dtos.BudgetPeriodResponse, len(lastPeriods))
+	if len(lastPeriods) == 0 {
+		return periods, nil
+	}
+
+	expenses, err := s.budgetRepo.FindExpenses(userID, categoryIDs, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, budget := range budgets {
+		if last, ok := lastPeriods[budget.ID]; ok {
+			periods[budget.ID] = budgetPeriods(budget, expenses, last)
+		}
+	}
+
+	return periods, nil
+}
+
+// budgetPeriods buckets the category's expenses into the periods 0..last and applies the rollover
+func budgetPeriods(budget *models.Budget, expenses []repositories.BudgetExpense, last int) []dtos.BudgetPeriodResponse {
+	spent := make([]decimal.Decimal, last+1)
+	for _, expense := range expenses {
+		if expense.CategoryID != budget.CategoryID {
+			continue
+		}
+		if n, ok := budget.PeriodIndex(expense.TransactionDate); ok && n <= last {
+			spent[n] = spent[n].Add(expense.Amount)
+		}
+	}
+
+	periods := make([]dtos.BudgetPeriodResponse, last+1)
+	carry := decimal.Zero
+	for n := 0; n <= last; n++ {
+		available := budget.Amount.Add(carry)
+		remaining := available.Sub(spent[n])
+
+		periods[n] = dtos.BudgetPeriodResponse{
+			Index:       n,
+			StartDate:   budget.PeriodStart(n),
+			EndDate:     budget.PeriodStart(n + 1).Add(-time.Second),
+			Budgeted:    budget.Amount,
+			RolledOver:  carry,
+			Available:   available,
+			Spent:       spent[n],
+			Remaining:   remaining,
+			PercentUsed: percentUsed(spent[n], available),
+			Status:      budgetStatus(spent[n], available),
+		}
+
+		switch budget.Rollover {
+		case "UNSPENT":
+			carry = decimal.Max(remaining, decimal.Zero)
+		case "ALL":
+			carry = remaining
+		default:
+			carry = decimal.Zero
+		}
+	}
+
+	return periods
+}
+
+// budgetResponse builds the response of a budget with the period containing asOf as Current
+func budgetResponse(budget *models.Budget, periods []dtos.BudgetPeriodResponse, asOf time.Time) dtos.BudgetResponse {
+	response := dtos.FromModelToBudgetResponse(budget)
+
+	if n, ok := budget.PeriodIndex(asOf); ok && n < len(periods) {
+		current := periods[n]
+		response.Current = &current
+	}
+
+	return response
+}
+
+// percentUsed returns spent as a percentage of available (100 once spending exceeds a non-positive amount)
+func percentUsed(spent, available decimal.Decimal) float64 {
+	if !available.IsPositive() {
+		if spent.IsPositive() {
+			return 100
+		}
+		return 0
+	}
+
+	percent, _ := spent.Div(available).Mul(decimal.NewFromInt(100)).Round(2).Float64()
+	return percent
+}
+
+// budgetStatus classifies the spending of a period against its available amount
+func budgetStatus(spent, available decimal.Decimal) string {
+	if spent.GreaterThan(available) {
+		return "OVER"
+	}
+	if percentUsed(spent, available) >= budgetWarningPercent {
+		return "WARNING"
+	}
+	return "ON_TRACK"
+}
+
+// findOwned loads a budget and checks it belongs to the user
+func (s *budgetService) findOwned(userID, id uint) (*models.Budget, error) {
+	budget, err := s.budgetRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if budget.UserID != userID {
+		return nil, errors.New("budget not found")
+	}
+
+	return budget, nil
+}
+
+// validateBudget checks the budget, that its category is one of the user's expense categories
+// and that it does not overlap another budget of the same category
+func (s *budgetService) validateBudget(budget *models.Budget) error {
+	if err := budget.Validate(); err != nil {
+		return err
+	}
+
+	category, err := s.categoryRepo.FindByID(budget.CategoryID)
+	if err != nil {
+		return err
+	}
+	if category.UserID != budget.UserID {
+		return errors.New("category not found")
+	}
+	if strings.ToUpper(category.Type) != "EXPENSE" {
+		return fmt.Errorf("budgets can only be set on expense categories, %q is %s", category.Name, category.Type)
+	}
+
+	others, err := s.budgetRepo.FindByCategory(budget.UserID, budget.CategoryID)
+	if err != nil {
+		return err
+	}
+	for _, other := range others {
+		if other.ID != budget.ID && other.Overlaps(budget) {
+			return fmt.Errorf("%w (budget %d)", ErrBudgetOverlap, other.ID)
+		}
+	}
+
+	return nil
+}
@@ -14,21 +14,31 @@ type DashboardService interface {
 	GetDashboard(userID uint) (*dtos.DashboardResponse, error)
 	CalculateRunway(userID uint) (*dtos.RunwayCalculation, error)
 	GetMonthlyStats(userID uint, month, year int) (*dtos.MonthlyStats, error)
+	GetProjection(userID uint, until time.Time) (*dtos.BalanceProjection, error)
 }
 
 type dashboardService struct {
-	accountRepo     repositories.AccountRepository
-	transactionRepo repositories.TransactionRepository
+	accountRepo        repositories.AccountRepository
+	transactionRepo    repositories.TransactionRepository
+	accountingEngine   AccountingEngineService
+	budgetService      BudgetService
+	savingsGoalService SavingsGoalService
 }
 
 // NewDashboardService creates a new dashboard service
 func NewDashboardService(
 	accountRepo repositories.AccountRepository,
 	transactionRepo repositories.TransactionRepository,
+	accountingEngine AccountingEngineService,
+	budgetService BudgetService,
+	savingsGoalService SavingsGoalService,
 ) DashboardService {
 	return &dashboardService{
-		accountRepo:     accountRepo,
-		transactionRepo: transactionRepo,
+		accountRepo:        accountRepo,
+		transactionRepo:    transactionRepo,
+		accountingEngine:   accountingEngine,
+		budgetService:      budgetService,
+		savingsGoalService: savingsGoalService,
 	}
 }
 
@@ -67,8 +77,17 @@ func (s *dashboardService) GetDashboard(userID uint) (*dtos.DashboardResponse, e
 
 	monthlyNetCashFlow := monthlyIncome.Sub(monthlyExpenses)
 
-	// Calculate runway
-	runway, runwayDays, avgMonthlyExpenses := s.calculateRunway(userID, liquidAssets, totalLiabilities)
+	monthlyDebtPayments, err := s.transactionRepo.GetMonthlyDebtPayments(userID, currentMonth, currentYear)
+	if err != nil {
+		return nil, err
+	}
+
+	// Calculate runway (loans are repaid in installments: only short-term debt counts against it)
+	shortTermLiabilities, err := s.accountRepo.GetShortTermLiabilities(userID)
+	if err != nil {
+		return nil, err
+	}
+	runway, runwayDays, avgMonthlyExpenses := s.calculateRunway(userID, liquidAssets, shortTermLiabilities)
 
 	// Get account balances
 	accounts, err := s.accountRepo.FindByUserID(userID)
@@ -76,6 +95,18 @@ func (s *dashboardService) GetDashboard(userID uint) (*dtos.DashboardResponse, e
 		return nil, err
 	}
 
+	// Budgets with a period in progress
+	budgets, err := s.budgetService.GetSummary(userID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// Progress of every savings goal
+	savingsGoals, err := s.savingsGoalService.GetSummary(userID)
+	if err != nil {
+		return nil, err
+	}
+
 	accountBalances := make([]dtos.AccountBalanceDTO, len(accounts))
 	for i, account := range accounts {
 		currencyCode := "USD" // default
@@ -104,10 +135,13 @@ func (s *dashboardService) GetDashboard(userID uint) (*dtos.DashboardResponse, e
 		MonthlyIncome:          monthlyIncome,
 		MonthlyExpenses:        monthlyExpenses,
 		MonthlyNetCashFlow:     monthlyNetCashFlow,
+		MonthlyDebtPayments:    monthlyDebtPayments,
 		Runway:                 runway,
 		RunwayDays:             runwayDays,
 		AverageMonthlyExpenses: avgMonthlyExpenses,
 		AccountBalances:        accountBalances,
+		Budgets:                budgets,
+		SavingsGoals:           savingsGoals,
 		AsOf:                   now,
 		BaseCurrency:           "USD", // TODO: Make this configurable per user
 	}, nil
@@ -162,7 +196,7 @@ func (s *dashboardService) CalculateRunway(userID uint) (*dtos.RunwayCalculation
 		return nil, err
 	}
 
-	liabilities, err := s.accountRepo.GetTotalLiabilities(userID)
+	liabilities, err := s.accountRepo.GetShortTermLiabilities(userID)
 	if err != nil {
 		return nil, err
 	}
@@ -214,16 +248,105 @@ func (s *dashboardService) GetMonthlyStats(userID uint, month, year int) (*dtos.
 
 	netCashFlow := income.Sub(expenses)
 
+	debtPayments, err := s.transactionRepo.GetMonthlyDebtPayments(userID, month, year)
+	if err != nil {
+		return nil, err
+	}
+
 	return &dtos.MonthlyStats{
 		Month:            month,
 		Year:             year,
 		Income:           income,
 		Expenses:         expenses,
 		NetCashFlow:      netCashFlow,
+		DebtPayments:     debtPayments,
 		TransactionCount: int(count),
 	}, nil
 }
 
+// GetProjection projects the user's balances to until with the pending transactions dated up to then.
+// Each pending transaction is previewed through the accounting engine, so the projection follows the
+// exact posting rules (splits, debt payments, liabilities) without writing anything to the ledger.
+func (s *dashboardService) GetProjection(userID uint, until time.Time) (*dtos.BalanceProjection, error) {
+	liquidAssets, err := s.accountRepo.GetLiquidAssets(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	totalAssets, err := s.accountRepo.GetTotalAssets(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	totalLiabilities, err := s.accountRepo.GetTotalLiabilities(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := s.transactionRepo.FindPending(userID, until)
+	if err != nil {
+		return nil, err
+	}
+
+	projection := &dtos.BalanceProjection{
+		AsOf:                  time.Now(),
+		Until:                 until,
+		LiquidAssets:          liquidAssets,
+		ProjectedLiquidAssets: liquidAssets,
+		NetWorth:              totalAssets.Sub(totalLiabilities),
+		ProjectedNetWorth:     totalAssets.Sub(totalLiabilities),
+		Accounts:              []dtos.AccountProjection{},
+		PendingTransactions:   make([]dtos.TransactionResponse, len(pending)),
+	}
+
+	for i, tx := range pending {
+		projection.PendingTransactions[i] = dtos.FromModelToTransactionResponse(tx)
+	}
+
+	// Net the pending changes per account
+	changes, err := s.accountingEngine.PreviewBalanceChanges(pending)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts, err := s.accountRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, account := range accounts {
+		change, ok := changes[account.ID]
+		if !ok || account.IsSystemManaged() {
+			continue
+		}
+
+		projection.Accounts = append(projection.Accounts, dtos.AccountProjection{
+			ID:               account.ID,
+			Name:             account.Name,
+			Type:             account.AccountType,
+			CurrentBalance:   account.Balance,
+			PendingChange:    change,
+			ProjectedBalance: account.Balance.Add(change),
+		})
+
+		// The totals only include active accounts
+		if !account.IsActive {
+			continue
+		}
+		if account.IsLiquidAsset() {
+			projection.ProjectedLiquidAssets = projection.ProjectedLiquidAssets.Add(change)
+		}
+		switch account.GetClassification() {
+		case "ASSET":
+			projection.ProjectedNetWorth = projection.ProjectedNetWorth.Add(change)
+		case "LIABILITY":
+			projection.ProjectedNetWorth = projection.ProjectedNetWorth.Sub(change)
+		}
+	}
+
+	return projection, nil
+}
+
 // Helper function to convert accounts to balance DTOs
 func convertAccountsToBalanceDTO(accounts []*models.Account) []dtos.AccountBalanceDTO {
 	result := make([]dtos.AccountBalanceDTO, len(accounts))
@@ -0,0 +1,457 @@
+package services
+
+import (
+	"arabella-api/internal/app/dtos"
+	"arabella-api/internal/app/models"
+	"arabella-api/internal/app/repositories"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// debtPayoffMaxMonths is the simulation horizon: debts still owed after it are reported as not paid off
+	debtPayoffMaxMonths = 600
+	// debtDueSoonDays is how close a due date has to be for a debt to be DUE_SOON
+	debtDueSoonDays = 7
+)
+
+// DebtService works on the user's liability accounts (CREDIT_CARD, LOAN and MORTGAGE) as a whole
+type DebtService interface {
+	SimulatePayoff(userID uint, req *dtos.DebtPayoffSimulationRequest) (*dtos.DebtPayoffSimulationResponse, error)
+	GetUpcoming(userID uint, days int) (*dtos.UpcomingDebtsResponse, error)
+}
+
+type debtService struct {
+	accountRepo      repositories.AccountRepository
+	journalEntryRepo repositories.JournalEntryRepository
+}
+
+// NewDebtService creates a new debt service
+func NewDebtService(accountRepo repositories.AccountRepository, journalEntryRepo repositories.JournalEntryRepository) DebtService {
+	return &debtService{
+		accountRepo:      accountRepo,
+		journalEntryRepo: journalEntryRepo,
+	}
+}
+
+// simulatedDebt is one debt with the terms used by the simulation
+type simulatedDebt struct {
+	account *models.Account
+	rate    decimal.Decimal // Annual, in percent
+	minimum decimal.Decimal
+}
+
+// SimulatePayoff simulates, month by month, paying off the user's debts with a fixed monthly budget:
+// the minimum payments plus ExtraMonthlyPayment. Every month each debt accrues a month of interest
+// on its balance and receives its minimum payment; the rest of the budget (including the minimums
+// of the debts already paid off) goes to the first debt still owed in the strategy's order.
+// Months are calendar months starting at the month of StartDate.
+func (s *debtService) SimulatePayoff(userID uint, req *dtos.DebtPayoffSimulationRequest) (*dtos.DebtPayoffSimulationResponse, error) {
+	if req.ExtraMonthlyPayment.IsNegative() {
+		return nil, errors.New("extra_monthly_payment cannot be negative")
+	}
+
+	start := time.Now()
+	if req.StartDate != "" {
+		parsed, err := parseScheduleDate(req.StartDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start_date: %w", err)
+		}
+		start = parsed
+	}
+	start = time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	debts, err := s.loadDebts(userID, req.Debts)
+	if err != nil {
+		return nil, err
+	}
+	if len(debts) == 0 {
+		return nil, errors.New("there are no debts to pay off")
+	}
+
+	budget := req.ExtraMonthlyPayment
+	summaries := make([]dtos.DebtSummary, 0, len(debts))
+	for _, debt := range debts {
+		budget = budget.Add(debt.minimum)
+		summaries = append(summaries, dtos.DebtSummary{
+			AccountID:      debt.account.ID,
+			AccountName:    debt.account.Name,
+			AccountType:    debt.account.AccountType,
+			Balance:        debt.account.Balance,
+			InterestRate:   debt.rate,
+			MinimumPayment: debt.minimum,
+		})
+	}
+
+	avalanche := sortedDebtIDs(debts, func(a, b *simulatedDebt) bool {
+		if !a.rate.Equal(b.rate) {
+			return a.rate.GreaterThan(b.rate)
+		}
+		return a.account.Balance.LessThan(b.account.Balance)
+	})
+	snowball := sortedDebtIDs(debts, func(a, b *simulatedDebt) bool {
+		if !a.account.Balance.Equal(b.account.Balance) {
+			return a.account.Balance.LessThan(b.account.Balance)
+		}
+		return a.rate.GreaterThan(b.rate)
+	})
+
+	strategies := []dtos.DebtPayoffStrategy{
+		simulateDebtPayoff("AVALANCHE", debts, avalanche, budget, start),
+		simulateDebtPayoff("SNOWBALL", debts, snowball, budget, start),
+	}
+
+	if len(req.CustomOrder) > 0 {
+		custom, err := customDebtOrder(debts, req.CustomOrder, avalanche)
+		if err != nil {
+			return nil, err
+		}
+		strategies = append(strategies, simulateDebtPayoff("CUSTOM", debts, custom, budget, start))
+	}
+
+	resp := &dtos.DebtPayoffSimulationResponse{
+		StartDate:           start,
+		ExtraMonthlyPayment: req.ExtraMonthlyPayment,
+		MonthlyBudget:       budget,
+		Debts:               summaries,
+		Strategies:          strategies,
+	}
+
+	var best *dtos.DebtPayoffStrategy
+	for i := range strategies {
+		if strategies[i].PaidOff && (best == nil || strategies[i].TotalInterest.LessThan(best.TotalInterest)) {
+			best = &strategies[i]
+		}
+	}
+	if best != nil {
+		resp.Recommended = best.Strategy
+	}
+
+	return resp, nil
+}
+
+// loadDebts returns the user's liability accounts with a balance owed and their simulation terms.
+// LOAN and MORTGAGE accounts default to the rate and installment of their terms, and credit cards to
+// their rate and the minimum payment of their current balance; the terms in the request override them
+// and are required for cards without terms.
+func (s *debtService) loadDebts(userID uint, inputs []dtos.DebtTermsInput) ([]*simulatedDebt, error) {
+	accounts, err := s.accountRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	terms := make(map[uint]dtos.DebtTermsInput, len(inputs))
+	for _, input := range inputs {
+		if _, exists := terms[input.AccountID]; exists {
+			return nil, fmt.Errorf("duplicate terms for account %d", input.AccountID)
+		}
+		terms[input.AccountID] = input
+	}
+
+	var debts []*simulatedDebt
+	for _, account := range accounts {
+		if !account.IsLiability() {
+			continue
+		}
+
+		// Terms for a debt already paid off are accepted and ignored
+		input, hasTerms := terms[account.ID]
+		delete(terms, account.ID)
+		if !account.Balance.IsPositive() {
+			continue
+		}
+
+		debt := &simulatedDebt{account: account}
+		hasDefaults := true
+		switch {
+		case account.Loan != nil:
+			debt.rate = account.Loan.InterestRate
+			debt.minimum = account.Loan.Installment()
+		case account.CreditCard != nil:
+			debt.rate = account.CreditCard.InterestRate
+			debt.minimum = account.CreditCard.MinimumPayment(account.Balance)
+		default:
+			hasDefaults = false
+		}
+
+		if hasTerms && input.InterestRate != nil {
+			debt.rate = *input.InterestRate
+		} else if !hasDefaults {
+			return nil, fmt.Errorf("interest_rate is required for %q", account.Name)
+		}
+
+		if hasTerms && input.MinimumPayment != nil {
+			debt.minimum = *input.MinimumPayment
+		} else if !hasDefaults {
+			return nil, fmt.Errorf("minimum_payment is required for %q", account.Name)
+		}
+
+		if debt.rate.IsNegative() {
+			return nil, fmt.Errorf("interest_rate of %q cannot be negative", account.Name)
+		}
+		if !debt.minimum.IsPositive() {
+			return nil, fmt.Errorf("minimum_payment of %q must be positive", account.Name)
+		}
+
+		debts = append(debts, debt)
+	}
+
+	for accountID := range terms {
+		return nil, fmt.Errorf("account %d is not one of your debts", accountID)
+	}
+
+	return debts, nil
+}
+
+// GetUpcoming returns the next payment of the user's credit cards (with statement terms) and loans,
+// sorted by due date. Overdue card statements are always listed; the others only when due within days.
+// Loan installments follow their schedule: payments made ahead of time do not move the next due date.
+func (s *debtService) GetUpcoming(userID uint, days int) (*dtos.UpcomingDebtsResponse, error) {
+	if days < 1 || days > 366 {
+		return nil, errors.New("days must be between 1 and 366")
+	}
+
+	accounts, err := s.accountRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	resp := &dtos.UpcomingDebtsResponse{
+		AsOf:  today,
+		Until: today.AddDate(0, 0, days),
+		Debts: []dtos.UpcomingDebt{},
+	}
+
+	for _, account := range accounts {
+		debt := dtos.UpcomingDebt{
+			AccountID:   account.ID,
+			AccountName: account.Name,
+			AccountType: account.AccountType,
+			Balance:     account.Balance,
+		}
+
+		switch {
+		case account.IsLoan() && account.Loan != nil:
+			_, due, ok := account.Loan.NextDueDate(today)
+			if !ok || !account.Balance.IsPositive() {
+				continue
+			}
+			debt.DueDate = due
+			debt.AmountDue = decimal.Min(account.Loan.Installment(), account.Balance)
+			debt.MinimumPayment = debt.AmountDue
+
+		case account.AccountType == "CREDIT_CARD" && account.CreditCard != nil:
+			statement, err := buildCardStatement(s.journalEntryRepo, account, today)
+			if err != nil {
+				return nil, err
+			}
+
+			// Once the last statement is settled (or its due date has passed with the minimum paid)
+			// the next payment is the one of the statement in progress
+			if statement.Status == "OVERDUE" || (statement.StatementDue.IsPositive() && !statement.PaymentDueDate.Before(today)) {
+				debt.DueDate = statement.PaymentDueDate
+				debt.AmountDue = statement.StatementDue
+				debt.MinimumPayment = statement.MinimumPaymentDue
+			} else {
+				debt.DueDate = account.CreditCard.DueDateFor(statement.NextStatementDate)
+				debt.AmountDue = statement.Balance
+				debt.MinimumPayment = account.CreditCard.MinimumPayment(statement.Balance)
+			}
+			if !debt.AmountDue.IsPositive() {
+				continue
+			}
+
+		default:
+			continue
+		}
+
+		debt.DaysUntilDue = int(debt.DueDate.Sub(today).Hours() / 24)
+		switch {
+		case debt.DaysUntilDue < 0:
+			debt.Status = "OVERDUE"
+		case debt.DaysUntilDue <= debtDueSoonDays:
+			debt.Status = "DUE_SOON"
+		default:
+			debt.Status = "UPCOMING"
+		}
+
+		if debt.DueDate.After(resp.Until) {
+			continue
+		}
+
+		resp.Debts = append(resp.Debts, debt)
+		resp.TotalDue = resp.TotalDue.Add(debt.AmountDue)
+		resp.TotalMinimum = resp.TotalMinimum.Add(debt.MinimumPayment)
+	}
+
+	sort.SliceStable(resp.Debts, func(i, j int) bool {
+		a, b := resp.Debts[i], resp.Debts[j]
+		if !a.DueDate.Equal(b.DueDate) {
+			return a.DueDate.Before(b.DueDate)
+		}
+		if !a.AmountDue.Equal(b.AmountDue) {
+			return a.AmountDue.GreaterThan(b.AmountDue)
+		}
+		return a.AccountID < b.AccountID
+	})
+
+	return resp, nil
+}
+
+// sortedDebtIDs returns the account IDs of the debts ordered by less (ties by account ID)
+func sortedDebtIDs(debts []*simulatedDebt, less func(a, b *simulatedDebt) bool) []uint {
+	sorted := make([]*simulatedDebt, len(debts))
+	copy(sorted, debts)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if less(sorted[i], sorted[j]) {
+			return true
+		}
+		if less(sorted[j], sorted[i]) {
+			return false
+		}
+		return sorted[i].account.ID < sorted[j].account.ID
+	})
+
+	ids := make([]uint, len(sorted))
+	for i, debt := range sorted {
+		ids[i] = debt.account.ID
+	}
+	return ids
+}
+
+// customDebtOrder validates the order requested by the user and completes it with the debts it
+// leaves out, in the fallback order
+func customDebtOrder(debts []*simulatedDebt, requested, fallback []uint) ([]uint, error) {
+	known := make(map[uint]bool, len(debts))
+	for _, debt := range debts {
+		known[debt.account.ID] = true
+	}
+
+	order := make([]uint, 0, len(debts))
+	listed := make(map[uint]bool, len(requested))
+	for _, id := range requested {
+		if !known[id] {
+			return nil, fmt.Errorf("custom_order: account %d is not one of your debts", id)
+		}
+		if listed[id] {
+			return nil, fmt.Errorf("custom_order: account %d is listed twice", id)
+		}
+		listed[id] = true
+		order = append(order, id)
+	}
+
+	for _, id := range fallback {
+		if !listed[id] {
+			order = append(order, id)
+		}
+	}
+
+	return order, nil
+}
+
+// simulateDebtPayoff runs the month-by-month simulation of one strategy
+func simulateDebtPayoff(strategy string, debts []*simulatedDebt, order []uint, budget decimal.Decimal, start time.Time) dtos.DebtPayoffStrategy {
+	balances := make(map[uint]decimal.Decimal, len(debts))
+	schedules := make(map[uint]*dtos.DebtPayoffSchedule, len(debts))
+	for _, debt := range debts {
+		balances[debt.account.ID] = debt.account.Balance
+		schedules[debt.account.ID] = &dtos.DebtPayoffSchedule{
+			AccountID:   debt.account.ID,
+			AccountName: debt.account.Name,
+		}
+	}
+
+	result := dtos.DebtPayoffStrategy{
+		Strategy: strategy,
+		Order:    order,
+	}
+
+	owed := len(debts)
+	for month := 1; owed > 0 && month <= debtPayoffMaxMonths; month++ {
+		date := start.AddDate(0, month-1, 0)
+		available := budget
+		interest := make(map[uint]decimal.Decimal, owed)
+		payments := make(map[uint]decimal.Decimal, owed)
+
+		// Interest accrues on the balance owed at the start of the month, then the minimums are paid
+		for _, debt := range debts {
+			id := debt.account.ID
+			if !balances[id].IsPositive() {
+				continue
+			}
+
+			interest[id] = balances[id].Mul(debt.rate).Div(decimal.NewFromInt(1200)).Round(2)
+			balances[id] = balances[id].Add(interest[id])
+
+			payment := decimal.Min(debt.minimum, balances[id])
+			payments[id] = payment
+			balances[id] = balances[id].Sub(payment)
+			available = available.Sub(payment)
+		}
+
+		// What is left of the budget goes to the debts in the strategy's order
+		for _, id := range order {
+			if !available.IsPositive() {
+				break
+			}
+			if !balances[id].IsPositive() {
+				continue
+			}
+
+			extra := decimal.Min(available, balances[id])
+			payments[id] = payments[id].Add(extra)
+			balances[id] = balances[id].Sub(extra)
+			available = available.Sub(extra)
+		}
+
+		for _, debt := range debts {
+			id := debt.account.ID
+			payment, active := payments[id]
+			if !active {
+				continue
+			}
+
+			schedule := schedules[id]
+			schedule.Months = month
+			schedule.TotalInterest = schedule.TotalInterest.Add(interest[id])
+			schedule.TotalPaid = schedule.TotalPaid.Add(payment)
+			schedule.Schedule = append(schedule.Schedule, dtos.DebtPayoffMonth{
+				Month:    month,
+				Date:     date,
+				Payment:  payment,
+				Interest: interest[id],
+				Balance:  balances[id],
+			})
+
+			if !balances[id].IsPositive() {
+				paidOffAt := date
+				schedule.PaidOff = true
+				schedule.PayoffDate = &paidOffAt
+				owed--
+			}
+		}
+
+		result.Months = month
+	}
+
+	result.PaidOff = owed == 0
+	result.Debts = make([]dtos.DebtPayoffSchedule, 0, len(order))
+	for _, id := range order {
+		schedule := schedules[id]
+		result.TotalInterest = result.TotalInterest.Add(schedule.TotalInterest)
+		result.TotalPaid = result.TotalPaid.Add(schedule.TotalPaid)
+		if result.PaidOff && schedule.PayoffDate != nil && (result.PayoffDate == nil || schedule.PayoffDate.After(*result.PayoffDate)) {
+			result.PayoffDate = schedule.PayoffDate
+		}
+		result.Debts = append(result.Debts, *schedule)
+	}
+
+	return result
+}
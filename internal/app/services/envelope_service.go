@@ -0,0 +1,347 @@
+package services
+
+import (
+	"arabella-api/internal/app/dtos"
+	"arabella-api/internal/app/models"
+	"arabella-api/internal/app/repositories"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrEnvelopeExists is returned when a category already has an envelope
+	ErrEnvelopeExists = errors.New("the category already has an envelope")
+	// ErrInsufficientEnvelopeFunds is returned when a move takes more than its source holds
+	ErrInsufficientEnvelopeFunds = errors.New("insufficient funds to move")
+)
+
+// EnvelopeService allocates the user's liquid funds (BANK + CASH, as GetLiquidAssets) to envelopes,
+// one per expense category. Balances are computed on the fly from the moves and the EXPENSE
+// transactions of each category, so edits and reversals are always reflected. Expenses paid with
+// a credit card draw the envelope down as well, although the liquid funds only drop when the card is paid.
+type EnvelopeService interface {
+	Create(userID uint, req *dtos.CreateEnvelopeRequest) (*dtos.EnvelopeResponse, error)
+	GetByUser(userID uint) ([]dtos.EnvelopeResponse, error)
+	GetByID(userID, id uint) (*dtos.EnvelopeResponse, error)
+	Delete(userID, id uint) error
+	Move(userID uint, req *dtos.MoveEnvelopeFundsRequest) (*dtos.EnvelopeSummary, error)
+	GetMoves(userID uint, envelopeID *uint) ([]dtos.EnvelopeMoveResponse, error)
+	GetSummary(userID uint) (*dtos.EnvelopeSummary, error)
+}
+
+type envelopeService struct {
+	envelopeRepo repositories.EnvelopeRepository
+	categoryRepo repositories.CategoryRepository
+}
+
+// NewEnvelopeService creates a new envelope service
+func NewEnvelopeService(
+	envelopeRepo repositories.EnvelopeRepository,
+	categoryRepo repositories.CategoryRepository,
+) EnvelopeService {
+	return &envelopeService{
+		envelopeRepo: envelopeRepo,
+		categoryRepo: categoryRepo,
+	}
+}
+
+// Create creates the envelope of one of the user's expense categories, optionally assigning it
+// an initial amount from the unassigned funds
+func (s *envelopeService) Create(userID uint, req *dtos.CreateEnvelopeRequest) (*dtos.EnvelopeResponse, error) {
+	category, err := s.categoryRepo.FindByID(req.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	if category.UserID != userID {
+		return nil, errors.New("category not found")
+	}
+	if strings.ToUpper(category.Type) != "EXPENSE" {
+		return nil, fmt.Errorf("envelopes can only be created for expense categories, %q is %s", category.Name, category.Type)
+	}
+
+	startDate := time.Now().UTC()
+	if req.StartDate != "" {
+		parsed, err := parseScheduleDate(req.StartDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start_date: %w", err)
+		}
+		startDate = parsed.UTC()
+	}
+	startDate = time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, time.UTC)
+
+	if req.Amount != nil && !req.Amount.IsPositive() {
+		return nil, fmt.Errorf("amount must be positive, got: %s", req.Amount.String())
+	}
+
+	envelope := &models.Envelope{
+		UserID:     userID,
+		CategoryID: category.ID,
+		StartDate:  startDate,
+		Notes:      req.Notes,
+	}
+
+	// The envelope and its initial assignment are written together, under the user's lock
+	err = s.envelopeRepo.WithUserLock(userID, func(repo repositories.EnvelopeRepository) error {
+		if _, err := repo.FindByCategory(userID, category.ID); err == nil {
+			return ErrEnvelopeExists
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if req.Amount != nil {
+			if err := checkUnassigned(repo, userID, *req.Amount); err != nil {
+				return err
+			}
+		}
+
+		if err := repo.Create(envelope); err != nil {
+			return err
+		}
+
+		if req.Amount == nil {
+			return nil
+		}
+
+		return repo.CreateMove(&models.EnvelopeMove{
+			UserID:       userID,
+			ToEnvelopeID: &envelope.ID,
+			Amount:       *req.Amount,
+			MoveDate:     time.Now(),
+			Notes:        "Initial assignment",
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(userID, envelope.ID)
+}
+
+// GetByUser returns every envelope of the user with its balance
+func (s *envelopeService) GetByUser(userID uint) ([]dtos.EnvelopeResponse, error) {
+	return evaluateEnvelopes(s.envelopeRepo, userID)
+}
+
+// GetByID returns an envelope with its balance
+func (s *envelopeService) GetByID(userID, id uint) (*dtos.EnvelopeResponse, error) {
+	return findEnvelopeBalance(s.envelopeRepo, userID, id)
+}
+
+// Delete soft deletes an envelope; its balance goes back to the unassigned funds
+func (s *envelopeService) Delete(userID, id uint) error {
+	envelope, err := findOwnedEnvelope(s.envelopeRepo, userID, id)
+	if err != nil {
+		return err
+	}
+
+	return s.envelopeRepo.Delete(envelope.ID)
+}
+
+// findEnvelopeBalance returns one of the user's envelopes with its balance, read through repo
+func findEnvelopeBalance(repo repositories.EnvelopeRepository, userID, id uint) (*dtos.EnvelopeResponse, error) {
+	if _, err := findOwnedEnvelope(repo, userID, id); err != nil {
+		return nil, err
+	}
+
+	envelopes, err := evaluateEnvelopes(repo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range envelopes {
+		if envelopes[i].ID == id {
+			return &envelopes[i], nil
+		}
+	}
+
+	return nil, errors.New("envelope not found")
+}
+
+// Move moves funds into, out of or between envelopes. The source (an envelope, or the unassigned
+// funds when FromEnvelopeID is nil) must hold at least the amount.
+func (s *envelopeService) Move(userID uint, req *dtos.MoveEnvelopeFundsRequest) (*dtos.EnvelopeSummary, error) {
+	if req.FromEnvelopeID == nil && req.ToEnvelopeID == nil {
+		return nil, errors.New("from_envelope_id or to_envelope_id is required")
+	}
+
+	// The source balance is checked and the move written under the user's lock, so two
+	// concurrent moves cannot both spend the same funds
+	err := s.envelopeRepo.WithUserLock(userID, func(repo repositories.EnvelopeRepository) error {
+		if req.ToEnvelopeID != nil {
+			if _, err := findOwnedEnvelope(repo, userID, *req.ToEnvelopeID); err != nil {
+				return err
+			}
+		}
+
+		if req.FromEnvelopeID != nil {
+			source, err := findEnvelopeBalance(repo, userID, *req.FromEnvelopeID)
+			if err != nil {
+				return err
+			}
+			if req.Amount.GreaterThan(source.Balance) {
+				return fmt.Errorf("%w: envelope %q holds %s", ErrInsufficientEnvelopeFunds,
+					source.CategoryName, decimal.Max(source.Balance, decimal.Zero).String())
+			}
+		} else if err := checkUnassigned(repo, userID, req.Amount); err != nil {
+			return err
+		}
+
+		return repo.CreateMove(&models.EnvelopeMove{
+			UserID:         userID,
+			FromEnvelopeID: req.FromEnvelopeID,
+			ToEnvelopeID:   req.ToEnvelopeID,
+			Amount:         req.Amount,
+			MoveDate:       time.Now(),
+			Notes:          req.Notes,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetSummary(userID)
+}
+
+// GetMoves returns the moves of the user, most recent first; with envelopeID, only those of that envelope
+func (s *envelopeService) GetMoves(userID uint, envelopeID *uint) ([]dtos.EnvelopeMoveResponse, error) {
+	if envelopeID != nil {
+		if _, err := findOwnedEnvelope(s.envelopeRepo, userID, *envelopeID); err != nil {
+			return nil, err
+		}
+	}
+
+	moves, err := s.envelopeRepo.FindMoves(userID, envelopeID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dtos.EnvelopeMoveResponse, len(moves))
+	for i, move := range moves {
+		responses[i] = dtos.FromModelToEnvelopeMoveResponse(move)
+	}
+
+	return responses, nil
+}
+
+// GetSummary reports the liquid funds, what is allocated to envelopes and what is still unassigned
+func (s *envelopeService) GetSummary(userID uint) (*dtos.EnvelopeSummary, error) {
+	return summarizeEnvelopes(s.envelopeRepo, userID)
+}
+
+// summarizeEnvelopes builds the user's envelope summary, read through repo
+func summarizeEnvelopes(repo repositories.EnvelopeRepository, userID uint) (*dtos.EnvelopeSummary, error) {
+	liquidAssets, err := repo.GetLiquidAssets(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	envelopes, err := evaluateEnvelopes(repo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &dtos.EnvelopeSummary{
+		AsOf:         time.Now(),
+		LiquidAssets: liquidAssets,
+		Envelopes:    envelopes,
+	}
+
+	for _, envelope := range envelopes {
+		if envelope.Balance.IsNegative() {
+			summary.Overspent = summary.Overspent.Add(envelope.Balance.Neg())
+		} else {
+			summary.Allocated = summary.Allocated.Add(envelope.Balance)
+		}
+	}
+
+	summary.Unassigned = liquidAssets.Sub(summary.Allocated)
+
+	return summary, nil
+}
+
+// evaluateEnvelopes computes the balance of every envelope of the user. Reads go through repo, so inside
+// WithUserLock they use the locked transaction instead of a second connection.
+func evaluateEnvelopes(repo repositories.EnvelopeRepository, userID uint) ([]dtos.EnvelopeResponse, error) {
+	envelopes, err := repo.FindByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	moved, err := repo.GetMovedTotals(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	spent, err := repo.GetSpentTotals(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dtos.EnvelopeResponse, len(envelopes))
+	index := make(map[uint]*dtos.EnvelopeResponse, len(envelopes))
+	for i, envelope := range envelopes {
+		responses[i] = dtos.EnvelopeResponse{
+			ID:         envelope.ID,
+			CategoryID: envelope.CategoryID,
+			StartDate:  envelope.StartDate,
+			Notes:      envelope.Notes,
+			CreatedAt:  envelope.CreatedAt,
+		}
+		if envelope.Category != nil {
+			responses[i].CategoryName = envelope.Category.Name
+		}
+		index[envelope.ID] = &responses[i]
+	}
+
+	// Totals of deleted envelopes are ignored: their funds are back in the unassigned ones
+	for _, total := range moved {
+		if envelope, ok := index[total.EnvelopeID]; ok {
+			envelope.Assigned = envelope.Assigned.Add(total.Amount)
+		}
+	}
+	for _, total := range spent {
+		if envelope, ok := index[total.EnvelopeID]; ok {
+			envelope.Spent = envelope.Spent.Add(total.Amount)
+		}
+	}
+
+	for i := range responses {
+		responses[i].Balance = responses[i].Assigned.Sub(responses[i].Spent)
+	}
+
+	return responses, nil
+}
+
+// checkUnassigned checks that the unassigned funds cover amount
+func checkUnassigned(repo repositories.EnvelopeRepository, userID uint, amount decimal.Decimal) error {
+	summary, err := summarizeEnvelopes(repo, userID)
+	if err != nil {
+		return err
+	}
+
+	if amount.GreaterThan(summary.Unassigned) {
+		return fmt.Errorf("%w: %s unassigned", ErrInsufficientEnvelopeFunds,
+			decimal.Max(summary.Unassigned, decimal.Zero).String())
+	}
+
+	return nil
+}
+
+// findOwnedEnvelope loads an envelope and checks it belongs to the user
+func findOwnedEnvelope(repo repositories.EnvelopeRepository, userID, id uint) (*models.Envelope, error) {
+	envelope, err := repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if envelope.UserID != userID {
+		return nil, errors.New("envelope not found")
+	}
+
+	return envelope, nil
+}
@@ -3,6 +3,10 @@ package services
 import (
 	"arabella-api/internal/app/dtos"
 	"arabella-api/internal/app/repositories"
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 // JournalEntryService handles journal entry queries (read-only)
@@ -11,16 +15,19 @@ type JournalEntryService interface {
 	GetByTransaction(transactionID uint) ([]dtos.JournalEntryResponse, error)
 	GetByUser(userID uint, filters dtos.JournalEntryFilters) (*dtos.JournalEntryListResponse, error)
 	VerifyTransactionBalance(transactionID uint) (*dtos.BalanceVerificationResponse, error)
+	GetAccountLedger(userID, accountID uint, filters dtos.JournalEntryFilters) (*dtos.AccountLedgerResponse, error)
 }
 
 type journalEntryService struct {
 	journalEntryRepo repositories.JournalEntryRepository
+	accountRepo      repositories.AccountRepository
 }
 
 // NewJournalEntryService creates a new journal entry service
-func NewJournalEntryService(journalEntryRepo repositories.JournalEntryRepository) JournalEntryService {
+func NewJournalEntryService(journalEntryRepo repositories.JournalEntryRepository, accountRepo repositories.AccountRepository) JournalEntryService {
 	return &journalEntryService{
 		journalEntryRepo: journalEntryRepo,
+		accountRepo:      accountRepo,
 	}
 }
 
@@ -95,3 +102,105 @@ func (s *journalEntryService) VerifyTransactionBalance(transactionID uint) (*dto
 		Message:       message,
 	}, nil
 }
+
+// GetAccountLedger returns one page of an account's journal entries in date order with a running
+// balance. The running balance of the first line on the page starts from the balance before
+// StartDate plus every entry of the range that falls on earlier pages.
+func (s *journalEntryService) GetAccountLedger(userID, accountID uint, filters dtos.JournalEntryFilters) (*dtos.AccountLedgerResponse, error) {
+	account, err := s.accountRepo.FindByID(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if account.UserID != userID {
+		return nil, errors.New("account not found")
+	}
+
+	// A running balance only makes sense over every entry of the range, in statement order
+	filters.DebitOrCredit = nil
+	filters.Ascending = true
+
+	entries, total, err := s.journalEntryRepo.FindByAccount(accountID, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	// Same normalization as FindByAccount
+	page := filters.Page
+	if page < 1 {
+		page = 1
+	}
+	pageSize := filters.PageSize
+	if pageSize < 1 {
+		pageSize = 50
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	opening := decimal.Zero
+	if filters.StartDate != nil {
+		// Microsecond precision matches PostgreSQL timestamps
+		before := filters.StartDate.Add(-time.Microsecond)
+		net, err := s.journalEntryRepo.GetAccountBalance(accountID, &before)
+		if err != nil {
+			return nil, err
+		}
+		opening = account.BalanceDelta("DEBIT", net)
+	}
+
+	closingNet, err := s.journalEntryRepo.GetAccountBalance(accountID, filters.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	leading, err := s.journalEntryRepo.GetAccountLeadingBalance(accountID, filters, (page-1)*pageSize)
+	if err != nil {
+		return nil, err
+	}
+	running := opening.Add(account.BalanceDelta("DEBIT", leading))
+
+	lines := make([]dtos.AccountLedgerLine, len(entries))
+	for i, entry := range entries {
+		running = running.Add(account.BalanceDelta(entry.DebitOrCredit, entry.Amount))
+
+		line := dtos.AccountLedgerLine{
+			EntryID:        entry.ID,
+			TransactionID:  entry.TransactionID,
+			EntryDate:      entry.EntryDate,
+			Description:    entry.Description,
+			RunningBalance: running,
+		}
+		if entry.DebitOrCredit == "DEBIT" {
+			line.Debit = entry.Amount
+		} else {
+			line.Credit = entry.Amount
+		}
+		lines[i] = line
+	}
+
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	response := &dtos.AccountLedgerResponse{
+		AccountID:      account.ID,
+		AccountName:    account.Name,
+		AccountType:    account.AccountType,
+		StartDate:      filters.StartDate,
+		EndDate:        filters.EndDate,
+		OpeningBalance: opening,
+		ClosingBalance: account.BalanceDelta("DEBIT", closingNet),
+		Entries:        lines,
+		Total:          total,
+		Page:           page,
+		PageSize:       pageSize,
+		TotalPages:     totalPages,
+	}
+	if account.Currency != nil {
+		response.CurrencyCode = account.Currency.Code
+	}
+
+	return response, nil
+}
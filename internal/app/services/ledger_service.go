@@ -0,0 +1,476 @@
+package services
+
+import (
+	"arabella-api/internal/app/dtos"
+	"arabella-api/internal/app/models"
+	"arabella-api/internal/app/repositories"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LedgerService groups maintenance operations over the general ledger (journal entries + cached balances)
+// These operations are meant for administrators and console commands, not for regular API users
+type LedgerService interface {
+	MigrateCategoryLedgers() (*dtos.CategoryLedgerMigrationReport, error)
+	BackfillOpeningBalances() (*dtos.OpeningBalanceBackfillReport, error)
+	MigrateReversals() (*dtos.ReversalMigrationReport, error)
+	Verify(userID *uint, fix bool) (*dtos.LedgerVerificationReport, error)
+}
+
+type ledgerService struct {
+	db                     *gorm.DB
+	journalEntryRepository repositories.JournalEntryRepository
+	accountingEngine       AccountingEngineService
+}
+
+// NewLedgerService creates a new ledger service
+func NewLedgerService(
+	db *gorm.DB,
+	journalEntryRepo repositories.JournalEntryRepository,
+	accountingEngine AccountingEngineService,
+) LedgerService {
+	return &ledgerService{
+		db:                     db,
+		journalEntryRepository: journalEntryRepo,
+		accountingEngine:       accountingEngine,
+	}
+}
+
+// MigrateCategoryLedgers moves existing data to the nominal ledger account scheme.
+// Before, INCOME/EXPENSE journal entries stored the CategoryID in JournalEntry.AccountID.
+// In a single database transaction this:
+// 1. Backfills Account.Classification for existing real accounts
+// 2. Creates a nominal CATEGORY account for every category that lacks one
+// 3. Rewrites the category-side journal entries to reference the category's ledger account
+// 4. Recomputes the cached balance of every nominal account from its journal entries
+//
+// Before step 1, the CREDIT_CARD balances written by the old engine (which subtracted expenses from them)
+// are negated, since liabilities now hold the amount owed as a positive balance. Only unclassified
+// (pre-migration) cards are touched, so this happens once per card.
+//
+// It is idempotent: entries that already reference one of the transaction's ledger accounts are skipped.
+func (s *ledgerService) MigrateCategoryLedgers() (*dtos.CategoryLedgerMigrationReport, error) {
+	report := &dtos.CategoryLedgerMigrationReport{UnresolvedEntryIDs: []uint{}}
+
+	err := s.db.Transaction(func(dbTx *gorm.DB) error {
+		// Step 1: Fix the sign of legacy credit card balances, then classify existing real accounts
+		result := dbTx.Unscoped().Model(&models.Account{}).
+			Where("(classification IS NULL OR classification = '') AND account_type = ?", "CREDIT_CARD").
+			Updates(map[string]interface{}{
+				"balance": gorm.Expr("-balance"),
+				"version": gorm.Expr("version + 1"),
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to fix credit card balances: %w", result.Error)
+		}
+		report.CreditCardsResigned = result.RowsAffected
+
+		result = dbTx.Unscoped().Model(&models.Account{}).
+			Where("(classification IS NULL OR classification = '') AND account_type <> ?", "CATEGORY").
+			Update("classification", gorm.Expr("CASE WHEN account_type IN ('CREDIT_CARD', 'LOAN', 'MORTGAGE') THEN 'LIABILITY' ELSE 'ASSET' END"))
+		if result.Error != nil {
+			return fmt.Errorf("failed to classify accounts: %w", result.Error)
+		}
+		report.AccountsClassified = result.RowsAffected
+
+		// Step 2: Ensure every category (including soft-deleted ones) has a ledger account
+		var categories []*models.Category
+		if err := dbTx.Unscoped().Find(&categories).Error; err != nil {
+			return fmt.Errorf("failed to load categories: %w", err)
+		}
+
+		ledgerByCategory := make(map[uint]uint, len(categories))
+		for _, category := range categories {
+			if category.LedgerAccountID == nil {
+				report.LedgerAccountsCreated++
+			}
+			ledgerID, err := repositories.EnsureCategoryLedgerAccount(dbTx, category)
+			if err != nil {
+				return err
+			}
+			ledgerByCategory[category.ID] = ledgerID
+		}
+
+		ledgerCreatedAt, err := s.loadLedgerCreationTimes(dbTx, ledgerByCategory)
+		if err != nil {
+			return err
+		}
+
+		// Step 3: Repoint category-side entries of every INCOME/EXPENSE transaction
+		var transactions []*models.Transaction
+		if err := dbTx.Unscoped().
+			Preload("Splits").
+			Where("type IN ?", []string{"INCOME", "EXPENSE"}).
+			Find(&transactions).Error; err != nil {
+			return fmt.Errorf("failed to load transactions: %w", err)
+		}
+
+		for _, tx := range transactions {
+			if err := s.migrateTransactionEntries(dbTx, tx, ledgerByCategory, ledgerCreatedAt, report); err != nil {
+				return err
+			}
+		}
+
+		// Step 4: Rebuild the cached balance of the nominal accounts
+		return s.recomputeNominalBalances(dbTx, ledgerByCategory)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// MigrateReversals moves data to the transaction status lifecycle.
+// Before, a reversal posted its entries under the original's TransactionID (described "REVERSAL: ...")
+// and flagged the original is_reconciled. In a single database transaction, for every such original this:
+// 1. Creates the reversal record, dated at its first reversing entry
+// 2. Repoints the reversing entries to it
+// 3. Sets the original's status (REVERSED if it was replaced by an edit, VOIDED otherwise),
+// links it to the reversal record and clears the is_reconciled flag the old reversal set
+//
+// It is idempotent: once moved, the reversing entries no longer sit under the original.
+// Run it after MigrateCategoryLedgers, which resolves category entries through the original's splits.
+func (s *ledgerService) MigrateReversals() (*dtos.ReversalMigrationReport, error) {
+	report := &dtos.ReversalMigrationReport{}
+
+	err := s.db.Transaction(func(dbTx *gorm.DB) error {
+		var transactionIDs []uint
+		if err := dbTx.Model(&models.JournalEntry{}).
+			Joins("JOIN transactions ON transactions.id = journal_entries.transaction_id").
+			Where("journal_entries.description LIKE ? AND transactions.reverses_transaction_id IS NULL", "REVERSAL: %").
+			Distinct().
+			Order("journal_entries.transaction_id ASC").
+			Pluck("journal_entries.transaction_id", &transactionIDs).Error; err != nil {
+			return fmt.Errorf("failed to find legacy reversals: %w", err)
+		}
+
+		for _, transactionID := range transactionIDs {
+			var tx models.Transaction
+			if err := dbTx.Unscoped().First(&tx, transactionID).Error; err != nil {
+				return fmt.Errorf("transaction %d: %w", transactionID, err)
+			}
+
+			var firstEntry models.JournalEntry
+			if err := dbTx.Where("transaction_id = ? AND description LIKE ?", tx.ID, "REVERSAL: %").
+				Order("entry_date ASC, id ASC").
+				First(&firstEntry).Error; err != nil {
+				return fmt.Errorf("transaction %d: %w", tx.ID, err)
+			}
+
+			// Step 1: Reversal record
+			reversal := reversalRecordFor(&tx, firstEntry.EntryDate)
+			if err := dbTx.Omit("AccountFrom", "AccountTo", "Category", "Splits").Create(reversal).Error; err != nil {
+				return fmt.Errorf("transaction %d: failed to create reversal record: %w", tx.ID, err)
+			}
+
+			// Step 2: Reversing entries
+			result := dbTx.Model(&models.JournalEntry{}).
+				Where("transaction_id = ? AND description LIKE ?", tx.ID, "REVERSAL: %").
+				Update("transaction_id", reversal.ID)
+			if result.Error != nil {
+				return fmt.Errorf("transaction %d: failed to move reversing entries: %w", tx.ID, result.Error)
+			}
+			report.EntriesMoved += result.RowsAffected
+
+			// Step 3: Status and link on the original
+			status := "VOIDED"
+			if tx.ReplacedByTransactionID != nil {
+				status = "REVERSED"
+			}
+			if err := dbTx.Unscoped().Model(&tx).Updates(map[string]interface{}{
+				"status":                     status,
+				"reversed_by_transaction_id": reversal.ID,
+				"is_reconciled":              false,
+			}).Error; err != nil {
+				return fmt.Errorf("transaction %d: failed to update status: %w", tx.ID, err)
+			}
+
+			report.TransactionsMigrated++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// BackfillOpeningBalances journals the balances that were written directly to accounts before
+// opening balances went through the Accounting Engine. For every real account whose cached balance
+// differs from its journal, an OPENING_BALANCE transaction for the difference is posted against equity
+// (dated at the account's creation), so the ledger reproduces the stored balance.
+// Each account is handled in its own database transaction; running it again is a no-op.
+func (s *ledgerService) BackfillOpeningBalances() (*dtos.OpeningBalanceBackfillReport, error) {
+	report := &dtos.OpeningBalanceBackfillReport{TransactionIDs: []uint{}}
+
+	var accounts []*models.Account
+	if err := s.db.Where("account_type NOT IN ?", []string{"CATEGORY", "EQUITY"}).Order("id ASC").Find(&accounts).Error; err != nil {
+		return nil, fmt.Errorf("failed to load accounts: %w", err)
+	}
+
+	for _, account := range accounts {
+		report.AccountsChecked++
+
+		tx, err := s.accountingEngine.BackfillOpeningBalance(account.ID)
+		if err != nil {
+			return report, fmt.Errorf("account %d: %w", account.ID, err)
+		}
+		if tx != nil {
+			report.TransactionIDs = append(report.TransactionIDs, tx.ID)
+		}
+	}
+
+	return report, nil
+}
+
+// Verify compares the cached Account.Balance of every account with the balance recomputed from
+// journal_entries (GetAccountBalance), and checks that the entries of every transaction balance
+// (VerifyTransactionBalance). The check covers every user unless userID is set.
+//
+// With fix=true, the cached balance of every drifted account is rebuilt from the journal inside
+// a single database transaction. Unbalanced transactions are only reported: they need a correcting entry.
+func (s *ledgerService) Verify(userID *uint, fix bool) (*dtos.LedgerVerificationReport, error) {
+	report := &dtos.LedgerVerificationReport{
+		UserID:                 userID,
+		AccountDrifts:          []dtos.AccountBalanceDrift{},
+		UnbalancedTransactions: []dtos.UnbalancedTransaction{},
+	}
+
+	// Step 1: Cached vs journal balance of every account (system ledger accounts included)
+	accountQuery := s.db.Order("user_id ASC, id ASC")
+	if userID != nil {
+		accountQuery = accountQuery.Where("user_id = ?", *userID)
+	}
+
+	var accounts []*models.Account
+	if err := accountQuery.Find(&accounts).Error; err != nil {
+		return nil, fmt.Errorf("failed to load accounts: %w", err)
+	}
+
+	for _, account := range accounts {
+		report.AccountsChecked++
+
+		// GetAccountBalance returns Debits - Credits; flip it for credit-normal accounts
+		debitMinusCredit, err := s.journalEntryRepository.GetAccountBalance(account.ID, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute ledger balance of account %d: %w", account.ID, err)
+		}
+		ledger := account.BalanceDelta("DEBIT", debitMinusCredit)
+
+		if !ledger.Equal(account.Balance) {
+			report.AccountDrifts = append(report.AccountDrifts, dtos.AccountBalanceDrift{
+				UserID:        account.UserID,
+				AccountID:     account.ID,
+				AccountName:   account.Name,
+				AccountType:   account.AccountType,
+				CachedBalance: account.Balance,
+				LedgerBalance: ledger,
+				Difference:    account.Balance.Sub(ledger),
+			})
+		}
+	}
+
+	// Step 2: Debits = Credits for every transaction
+	transactionQuery := s.db.Order("user_id ASC, id ASC")
+	if userID != nil {
+		transactionQuery = transactionQuery.Where("user_id = ?", *userID)
+	}
+
+	var transactions []*models.Transaction
+	if err := transactionQuery.Find(&transactions).Error; err != nil {
+		return nil, fmt.Errorf("failed to load transactions: %w", err)
+	}
+
+	for _, tx := range transactions {
+		report.TransactionsChecked++
+
+		totalDebits, totalCredits, err := s.journalEntryRepository.VerifyTransactionBalance(tx.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify transaction %d: %w", tx.ID, err)
+		}
+
+		if !totalDebits.Equal(totalCredits) {
+			report.UnbalancedTransactions = append(report.UnbalancedTransactions, dtos.UnbalancedTransaction{
+				UserID:        tx.UserID,
+				TransactionID: tx.ID,
+				Type:          tx.Type,
+				TotalDebits:   totalDebits,
+				TotalCredits:  totalCredits,
+			})
+		}
+	}
+
+	if !fix || len(report.AccountDrifts) == 0 {
+		return report, nil
+	}
+
+	// Step 3: Rebuild the drifted cached balances atomically
+	err := s.db.Transaction(func(dbTx *gorm.DB) error {
+		for _, drift := range report.AccountDrifts {
+			var account models.Account
+			if err := dbTx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&account, drift.AccountID).Error; err != nil {
+				return fmt.Errorf("account %d not found: %w", drift.AccountID, err)
+			}
+
+			// Recompute under the lock so entries posted since Step 1 are included
+			balance, err := journalBalance(dbTx, &account)
+			if err != nil {
+				return err
+			}
+
+			if err := dbTx.Model(&account).Updates(map[string]interface{}{
+				"balance": balance,
+				"version": gorm.Expr("version + 1"),
+			}).Error; err != nil {
+				return fmt.Errorf("failed to rebuild balance of account %d: %w", account.ID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report.Fixed = true
+	return report, nil
+}
+
+// migrateTransactionEntries rewrites the category-side entries of a single transaction
+func (s *ledgerService) migrateTransactionEntries(
+	dbTx *gorm.DB,
+	tx *models.Transaction,
+	ledgerByCategory map[uint]uint,
+	ledgerCreatedAt map[uint]time.Time,
+	report *dtos.CategoryLedgerMigrationReport,
+) error {
+	// Categories referenced by the transaction and their ledger accounts
+	categoryIDs := map[uint]bool{}
+	if tx.CategoryID != nil {
+		categoryIDs[*tx.CategoryID] = true
+	}
+	for _, leg := range tx.Splits {
+		if leg.CategoryID != nil {
+			categoryIDs[*leg.CategoryID] = true
+		}
+	}
+
+	ledgerIDs := map[uint]bool{}
+	for categoryID := range categoryIDs {
+		if ledgerID, ok := ledgerByCategory[categoryID]; ok {
+			ledgerIDs[ledgerID] = true
+		}
+	}
+
+	var entries []*models.JournalEntry
+	if err := dbTx.Where("transaction_id = ?", tx.ID).Find(&entries).Error; err != nil {
+		return fmt.Errorf("failed to load journal entries of transaction %d: %w", tx.ID, err)
+	}
+
+	for _, entry := range entries {
+		if !isCategorySideEntry(entry.Description) {
+			continue
+		}
+
+		// An ID that is both a ledger account and a category of this transaction is ambiguous.
+		// Legacy entries were never updated after the ledger account was created, migrated ones were.
+		if ledgerIDs[entry.AccountID] &&
+			(!categoryIDs[entry.AccountID] || !entry.UpdatedAt.Before(ledgerCreatedAt[entry.AccountID])) {
+			report.EntriesAlreadyMigrated++
+			continue
+		}
+
+		ledgerID, ok := ledgerByCategory[entry.AccountID]
+		if !ok || !categoryIDs[entry.AccountID] {
+			report.UnresolvedEntryIDs = append(report.UnresolvedEntryIDs, entry.ID)
+			continue
+		}
+
+		if err := dbTx.Model(&models.JournalEntry{}).
+			Where("id = ?", entry.ID).
+			Update("account_id", ledgerID).Error; err != nil {
+			return fmt.Errorf("failed to rewrite journal entry %d: %w", entry.ID, err)
+		}
+		report.EntriesRewritten++
+	}
+
+	return nil
+}
+
+// loadLedgerCreationTimes returns the creation time of every ledger account, keyed by account ID
+func (s *ledgerService) loadLedgerCreationTimes(dbTx *gorm.DB, ledgerByCategory map[uint]uint) (map[uint]time.Time, error) {
+	ids := make([]uint, 0, len(ledgerByCategory))
+	for _, ledgerID := range ledgerByCategory {
+		ids = append(ids, ledgerID)
+	}
+
+	var accounts []*models.Account
+	if err := dbTx.Unscoped().Select("id", "created_at").Where("id IN ?", ids).Find(&accounts).Error; err != nil {
+		return nil, fmt.Errorf("failed to load ledger accounts: %w", err)
+	}
+
+	createdAt := make(map[uint]time.Time, len(accounts))
+	for _, account := range accounts {
+		createdAt[account.ID] = account.CreatedAt
+	}
+	return createdAt, nil
+}
+
+// recomputeNominalBalances sets the cached balance of each nominal account from its journal entries
+func (s *ledgerService) recomputeNominalBalances(dbTx *gorm.DB, ledgerByCategory map[uint]uint) error {
+	for _, ledgerID := range ledgerByCategory {
+		var account models.Account
+		if err := dbTx.Unscoped().First(&account, ledgerID).Error; err != nil {
+			return fmt.Errorf("ledger account %d not found: %w", ledgerID, err)
+		}
+
+		balance, err := journalBalance(dbTx, &account)
+		if err != nil {
+			return err
+		}
+
+		if err := dbTx.Unscoped().Model(&models.Account{}).
+			Where("id = ?", ledgerID).
+			Updates(map[string]interface{}{
+				"balance": balance,
+				"version": gorm.Expr("version + 1"),
+			}).Error; err != nil {
+			return fmt.Errorf("failed to update balance of ledger account %d: %w", ledgerID, err)
+		}
+	}
+
+	return nil
+}
+
+// journalBalance computes an account's balance from its journal entries, signed by the account's
+// normal side (see Account.BalanceDelta) so it is directly comparable with the cached Account.Balance
+func journalBalance(db *gorm.DB, account *models.Account) (decimal.Decimal, error) {
+	var totals struct {
+		TotalDebits  decimal.Decimal
+		TotalCredits decimal.Decimal
+	}
+	if err := db.Model(&models.JournalEntry{}).
+		Select(`COALESCE(SUM(CASE WHEN debit_or_credit = 'DEBIT' THEN amount ELSE 0 END), 0) AS total_debits,
+			COALESCE(SUM(CASE WHEN debit_or_credit = 'CREDIT' THEN amount ELSE 0 END), 0) AS total_credits`).
+		Where("account_id = ?", account.ID).
+		Scan(&totals).Error; err != nil {
+		return decimal.Zero, fmt.Errorf("failed to total journal entries of account %d: %w", account.ID, err)
+	}
+
+	return account.BalanceDelta("DEBIT", totals.TotalDebits).Add(account.BalanceDelta("CREDIT", totals.TotalCredits)), nil
+}
+
+// isCategorySideEntry reports whether a journal entry was posted on the category side of an
+// INCOME/EXPENSE transaction. The Accounting Engine labels those entries "Expense: ..." and
+// "Revenue: ...", and reversals prefix the original description with "REVERSAL: ".
+func isCategorySideEntry(description string) bool {
+	description = strings.TrimPrefix(description, "REVERSAL: ")
+	return strings.HasPrefix(description, "Expense: ") || strings.HasPrefix(description, "Revenue: ")
+}
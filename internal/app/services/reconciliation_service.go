@@ -0,0 +1,325 @@
+package services
+
+import (
+	"arabella-api/internal/app/dtos"
+	"arabella-api/internal/app/models"
+	"arabella-api/internal/app/repositories"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ReconciliationService handles the bank/card statement reconciliation workflow:
+// start with the statement's date and ending balance, tick cleared transactions while watching
+// the difference, and complete once it reaches zero. Completing locks the cleared transactions.
+type ReconciliationService interface {
+	Start(userID uint, req *dtos.StartReconciliationRequest) (*dtos.ReconciliationResponse, error)
+	GetByID(userID, id uint) (*dtos.ReconciliationResponse, error)
+	GetByAccount(userID, accountID uint) ([]dtos.ReconciliationResponse, error)
+	SetCleared(userID, id uint, req *dtos.ClearTransactionsRequest) (*dtos.ReconciliationResponse, error)
+	Complete(userID, id uint) (*dtos.ReconciliationResponse, error)
+	Cancel(userID, id uint) error
+}
+
+type reconciliationService struct {
+	reconciliationRepo repositories.ReconciliationRepository
+	accountRepo        repositories.AccountRepository
+}
+
+// NewReconciliationService creates a new reconciliation service
+func NewReconciliationService(
+	reconciliationRepo repositories.ReconciliationRepository,
+	accountRepo repositories.AccountRepository,
+) ReconciliationService {
+	return &reconciliationService{
+		reconciliationRepo: reconciliationRepo,
+		accountRepo:        accountRepo,
+	}
+}
+
+// Start opens a reconciliation for an account. The opening balance is the ending balance of the
+// previous completed reconciliation, so each statement only has to explain its own movements.
+func (s *reconciliationService) Start(userID uint, req *dtos.StartReconciliationRequest) (*dtos.ReconciliationResponse, error) {
+	account, err := s.accountRepo.FindByID(req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if account.UserID != userID {
+		return nil, errors.New("account not found")
+	}
+
+	if account.IsSystemManaged() {
+		return nil, errors.New("system ledger accounts cannot be reconciled")
+	}
+
+	statementDate, err := parseStatementDate(req.StatementDate)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := s.reconciliationRepo.FindInProgress(account.ID)
+	if err == nil {
+		return nil, fmt.Errorf("account %d already has reconciliation %d in progress", account.ID, current.ID)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	reconciliation := &models.Reconciliation{
+		UserID:           userID,
+		AccountID:        account.ID,
+		StatementDate:    statementDate,
+		StatementBalance: req.StatementBalance,
+		Status:           "IN_PROGRESS",
+	}
+
+	last, err := s.reconciliationRepo.FindLastCompleted(account.ID)
+	if err == nil {
+		if !statementDate.After(last.StatementDate) {
+			return nil, fmt.Errorf("statement date must be after the last reconciled statement (%s)", last.StatementDate.Format("2006-01-02"))
+		}
+		reconciliation.OpeningBalance = last.StatementBalance
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if err := s.reconciliationRepo.Create(reconciliation); err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(userID, reconciliation.ID)
+}
+
+// GetByID retrieves a reconciliation with its cleared transactions and, while in progress,
+// the transactions that can still be ticked
+func (s *reconciliationService) GetByID(userID, id uint) (*dtos.ReconciliationResponse, error) {
+	reconciliation, err := s.findOwned(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dtos.FromModelToReconciliationResponse(reconciliation)
+	if reconciliation.IsCompleted() {
+		return &response, nil
+	}
+
+	candidates, err := s.candidates(reconciliation)
+	if err != nil {
+		return nil, err
+	}
+
+	cleared := make(map[uint]bool, len(reconciliation.Items))
+	for _, item := range reconciliation.Items {
+		cleared[item.TransactionID] = true
+	}
+
+	for _, candidate := range candidates {
+		if !cleared[candidate.TransactionID] {
+			response.UnclearedTransactions = append(response.UnclearedTransactions, candidate)
+		}
+	}
+
+	return &response, nil
+}
+
+// GetByAccount retrieves the reconciliation history of an account owned by the user
+func (s *reconciliationService) GetByAccount(userID, accountID uint) ([]dtos.ReconciliationResponse, error) {
+	account, err := s.accountRepo.FindByID(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if account.UserID != userID {
+		return nil, errors.New("account not found")
+	}
+
+	reconciliations, err := s.reconciliationRepo.FindByAccount(account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dtos.ReconciliationResponse, len(reconciliations))
+	for i, reconciliation := range reconciliations {
+		responses[i] = dtos.FromModelToReconciliationResponse(reconciliation)
+		// The list view only carries the totals
+		responses[i].ClearedTransactions = nil
+	}
+
+	return responses, nil
+}
+
+// SetCleared ticks or unticks transactions and returns the reconciliation with its new difference
+func (s *reconciliationService) SetCleared(userID, id uint, req *dtos.ClearTransactionsRequest) (*dtos.ReconciliationResponse, error) {
+	reconciliation, err := s.findOwned(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if reconciliation.IsCompleted() {
+		return nil, fmt.Errorf("reconciliation %d is already completed", reconciliation.ID)
+	}
+
+	if !req.Cleared {
+		if err := s.reconciliationRepo.RemoveItems(reconciliation.ID, req.TransactionIDs); err != nil {
+			return nil, err
+		}
+		return s.GetByID(userID, reconciliation.ID)
+	}
+
+	candidates, err := s.candidatesByTransaction(reconciliation)
+	if err != nil {
+		return nil, err
+	}
+
+	cleared := make(map[uint]bool, len(reconciliation.Items))
+	for _, item := range reconciliation.Items {
+		cleared[item.TransactionID] = true
+	}
+
+	var items []*models.ReconciliationItem
+	for _, transactionID := range req.TransactionIDs {
+		if cleared[transactionID] {
+			continue
+		}
+
+		candidate, ok := candidates[transactionID]
+		if !ok {
+			return nil, fmt.Errorf("transaction %d cannot be cleared: it does not post to this account by the statement date or was already reconciled", transactionID)
+		}
+
+		cleared[transactionID] = true
+		items = append(items, &models.ReconciliationItem{
+			ReconciliationID: reconciliation.ID,
+			TransactionID:    transactionID,
+			Amount:           candidate.Amount,
+		})
+	}
+
+	if err := s.reconciliationRepo.AddItems(items); err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(userID, reconciliation.ID)
+}
+
+// Complete finishes a reconciliation whose difference is zero and locks its cleared transactions
+func (s *reconciliationService) Complete(userID, id uint) (*dtos.ReconciliationResponse, error) {
+	reconciliation, err := s.findOwned(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if reconciliation.IsCompleted() {
+		return nil, fmt.Errorf("reconciliation %d is already completed", reconciliation.ID)
+	}
+
+	// A ticked transaction may have been edited or reversed since; its amount must still hold
+	candidates, err := s.candidatesByTransaction(reconciliation)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range reconciliation.Items {
+		candidate, ok := candidates[item.TransactionID]
+		if !ok || !candidate.Amount.Equal(item.Amount) {
+			return nil, fmt.Errorf("transaction %d changed after it was cleared; untick it and review it again", item.TransactionID)
+		}
+	}
+
+	if difference := reconciliation.Difference(); !difference.IsZero() {
+		return nil, fmt.Errorf("difference must be zero to complete the reconciliation, got: %s", difference.String())
+	}
+
+	now := time.Now()
+	reconciliation.Status = "COMPLETED"
+	reconciliation.CompletedAt = &now
+
+	if err := s.reconciliationRepo.Complete(reconciliation); err != nil {
+		return nil, err
+	}
+
+	response := dtos.FromModelToReconciliationResponse(reconciliation)
+	return &response, nil
+}
+
+// Cancel discards a reconciliation that is still in progress
+func (s *reconciliationService) Cancel(userID, id uint) error {
+	reconciliation, err := s.findOwned(userID, id)
+	if err != nil {
+		return err
+	}
+
+	if reconciliation.IsCompleted() {
+		return fmt.Errorf("reconciliation %d is completed and cannot be cancelled", reconciliation.ID)
+	}
+
+	return s.reconciliationRepo.Delete(reconciliation)
+}
+
+// findOwned loads a reconciliation and checks it belongs to the user
+func (s *reconciliationService) findOwned(userID, id uint) (*models.Reconciliation, error) {
+	reconciliation, err := s.reconciliationRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if reconciliation.UserID != userID {
+		return nil, errors.New("reconciliation not found")
+	}
+
+	return reconciliation, nil
+}
+
+// candidates lists the transactions that can be cleared on the reconciled account,
+// with their effect on the account's balance (normal side)
+func (s *reconciliationService) candidates(reconciliation *models.Reconciliation) ([]dtos.ReconciliationTransactionResponse, error) {
+	rows, err := s.reconciliationRepo.FindUnclearedTransactions(reconciliation.AccountID, reconciliation.StatementDate)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]dtos.ReconciliationTransactionResponse, len(rows))
+	for i, row := range rows {
+		candidates[i] = dtos.ReconciliationTransactionResponse{
+			TransactionID:   row.TransactionID,
+			TransactionDate: row.TransactionDate,
+			Description:     row.Description,
+			Amount:          reconciliation.Account.BalanceDelta("DEBIT", row.Net),
+		}
+	}
+
+	return candidates, nil
+}
+
+// candidatesByTransaction indexes candidates by transaction ID
+func (s *reconciliationService) candidatesByTransaction(reconciliation *models.Reconciliation) (map[uint]dtos.ReconciliationTransactionResponse, error) {
+	candidates, err := s.candidates(reconciliation)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]dtos.ReconciliationTransactionResponse, len(candidates))
+	for _, candidate := range candidates {
+		byID[candidate.TransactionID] = candidate
+	}
+
+	return byID, nil
+}
+
+// parseStatementDate accepts RFC3339 or a plain date; a plain date covers the whole day (UTC)
+func parseStatementDate(value string) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid statement_date: %w", err)
+	}
+
+	// Microsecond precision matches PostgreSQL timestamps
+	return parsed.AddDate(0, 0, 1).Add(-time.Microsecond), nil
+}
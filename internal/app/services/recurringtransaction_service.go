@@ -0,0 +1,476 @@
+package services
+
+import (
+	"arabella-api/internal/app/dtos"
+	"arabella-api/internal/app/models"
+	"arabella-api/internal/app/repositories"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// RecurringTransactionService manages recurring transaction templates and materializes their due
+// occurrences through the accounting engine. Each occurrence is claimed in the recurring_occurrences
+// table in the same DB transaction that posts it, so it is never posted twice.
+type RecurringTransactionService interface {
+	Create(userID uint, req *dtos.CreateRecurringTransactionRequest) (*dtos.RecurringTransactionResponse, error)
+	GetByUser(userID uint) ([]dtos.RecurringTransactionResponse, error)
+	GetByID(userID, id uint) (*dtos.RecurringTransactionResponse, error)
+	Update(userID, id uint, req *dtos.UpdateRecurringTransactionRequest) (*dtos.RecurringTransactionResponse, error)
+	Delete(userID, id uint) error
+	GetOccurrences(userID, id uint, upcoming int) (*dtos.RecurringOccurrencesResponse, error)
+	SetOverride(userID, id uint, req *dtos.RecurringOverrideRequest) (*dtos.RecurringTransactionResponse, error)
+	DeleteOverride(userID, id, overrideID uint) error
+	RunDue(asOf time.Time, userID *uint) (*dtos.RecurringRunReport, error)
+}
+
+type recurringTransactionService struct {
+	recurringRepo    repositories.RecurringTransactionRepository
+	accountRepo      repositories.AccountRepository
+	categoryRepo     repositories.CategoryRepository
+	accountingEngine AccountingEngineService
+}
+
+// NewRecurringTransactionService creates a new recurring transaction service
+func NewRecurringTransactionService(
+	recurringRepo repositories.RecurringTransactionRepository,
+	accountRepo repositories.AccountRepository,
+	categoryRepo repositories.CategoryRepository,
+	accountingEngine AccountingEngineService,
+) RecurringTransactionService {
+	return &recurringTransactionService{
+		recurringRepo:    recurringRepo,
+		accountRepo:      accountRepo,
+		categoryRepo:     categoryRepo,
+		accountingEngine: accountingEngine,
+	}
+}
+
+// Create creates a recurring transaction; its first occurrence is StartDate
+func (s *recurringTransactionService) Create(userID uint, req *dtos.CreateRecurringTransactionRequest) (*dtos.RecurringTransactionResponse, error) {
+	startDate, err := parseScheduleDate(req.StartDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start_date: %w", err)
+	}
+
+	template := &models.RecurringTransaction{
+		UserID:        userID,
+		Type:          req.Type,
+		Description:   req.Description,
+		Amount:        req.Amount,
+		AccountFromID: req.AccountFromID,
+		AccountToID:   req.AccountToID,
+		CategoryID:    req.CategoryID,
+		Notes:         req.Notes,
+		Frequency:     req.Frequency,
+		Interval:      req.Interval,
+		DayOfMonth:    req.DayOfMonth,
+		StartDate:     startDate,
+		Count:         req.Count,
+		IsActive:      true,
+	}
+	if template.Interval == 0 {
+		template.Interval = 1
+	}
+
+	if req.EndDate != nil && *req.EndDate != "" {
+		endDate, err := parseScheduleDate(*req.EndDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid end_date: %w", err)
+		}
+		template.EndDate = &endDate
+	}
+
+	if err := s.validateTemplate(template); err != nil {
+		return nil, err
+	}
+
+	template.Advance(0)
+	if err := s.recurringRepo.Create(template); err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(userID, template.ID)
+}
+
+// GetByUser retrieves the recurring transactions of a user, next due first
+func (s *recurringTransactionService) GetByUser(userID uint) ([]dtos.RecurringTransactionResponse, error) {
+	templates, err := s.recurringRepo.FindByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dtos.RecurringTransactionResponse, len(templates))
+	for i, template := range templates {
+		responses[i] = dtos.FromModelToRecurringTransactionResponse(template)
+	}
+
+	return responses, nil
+}
+
+// GetByID retrieves a recurring transaction owned by the user
+func (s *recurringTransactionService) GetByID(userID, id uint) (*dtos.RecurringTransactionResponse, error) {
+	template, err := s.findOwned(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dtos.FromModelToRecurringTransactionResponse(template)
+	return &response, nil
+}
+
+// Update changes a recurring transaction. New amounts and accounts apply to the occurrences not yet
+// posted; the schedule can only change while nothing was posted.
+func (s *recurringTransactionService) Update(userID, id uint, req *dtos.UpdateRecurringTransactionRequest) (*dtos.RecurringTransactionResponse, error) {
+	template, err := s.findOwned(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Description != nil {
+		template.Description = *req.Description
+	}
+	if req.Amount != nil {
+		template.Amount = *req.Amount
+	}
+	if req.AccountFromID != nil {
+		template.AccountFromID = *req.AccountFromID
+	}
+	if req.AccountToID != nil {
+		template.AccountToID = req.AccountToID
+	}
+	if req.CategoryID != nil {
+		template.CategoryID = req.CategoryID
+	}
+	if req.Notes != nil {
+		template.Notes = *req.Notes
+	}
+	if req.Frequency != nil {
+		template.Frequency = *req.Frequency
+	}
+	if req.Interval != nil {
+		template.Interval = *req.Interval
+	}
+	if req.DayOfMonth != nil {
+		template.DayOfMonth = req.DayOfMonth
+	}
+	if req.StartDate != nil {
+		startDate, err := parseScheduleDate(*req.StartDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start_date: %w", err)
+		}
+		template.StartDate = startDate
+	}
+	if req.EndDate != nil {
+		template.EndDate = nil
+		if *req.EndDate != "" {
+			endDate, err := parseScheduleDate(*req.EndDate)
+			if err != nil {
+				return nil, fmt.Errorf("invalid end_date: %w", err)
+			}
+			template.EndDate = &endDate
+		}
+	}
+	if req.Count != nil {
+		template.Count = nil
+		if *req.Count > 0 {
+			template.Count = req.Count
+		}
+	}
+	if req.IsActive != nil {
+		template.IsActive = *req.IsActive
+	}
+
+	if err := s.validateTemplate(template); err != nil {
+		return nil, err
+	}
+
+	// Checked against the stored progress, locked, so a run posting in between is taken into account
+	err = s.recurringRepo.Update(template, func(current *models.RecurringTransaction) error {
+		if req.ChangesSchedule() && current.NextOccurrence > 0 {
+			return fmt.Errorf("recurring transaction %d already posted %d occurrence(s); its schedule cannot change (set an end date and create a new one)", template.ID, current.NextOccurrence)
+		}
+
+		// Schedule, end date or count may have moved the next occurrence
+		template.Advance(current.NextOccurrence)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(userID, template.ID)
+}
+
+// Delete removes a recurring transaction; the transactions it already posted are kept
+func (s *recurringTransactionService) Delete(userID, id uint) error {
+	template, err := s.findOwned(userID, id)
+	if err != nil {
+		return err
+	}
+
+	return s.recurringRepo.Delete(template.ID)
+}
+
+// GetOccurrences lists the materialized occurrences and up to `upcoming` scheduled ones,
+// with the amounts that will be posted after overrides
+func (s *recurringTransactionService) GetOccurrences(userID, id uint, upcoming int) (*dtos.RecurringOccurrencesResponse, error) {
+	template, err := s.findOwned(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	occurrences, err := s.recurringRepo.FindOccurrences(template.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dtos.RecurringOccurrencesResponse{
+		RecurringTransactionID: template.ID,
+		Posted:                 make([]dtos.RecurringOccurrenceResponse, len(occurrences)),
+		Upcoming:               []dtos.RecurringOccurrenceResponse{},
+	}
+
+	for i, occurrence := range occurrences {
+		response.Posted[i] = dtos.RecurringOccurrenceResponse{
+			Index:         occurrence.OccurrenceIndex,
+			Date:          occurrence.OccurrenceDate,
+			Amount:        occurrence.Amount,
+			Status:        occurrence.Status,
+			TransactionID: occurrence.TransactionID,
+		}
+	}
+
+	for n := template.NextOccurrence; n < template.NextOccurrence+upcoming && template.HasOccurrence(n); n++ {
+		date := template.OccurrenceDate(n)
+		amount, skip := occurrenceAmount(template, date)
+
+		status := "SCHEDULED"
+		if skip {
+			status = "SKIPPED"
+		}
+
+		response.Upcoming = append(response.Upcoming, dtos.RecurringOccurrenceResponse{
+			Index:  n,
+			Date:   date,
+			Amount: amount,
+			Status: status,
+		})
+	}
+
+	return response, nil
+}
+
+// SetOverride changes the amount of an upcoming occurrence, or skips it
+func (s *recurringTransactionService) SetOverride(userID, id uint, req *dtos.RecurringOverrideRequest) (*dtos.RecurringTransactionResponse, error) {
+	template, err := s.findOwned(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	date, err := time.Parse("2006-01-02", req.OccurrenceDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid occurrence_date (expected YYYY-MM-DD): %w", err)
+	}
+
+	if !req.Skip {
+		if req.Amount == nil {
+			return nil, errors.New("amount is required unless the occurrence is skipped")
+		}
+		if !req.Amount.IsPositive() {
+			return nil, fmt.Errorf("amount must be positive, got: %s", req.Amount.String())
+		}
+	}
+
+	if !hasUpcomingOccurrenceOn(template, date) {
+		return nil, fmt.Errorf("recurring transaction %d has no upcoming occurrence on %s", template.ID, req.OccurrenceDate)
+	}
+
+	override := &models.RecurringTransactionOverride{
+		RecurringTransactionID: template.ID,
+		OccurrenceDate:         date,
+		Amount:                 req.Amount,
+		Skip:                   req.Skip,
+	}
+	if req.Skip {
+		override.Amount = nil
+	}
+
+	if err := s.recurringRepo.SaveOverride(override); err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(userID, template.ID)
+}
+
+// DeleteOverride restores the template amount for an occurrence
+func (s *recurringTransactionService) DeleteOverride(userID, id, overrideID uint) error {
+	template, err := s.findOwned(userID, id)
+	if err != nil {
+		return err
+	}
+
+	return s.recurringRepo.DeleteOverride(template.ID, overrideID)
+}
+
+// RunDue materializes every occurrence due by asOf (optionally for one user only), oldest first.
+// Missed occurrences are caught up with their own dates. A template stops at its first failure
+// (e.g. a closed accounting period) and is retried on the next run.
+func (s *recurringTransactionService) RunDue(asOf time.Time, userID *uint) (*dtos.RecurringRunReport, error) {
+	templates, err := s.recurringRepo.FindDue(asOf, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dtos.RecurringRunReport{
+		AsOf:     asOf,
+		Failures: []dtos.RecurringRunFailure{},
+	}
+
+	for _, template := range templates {
+		report.TemplatesProcessed++
+
+		for template.NextOccurrenceDate != nil && !template.NextOccurrenceDate.After(asOf) {
+			date := *template.NextOccurrenceDate
+			amount, skip := occurrenceAmount(template, date)
+
+			occurrence := &models.RecurringOccurrence{
+				RecurringTransactionID: template.ID,
+				OccurrenceIndex:        template.NextOccurrence,
+				OccurrenceDate:         date,
+				Amount:                 amount,
+				Status:                 "POSTED",
+			}
+
+			var tx *models.Transaction
+			if skip {
+				occurrence.Status = "SKIPPED"
+				occurrence.Amount = decimal.Zero
+			} else {
+				tx = template.NewTransaction(date, amount)
+			}
+
+			err := s.accountingEngine.PostRecurringOccurrence(template, occurrence, tx)
+			if errors.Is(err, ErrOccurrenceAlreadyPosted) {
+				// A concurrent run or an edit moved the template on: continue from its stored state
+				report.AlreadyPosted++
+				current, err := s.recurringRepo.FindByID(template.ID)
+				if err != nil || current.NextOccurrence <= occurrence.OccurrenceIndex {
+					break
+				}
+				template = current
+				continue
+			}
+			if err != nil {
+				report.Failures = append(report.Failures, dtos.RecurringRunFailure{
+					RecurringTransactionID: template.ID,
+					UserID:                 template.UserID,
+					OccurrenceDate:         date,
+					Error:                  err.Error(),
+				})
+				break
+			}
+
+			if skip {
+				report.Skipped++
+			} else {
+				report.Posted++
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// findOwned loads a recurring transaction and checks it belongs to the user
+func (s *recurringTransactionService) findOwned(userID, id uint) (*models.RecurringTransaction, error) {
+	template, err := s.recurringRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if template.UserID != userID {
+		return nil, errors.New("recurring transaction not found")
+	}
+
+	return template, nil
+}
+
+// validateTemplate checks the schedule, the shape of the transactions it will post
+// and that the accounts and category belong to the user
+func (s *recurringTransactionService) validateTemplate(template *models.RecurringTransaction) error {
+	if err := template.Validate(); err != nil {
+		return err
+	}
+
+	if err := template.NewTransaction(template.StartDate, template.Amount).Validate(); err != nil {
+		return err
+	}
+
+	accountIDs := []uint{template.AccountFromID}
+	if template.AccountToID != nil {
+		accountIDs = append(accountIDs, *template.AccountToID)
+	}
+	for _, accountID := range accountIDs {
+		account, err := s.accountRepo.FindByID(accountID)
+		if err != nil {
+			return err
+		}
+		if account.UserID != template.UserID || account.IsSystemManaged() {
+			return fmt.Errorf("account %d not found", accountID)
+		}
+	}
+
+	if template.CategoryID != nil {
+		category, err := s.categoryRepo.FindByID(*template.CategoryID)
+		if err != nil {
+			return err
+		}
+		if category.UserID != template.UserID {
+			return errors.New("category not found")
+		}
+	}
+
+	return nil
+}
+
+// occurrenceAmount returns the amount of the occurrence on date after overrides, and whether it is skipped
+func occurrenceAmount(template *models.RecurringTransaction, date time.Time) (decimal.Decimal, bool) {
+	override := template.FindOverride(date)
+	if override == nil {
+		return template.Amount, false
+	}
+	if override.Skip {
+		return template.Amount, true
+	}
+	if override.Amount != nil {
+		return *override.Amount, false
+	}
+	return template.Amount, false
+}
+
+// hasUpcomingOccurrenceOn returns true if an occurrence not yet posted falls on the date's calendar day
+func hasUpcomingOccurrenceOn(template *models.RecurringTransaction, date time.Time) bool {
+	dayEnd := date.AddDate(0, 0, 1)
+	for n := template.NextOccurrence; template.HasOccurrence(n); n++ {
+		occurrence := template.OccurrenceDate(n).UTC()
+		if !occurrence.Before(dayEnd) {
+			return false
+		}
+		if !occurrence.Before(date) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseScheduleDate accepts RFC3339 or a plain date (midnight UTC)
+func parseScheduleDate(value string) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+
+	return time.Parse("2006-01-02", value)
+}
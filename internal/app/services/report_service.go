@@ -0,0 +1,518 @@
+package services
+
+import (
+	"arabella-api/internal/app/dtos"
+	"arabella-api/internal/app/models"
+	"arabella-api/internal/app/repositories"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ReportService builds financial statements from the journal (never from cached balances)
+type ReportService interface {
+	GetTrialBalance(userID uint, asOf time.Time) (*dtos.TrialBalanceReport, error)
+	GetIncomeStatement(userID uint, startDate, endDate time.Time, period string) (*dtos.IncomeStatementReport, error)
+	GetBalanceSheet(userID uint, dates []time.Time) (*dtos.BalanceSheetReport, error)
+	GetCashFlow(userID uint, startDate, endDate time.Time) (*dtos.CashFlowReport, error)
+}
+
+type reportService struct {
+	journalEntryRepo repositories.JournalEntryRepository
+	accountRepo      repositories.AccountRepository
+	categoryRepo     repositories.CategoryRepository
+	systemValueRepo  repositories.SystemValueRepository
+}
+
+// NewReportService creates a new report service
+func NewReportService(
+	journalEntryRepo repositories.JournalEntryRepository,
+	accountRepo repositories.AccountRepository,
+	categoryRepo repositories.CategoryRepository,
+	systemValueRepo repositories.SystemValueRepository,
+) ReportService {
+	return &reportService{
+		journalEntryRepo: journalEntryRepo,
+		accountRepo:      accountRepo,
+		categoryRepo:     categoryRepo,
+		systemValueRepo:  systemValueRepo,
+	}
+}
+
+// GetTrialBalance lists the debit/credit totals of every account posted to up to asOf (inclusive).
+// Groups follow the ACCOUNT_CLASSIFICATION catalog order; the report is balanced when total debits
+// equal total credits, which double-entry guarantees unless the journal is corrupted.
+func (s *reportService) GetTrialBalance(userID uint, asOf time.Time) (*dtos.TrialBalanceReport, error) {
+	totals, accounts, err := s.loadAccountTotals(userID, nil, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := s.classificationGroups()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dtos.TrialBalanceReport{AsOf: asOf}
+	for _, total := range totals {
+		account, ok := accounts[total.AccountID]
+		if !ok {
+			// Entry pointing at a missing account: keep it visible so the totals still add up
+			account = &models.Account{Name: "Unknown account"}
+			account.ID = total.AccountID
+		}
+
+		net := total.TotalDebits.Sub(total.TotalCredits)
+		line := dtos.TrialBalanceLine{
+			AccountID:     account.ID,
+			AccountName:   account.Name,
+			AccountType:   account.AccountType,
+			TotalDebits:   total.TotalDebits,
+			TotalCredits:  total.TotalCredits,
+			DebitBalance:  decimal.Max(net, decimal.Zero),
+			CreditBalance: decimal.Max(net.Neg(), decimal.Zero),
+			Balance:       account.BalanceDelta("DEBIT", net),
+		}
+
+		group := groups.get(account.GetClassification())
+		group.Accounts = append(group.Accounts, line)
+		group.TotalDebits = group.TotalDebits.Add(line.TotalDebits)
+		group.TotalCredits = group.TotalCredits.Add(line.TotalCredits)
+		group.Balance = group.Balance.Add(line.Balance)
+
+		report.TotalDebits = report.TotalDebits.Add(line.TotalDebits)
+		report.TotalCredits = report.TotalCredits.Add(line.TotalCredits)
+		report.TotalDebitBalances = report.TotalDebitBalances.Add(line.DebitBalance)
+		report.TotalCreditBalances = report.TotalCreditBalances.Add(line.CreditBalance)
+	}
+
+	report.Groups = groups.list()
+	report.IsBalanced = report.TotalDebits.Equal(report.TotalCredits) &&
+		report.TotalDebitBalances.Equal(report.TotalCreditBalances)
+
+	return report, nil
+}
+
+// GetIncomeStatement builds the income statement for entries dated in [startDate, endDate],
+// with one column per sub-period (month, quarter or year). Figures come from the nominal
+// INCOME/EXPENSE ledger accounts, so reversals and split legs are already netted in.
+func (s *reportService) GetIncomeStatement(userID uint, startDate, endDate time.Time, period string) (*dtos.IncomeStatementReport, error) {
+	if endDate.Before(startDate) {
+		return nil, errors.New("end date must not be before start date")
+	}
+
+	totals, err := s.journalEntryRepo.GetAccountPeriodTotals(userID, []string{"INCOME", "EXPENSE"}, startDate, endDate, period)
+	if err != nil {
+		return nil, err
+	}
+
+	// Columns: every sub-period touched by the range, even the ones without entries
+	report := &dtos.IncomeStatementReport{
+		StartDate: startDate,
+		EndDate:   endDate,
+		Period:    period,
+		Periods:   []dtos.IncomeStatementPeriod{},
+	}
+	columns := map[time.Time]int{}
+	for start := truncateToPeriod(startDate, period); !start.After(endDate); start = nextPeriod(start, period) {
+		columns[start] = len(report.Periods)
+		report.Periods = append(report.Periods, dtos.IncomeStatementPeriod{
+			Label:     periodLabel(start, period),
+			StartDate: start,
+			EndDate:   nextPeriod(start, period),
+		})
+	}
+	report.Income = newIncomeStatementSection(len(report.Periods))
+	report.Expenses = newIncomeStatementSection(len(report.Periods))
+
+	// Resolve accounts and the categories behind them
+	ids := []uint{}
+	seen := map[uint]bool{}
+	for _, total := range totals {
+		if !seen[total.AccountID] {
+			seen[total.AccountID] = true
+			ids = append(ids, total.AccountID)
+		}
+	}
+
+	accountList, err := s.accountRepo.FindByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	accounts := make(map[uint]*models.Account, len(accountList))
+	for _, account := range accountList {
+		accounts[account.ID] = account
+	}
+
+	categoryList, err := s.categoryRepo.FindByLedgerAccountIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	categoryByAccount := make(map[uint]uint, len(categoryList))
+	for _, category := range categoryList {
+		categoryByAccount[*category.LedgerAccountID] = category.ID
+	}
+
+	// Fill the lines, one per account, in the order the accounts first appear
+	lineIndex := map[uint]int{}
+	for _, total := range totals {
+		account, ok := accounts[total.AccountID]
+		if !ok {
+			continue
+		}
+
+		section := &report.Expenses
+		if account.GetClassification() == "INCOME" {
+			section = &report.Income
+		}
+
+		i, ok := lineIndex[account.ID]
+		if !ok {
+			line := dtos.IncomeStatementLine{
+				AccountID: account.ID,
+				Name:      account.Name,
+				Amounts:   make([]decimal.Decimal, len(report.Periods)),
+			}
+			if categoryID, found := categoryByAccount[account.ID]; found {
+				line.CategoryID = &categoryID
+			}
+			i = len(section.Lines)
+			lineIndex[account.ID] = i
+			section.Lines = append(section.Lines, line)
+		}
+
+		// Income grows with credits, expenses with debits (the account's normal side)
+		amount := account.BalanceDelta("DEBIT", total.TotalDebits.Sub(total.TotalCredits))
+		column := columns[total.PeriodStart.UTC()]
+
+		line := &section.Lines[i]
+		line.Amounts[column] = line.Amounts[column].Add(amount)
+		line.Total = line.Total.Add(amount)
+		section.Amounts[column] = section.Amounts[column].Add(amount)
+		section.Total = section.Total.Add(amount)
+	}
+
+	for i := range report.Periods {
+		report.Periods[i].TotalIncome = report.Income.Amounts[i]
+		report.Periods[i].TotalExpenses = report.Expenses.Amounts[i]
+		report.Periods[i].NetResult = report.Income.Amounts[i].Sub(report.Expenses.Amounts[i])
+	}
+	report.TotalIncome = report.Income.Total
+	report.TotalExpenses = report.Expenses.Total
+	report.NetResult = report.TotalIncome.Sub(report.TotalExpenses)
+
+	return report, nil
+}
+
+// GetBalanceSheet builds the balance sheet at each of the given dates (inclusive), computed from
+// journal entries rather than cached balances so any past date can be reported. The first date is
+// the main column, the others are comparative columns. Income and expense accounts are not listed:
+// their net amount up to each date is shown as accumulated earnings inside equity, which keeps
+// Assets = Liabilities + Equity.
+func (s *reportService) GetBalanceSheet(userID uint, dates []time.Time) (*dtos.BalanceSheetReport, error) {
+	if len(dates) == 0 {
+		return nil, errors.New("at least one date is required")
+	}
+
+	columns := len(dates)
+	report := &dtos.BalanceSheetReport{
+		Columns:                   make([]dtos.BalanceSheetColumn, columns),
+		Assets:                    newBalanceSheetSection(columns),
+		Liabilities:               newBalanceSheetSection(columns),
+		Equity:                    newBalanceSheetSection(columns),
+		NetWorth:                  make([]decimal.Decimal, columns),
+		TotalLiabilitiesAndEquity: make([]decimal.Decimal, columns),
+		IsBalanced:                make([]bool, columns),
+	}
+
+	earnings := dtos.BalanceSheetLine{Name: "Accumulated earnings", Amounts: make([]decimal.Decimal, columns)}
+	lineIndex := map[uint]int{}
+
+	for col, asOf := range dates {
+		report.Columns[col] = dtos.BalanceSheetColumn{Label: asOf.Format("2006-01-02"), AsOf: asOf}
+
+		totals, accounts, err := s.loadAccountTotals(userID, nil, asOf)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, total := range totals {
+			account, ok := accounts[total.AccountID]
+			if !ok {
+				continue
+			}
+
+			net := total.TotalDebits.Sub(total.TotalCredits)
+
+			var section *dtos.BalanceSheetSection
+			switch account.GetClassification() {
+			case "ASSET":
+				section = &report.Assets
+			case "LIABILITY":
+				section = &report.Liabilities
+			case "EQUITY":
+				section = &report.Equity
+			case "INCOME", "EXPENSE":
+				// Credit-normal view: income adds to equity, expenses reduce it
+				earnings.Amounts[col] = earnings.Amounts[col].Sub(net)
+				continue
+			default:
+				continue
+			}
+
+			i, ok := lineIndex[account.ID]
+			if !ok {
+				i = len(section.Lines)
+				lineIndex[account.ID] = i
+				section.Lines = append(section.Lines, dtos.BalanceSheetLine{
+					AccountID:   account.ID,
+					Name:        account.Name,
+					AccountType: account.AccountType,
+					Amounts:     make([]decimal.Decimal, columns),
+				})
+			}
+
+			amount := account.BalanceDelta("DEBIT", net)
+			section.Lines[i].Amounts[col] = amount
+			section.Totals[col] = section.Totals[col].Add(amount)
+		}
+	}
+
+	report.Equity.Lines = append(report.Equity.Lines, earnings)
+	for col := range dates {
+		report.Equity.Totals[col] = report.Equity.Totals[col].Add(earnings.Amounts[col])
+		report.NetWorth[col] = report.Assets.Totals[col].Sub(report.Liabilities.Totals[col])
+		report.TotalLiabilitiesAndEquity[col] = report.Liabilities.Totals[col].Add(report.Equity.Totals[col])
+		report.IsBalanced[col] = report.Assets.Totals[col].Equal(report.TotalLiabilitiesAndEquity[col])
+	}
+
+	return report, nil
+}
+
+// GetCashFlow classifies the journal movements of liquid accounts (Account.IsLiquidAsset) dated in
+// [startDate, endDate] by the type of the transaction that produced them. Opening cash is the
+// journal balance just before startDate; the closing cash it leads to is cross-checked against the
+// journal balance at endDate, i.e. the liquid accounts of the balance sheet on that date.
+func (s *reportService) GetCashFlow(userID uint, startDate, endDate time.Time) (*dtos.CashFlowReport, error) {
+	if endDate.Before(startDate) {
+		return nil, errors.New("end date must not be before start date")
+	}
+
+	closingTotals, accounts, err := s.loadAccountTotals(userID, nil, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	// Microsecond precision matches PostgreSQL timestamps
+	openingTotals, err := s.journalEntryRepo.GetAccountTotals(userID, nil, startDate.Add(-time.Microsecond))
+	if err != nil {
+		return nil, err
+	}
+
+	flows, err := s.journalEntryRepo.GetAccountFlowTotals(userID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dtos.CashFlowReport{
+		StartDate: startDate,
+		EndDate:   endDate,
+		Accounts:  []dtos.CashFlowAccountLine{},
+	}
+
+	// Every account with entries before startDate or inside the range also has entries up to endDate,
+	// so the closing totals define the set of lines
+	lineIndex := map[uint]int{}
+	for _, total := range closingTotals {
+		account, ok := accounts[total.AccountID]
+		if !ok || !account.IsLiquidAsset() {
+			continue
+		}
+
+		lineIndex[account.ID] = len(report.Accounts)
+		report.Accounts = append(report.Accounts, dtos.CashFlowAccountLine{
+			AccountID:   account.ID,
+			Name:        account.Name,
+			AccountType: account.AccountType,
+		})
+		report.BalanceSheetCash = report.BalanceSheetCash.Add(account.BalanceDelta("DEBIT", total.TotalDebits.Sub(total.TotalCredits)))
+	}
+
+	for _, total := range openingTotals {
+		i, ok := lineIndex[total.AccountID]
+		if !ok {
+			continue
+		}
+		line := &report.Accounts[i]
+		line.OpeningCash = accounts[total.AccountID].BalanceDelta("DEBIT", total.TotalDebits.Sub(total.TotalCredits))
+		report.OpeningCash = report.OpeningCash.Add(line.OpeningCash)
+	}
+
+	for _, flow := range flows {
+		i, ok := lineIndex[flow.AccountID]
+		if !ok {
+			continue
+		}
+		line := &report.Accounts[i]
+
+		var section *dtos.CashFlowSection
+		var lineNet *decimal.Decimal
+		switch flow.TransactionType {
+		case "INCOME", "EXPENSE":
+			section, lineNet = &report.Operating, &line.Operating
+		case "TRANSFER":
+			section, lineNet = &report.Transfers, &line.Transfers
+		case "DEBT_PAYMENT":
+			section, lineNet = &report.DebtPayments, &line.DebtPayments
+		default:
+			section, lineNet = &report.Adjustments, &line.Adjustments
+		}
+
+		// Liquid accounts are assets: debits bring cash in, credits take it out
+		net := flow.TotalDebits.Sub(flow.TotalCredits)
+		section.Inflows = section.Inflows.Add(flow.TotalDebits)
+		section.Outflows = section.Outflows.Add(flow.TotalCredits)
+		section.Net = section.Net.Add(net)
+		*lineNet = lineNet.Add(net)
+	}
+
+	for i := range report.Accounts {
+		line := &report.Accounts[i]
+		line.ClosingCash = line.OpeningCash.Add(line.Operating).Add(line.Transfers).Add(line.DebtPayments).Add(line.Adjustments)
+	}
+
+	report.NetChange = report.Operating.Net.Add(report.Transfers.Net).Add(report.DebtPayments.Net).Add(report.Adjustments.Net)
+	report.ClosingCash = report.OpeningCash.Add(report.NetChange)
+	report.IsReconciled = report.ClosingCash.Equal(report.BalanceSheetCash)
+
+	return report, nil
+}
+
+// newBalanceSheetSection creates an empty section with one total per column
+func newBalanceSheetSection(columns int) dtos.BalanceSheetSection {
+	return dtos.BalanceSheetSection{
+		Lines:  []dtos.BalanceSheetLine{},
+		Totals: make([]decimal.Decimal, columns),
+	}
+}
+
+// newIncomeStatementSection creates an empty section with one subtotal per period
+func newIncomeStatementSection(periods int) dtos.IncomeStatementSection {
+	return dtos.IncomeStatementSection{
+		Lines:   []dtos.IncomeStatementLine{},
+		Amounts: make([]decimal.Decimal, periods),
+	}
+}
+
+// truncateToPeriod returns the start (UTC) of the month, quarter or year containing t
+func truncateToPeriod(t time.Time, period string) time.Time {
+	t = t.UTC()
+	switch period {
+	case "year":
+		return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	case "quarter":
+		month := time.Month((int(t.Month())-1)/3*3 + 1)
+		return time.Date(t.Year(), month, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// nextPeriod returns the start of the period following the one that starts at start
+func nextPeriod(start time.Time, period string) time.Time {
+	switch period {
+	case "year":
+		return start.AddDate(1, 0, 0)
+	case "quarter":
+		return start.AddDate(0, 3, 0)
+	default:
+		return start.AddDate(0, 1, 0)
+	}
+}
+
+// periodLabel formats a period start as 2025-01, 2025-Q1 or 2025
+func periodLabel(start time.Time, period string) string {
+	switch period {
+	case "year":
+		return fmt.Sprintf("%d", start.Year())
+	case "quarter":
+		return fmt.Sprintf("%d-Q%d", start.Year(), (int(start.Month())-1)/3+1)
+	default:
+		return start.Format("2006-01")
+	}
+}
+
+// loadAccountTotals returns the journal totals of the user's accounts for entries dated in [from, to],
+// together with the accounts they belong to (keyed by ID)
+func (s *reportService) loadAccountTotals(userID uint, from *time.Time, to time.Time) ([]repositories.AccountEntryTotals, map[uint]*models.Account, error) {
+	totals, err := s.journalEntryRepo.GetAccountTotals(userID, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ids := make([]uint, len(totals))
+	for i, total := range totals {
+		ids[i] = total.AccountID
+	}
+
+	list, err := s.accountRepo.FindByIDs(ids)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	accounts := make(map[uint]*models.Account, len(list))
+	for _, account := range list {
+		accounts[account.ID] = account
+	}
+
+	return totals, accounts, nil
+}
+
+// classificationGroups prepares one empty trial balance group per ACCOUNT_CLASSIFICATION in catalog order
+func (s *reportService) classificationGroups() (*trialBalanceGroups, error) {
+	values, err := s.systemValueRepo.FindByCatalogType("ACCOUNT_CLASSIFICATION")
+	if err != nil {
+		return nil, err
+	}
+
+	groups := &trialBalanceGroups{index: map[string]int{}}
+	for _, value := range values {
+		groups.add(value.Value, value.Label)
+	}
+	return groups, nil
+}
+
+// trialBalanceGroups keeps the groups in catalog order while allowing lookup by classification
+type trialBalanceGroups struct {
+	groups []*dtos.TrialBalanceGroup
+	index  map[string]int
+}
+
+func (g *trialBalanceGroups) add(classification, label string) *dtos.TrialBalanceGroup {
+	group := &dtos.TrialBalanceGroup{
+		Classification: classification,
+		Label:          label,
+		Accounts:       []dtos.TrialBalanceLine{},
+	}
+	g.index[classification] = len(g.groups)
+	g.groups = append(g.groups, group)
+	return group
+}
+
+// get returns the group of a classification, appending it if the catalog does not know it
+func (g *trialBalanceGroups) get(classification string) *dtos.TrialBalanceGroup {
+	if i, ok := g.index[classification]; ok {
+		return g.groups[i]
+	}
+	return g.add(classification, classification)
+}
+
+func (g *trialBalanceGroups) list() []dtos.TrialBalanceGroup {
+	result := make([]dtos.TrialBalanceGroup, len(g.groups))
+	for i, group := range g.groups {
+		result[i] = *group
+	}
+	return result
+}
@@ -0,0 +1,523 @@
+package services
+
+import (
+	"arabella-api/internal/app/dtos"
+	"arabella-api/internal/app/models"
+	"arabella-api/internal/app/repositories"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrSavingsGoalAccountLinked is returned when linking an account that already counts towards a goal
+	ErrSavingsGoalAccountLinked = errors.New("the account is already linked to a savings goal")
+	// ErrEarmarkExceedsBalance is returned when the earmarks on an account would exceed its balance
+	ErrEarmarkExceedsBalance = errors.New("the earmarked amount exceeds the account balance")
+)
+
+// savingsPaceMonths is the number of past months used to measure the pace of a goal
+const savingsPaceMonths = 6
+
+// SavingsGoalService manages savings goals and reports their progress.
+// Progress is computed on the fly from the balances of the linked accounts and the earmarks;
+// the pace (average monthly contribution) comes from how the saved amount grew over the last months.
+type SavingsGoalService interface {
+	Create(userID uint, req *dtos.CreateSavingsGoalRequest) (*dtos.SavingsGoalResponse, error)
+	GetByUser(userID uint) ([]dtos.SavingsGoalResponse, error)
+	GetByID(userID, id uint) (*dtos.SavingsGoalResponse, error)
+	Update(userID, id uint, req *dtos.UpdateSavingsGoalRequest) (*dtos.SavingsGoalResponse, error)
+	Delete(userID, id uint) error
+	LinkAccount(userID, id, accountID uint) (*dtos.SavingsGoalResponse, error)
+	UnlinkAccount(userID, id, accountID uint) (*dtos.SavingsGoalResponse, error)
+	AddContribution(userID, id uint, req *dtos.CreateSavingsGoalContributionRequest) (*dtos.SavingsGoalResponse, error)
+	GetSummary(userID uint) (*dtos.SavingsGoalSummary, error)
+}
+
+type savingsGoalService struct {
+	savingsGoalRepo  repositories.SavingsGoalRepository
+	accountRepo      repositories.AccountRepository
+	journalEntryRepo repositories.JournalEntryRepository
+}
+
+// NewSavingsGoalService creates a new savings goal service
+func NewSavingsGoalService(
+	savingsGoalRepo repositories.SavingsGoalRepository,
+	accountRepo repositories.AccountRepository,
+	journalEntryRepo repositories.JournalEntryRepository,
+) SavingsGoalService {
+	return &savingsGoalService{
+		savingsGoalRepo:  savingsGoalRepo,
+		accountRepo:      accountRepo,
+		journalEntryRepo: journalEntryRepo,
+	}
+}
+
+// Create creates a savings goal, linking the given SAVINGS accounts in full
+func (s *savingsGoalService) Create(userID uint, req *dtos.CreateSavingsGoalRequest) (*dtos.SavingsGoalResponse, error) {
+	goal := &models.SavingsGoal{
+		UserID:       userID,
+		Name:         req.Name,
+		TargetAmount: req.TargetAmount,
+		Notes:        req.Notes,
+	}
+
+	if req.TargetDate != nil && *req.TargetDate != "" {
+		targetDate, err := parseScheduleDate(*req.TargetDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid target_date: %w", err)
+		}
+		goal.TargetDate = &targetDate
+	}
+
+	if err := goal.Validate(); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[uint]bool, len(req.AccountIDs))
+	accounts := make([]*models.Account, 0, len(req.AccountIDs))
+	for _, accountID := range req.AccountIDs {
+		if seen[accountID] {
+			return nil, fmt.Errorf("account %d is listed more than once", accountID)
+		}
+		seen[accountID] = true
+
+		account, err := s.findLinkableAccount(userID, accountID)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+
+	// Linking is checked against the earmarks under the user's lock, as contributions are
+	err := s.savingsGoalRepo.WithUserLock(userID, func(repo repositories.SavingsGoalRepository) error {
+		for _, account := range accounts {
+			if err := validateLink(repo, account); err != nil {
+				return err
+			}
+		}
+
+		return repo.Create(goal, req.AccountIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(userID, goal.ID)
+}
+
+// GetByUser returns every savings goal of the user with its progress
+func (s *savingsGoalService) GetByUser(userID uint) ([]dtos.SavingsGoalResponse, error) {
+	goals, err := s.savingsGoalRepo.FindByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	responses := make([]dtos.SavingsGoalResponse, len(goals))
+	for i, goal := range goals {
+		response, err := s.evaluate(goal, now)
+		if err != nil {
+			return nil, err
+		}
+		responses[i] = *response
+	}
+
+	return responses, nil
+}
+
+// GetByID returns a savings goal with its progress and contributions
+func (s *savingsGoalService) GetByID(userID, id uint) (*dtos.SavingsGoalResponse, error) {
+	goal, err := s.findOwned(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.evaluate(goal, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	response.Contributions = make([]dtos.SavingsGoalContributionResponse, len(goal.Contributions))
+	for i := range goal.Contributions {
+		response.Contributions[i] = dtos.FromModelToSavingsGoalContributionResponse(&goal.Contributions[i])
+	}
+
+	return response, nil
+}
+
+// Update updates the name, target or notes of a savings goal
+func (s *savingsGoalService) Update(userID, id uint, req *dtos.UpdateSavingsGoalRequest) (*dtos.SavingsGoalResponse, error) {
+	goal, err := s.findOwned(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		goal.Name = *req.Name
+	}
+	if req.TargetAmount != nil {
+		goal.TargetAmount = *req.TargetAmount
+	}
+	if req.TargetDate != nil {
+		goal.TargetDate = nil
+		if *req.TargetDate != "" {
+			targetDate, err := parseScheduleDate(*req.TargetDate)
+			if err != nil {
+				return nil, fmt.Errorf("invalid target_date: %w", err)
+			}
+			goal.TargetDate = &targetDate
+		}
+	}
+	if req.Notes != nil {
+		goal.Notes = *req.Notes
+	}
+
+	if err := s.savingsGoalRepo.Update(goal); err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(userID, goal.ID)
+}
+
+// Delete soft deletes a savings goal, unlinking its accounts and releasing its earmarks.
+// Account balances are not affected.
+func (s *savingsGoalService) Delete(userID, id uint) error {
+	goal, err := s.findOwned(userID, id)
+	if err != nil {
+		return err
+	}
+
+	return s.savingsGoalRepo.Delete(goal.ID)
+}
+
+// LinkAccount links a SAVINGS account in full to a savings goal
+func (s *savingsGoalService) LinkAccount(userID, id, accountID uint) (*dtos.SavingsGoalResponse, error) {
+	goal, err := s.findOwned(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.findLinkableAccount(userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Linking is checked against the earmarks under the user's lock, as contributions are
+	err = s.savingsGoalRepo.WithUserLock(userID, func(repo repositories.SavingsGoalRepository) error {
+		if err := validateLink(repo, account); err != nil {
+			return err
+		}
+
+		return repo.LinkAccount(goal.ID, account.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(userID, goal.ID)
+}
+
+// UnlinkAccount removes a linked account from a savings goal
+func (s *savingsGoalService) UnlinkAccount(userID, id, accountID uint) (*dtos.SavingsGoalResponse, error) {
+	goal, err := s.findOwned(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.savingsGoalRepo.UnlinkAccount(goal.ID, accountID); err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(userID, goal.ID)
+}
+
+// AddContribution earmarks part of an asset account's balance for a goal, or releases it when the
+// amount is negative. The earmarks of all goals on an account cannot exceed its balance, and a goal
+// cannot release more than it earmarked on the account.
+func (s *savingsGoalService) AddContribution(userID, id uint, req *dtos.CreateSavingsGoalContributionRequest) (*dtos.SavingsGoalResponse, error) {
+	goal, err := s.findOwned(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.accountRepo.FindByID(req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	if account.UserID != userID {
+		return nil, errors.New("account not found")
+	}
+	if account.IsSystemManaged() || account.GetClassification() != "ASSET" {
+		return nil, fmt.Errorf("only asset accounts can be earmarked, %q is %s", account.Name, account.AccountType)
+	}
+
+	contributionDate := time.Now()
+	if req.ContributionDate != "" {
+		parsed, err := parseScheduleDate(req.ContributionDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid contribution_date: %w", err)
+		}
+		if parsed.After(contributionDate) {
+			return nil, errors.New("contribution_date cannot be in the future")
+		}
+		contributionDate = parsed
+	}
+
+	// The earmark checks and the insert run under the user's lock, so two concurrent
+	// contributions cannot both fit in the same available balance
+	err = s.savingsGoalRepo.WithUserLock(userID, func(repo repositories.SavingsGoalRepository) error {
+		if _, err := repo.FindAccountLink(account.ID); err == nil {
+			return fmt.Errorf("%w: its whole balance already counts towards a goal", ErrSavingsGoalAccountLinked)
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if req.Amount.IsPositive() {
+			earmarked, err := repo.GetEarmarked(account.ID, nil)
+			if err != nil {
+				return err
+			}
+			if earmarked.Add(req.Amount).GreaterThan(account.Balance) {
+				return fmt.Errorf("%w: %s available on %q", ErrEarmarkExceedsBalance,
+					decimal.Max(account.Balance.Sub(earmarked), decimal.Zero).String(), account.Name)
+			}
+		} else {
+			earmarked, err := repo.GetEarmarked(account.ID, &goal.ID)
+			if err != nil {
+				return err
+			}
+			if earmarked.Add(req.Amount).IsNegative() {
+				return fmt.Errorf("cannot release more than the %s earmarked on %q", earmarked.String(), account.Name)
+			}
+		}
+
+		return repo.AddContribution(&models.SavingsGoalContribution{
+			SavingsGoalID:    goal.ID,
+			AccountID:        account.ID,
+			Amount:           req.Amount,
+			ContributionDate: contributionDate,
+			Notes:            req.Notes,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(userID, goal.ID)
+}
+
+// GetSummary aggregates the progress of every savings goal of the user
+func (s *savingsGoalService) GetSummary(userID uint) (*dtos.SavingsGoalSummary, error) {
+	goals, err := s.GetByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &dtos.SavingsGoalSummary{Goals: goals}
+	for _, goal := range goals {
+		summary.TotalTarget = summary.TotalTarget.Add(goal.TargetAmount)
+		summary.TotalSaved = summary.TotalSaved.Add(decimal.Min(goal.Saved, goal.TargetAmount))
+
+		switch goal.Status {
+		case "ACHIEVED":
+			summary.Achieved++
+		case "AT_RISK":
+			summary.AtRisk++
+		case "NO_PROGRESS":
+			summary.NoProgress++
+		default:
+			summary.OnTrack++
+		}
+	}
+
+	summary.PercentComplete = percentUsed(summary.TotalSaved, summary.TotalTarget)
+
+	return summary, nil
+}
+
+// evaluate computes the progress of a goal at asOf.
+// Saved counts the current balance of every linked account plus the earmarks (capped by the balance
+// of their account). The pace is the growth of the saved amount over the last savingsPaceMonths months,
+// measured on the ledger balances of the linked accounts and the dated earmarks.
+func (s *savingsGoalService) evaluate(goal *models.SavingsGoal, asOf time.Time) (*dtos.SavingsGoalResponse, error) {
+	response := &dtos.SavingsGoalResponse{
+		ID:           goal.ID,
+		Name:         goal.Name,
+		TargetAmount: goal.TargetAmount,
+		TargetDate:   goal.TargetDate,
+		Notes:        goal.Notes,
+		CreatedAt:    goal.CreatedAt,
+		Accounts:     []dtos.SavingsGoalAccountResponse{},
+	}
+
+	paceStart := asOf.AddDate(0, -savingsPaceMonths, 0)
+	savedNow, savedBefore := decimal.Zero, decimal.Zero
+
+	for _, link := range goal.Accounts {
+		if link.Account == nil {
+			continue
+		}
+
+		response.Saved = response.Saved.Add(link.Account.Balance)
+		response.Accounts = append(response.Accounts, dtos.SavingsGoalAccountResponse{
+			AccountID:   link.AccountID,
+			AccountName: link.Account.Name,
+			AccountType: link.Account.AccountType,
+			Balance:     link.Account.Balance,
+			Link:        "FULL",
+			Amount:      link.Account.Balance,
+		})
+
+		// Linked accounts are asset accounts: Debits - Credits is their balance
+		current, err := s.journalEntryRepo.GetAccountBalance(link.AccountID, &asOf)
+		if err != nil {
+			return nil, err
+		}
+		before, err := s.journalEntryRepo.GetAccountBalance(link.AccountID, &paceStart)
+		if err != nil {
+			return nil, err
+		}
+		savedNow = savedNow.Add(current)
+		savedBefore = savedBefore.Add(before)
+	}
+
+	earmarks := make(map[uint]*dtos.SavingsGoalAccountResponse)
+	order := []uint{}
+	for _, contribution := range goal.Contributions {
+		if !contribution.ContributionDate.After(asOf) {
+			savedNow = savedNow.Add(contribution.Amount)
+		}
+		if !contribution.ContributionDate.After(paceStart) {
+			savedBefore = savedBefore.Add(contribution.Amount)
+		}
+
+		earmark, ok := earmarks[contribution.AccountID]
+		if !ok {
+			earmark = &dtos.SavingsGoalAccountResponse{AccountID: contribution.AccountID, Link: "EARMARK"}
+			if contribution.Account != nil {
+				earmark.AccountName = contribution.Account.Name
+				earmark.AccountType = contribution.Account.AccountType
+				earmark.Balance = contribution.Account.Balance
+			}
+			earmarks[contribution.AccountID] = earmark
+			order = append(order, contribution.AccountID)
+		}
+		earmark.Amount = earmark.Amount.Add(contribution.Amount)
+	}
+
+	for _, accountID := range order {
+		earmark := earmarks[accountID]
+		if earmark.Amount.IsZero() {
+			continue
+		}
+
+		// The account may have been spent below what was earmarked
+		earmark.Amount = decimal.Min(earmark.Amount, decimal.Max(earmark.Balance, decimal.Zero))
+		response.Saved = response.Saved.Add(earmark.Amount)
+		response.Accounts = append(response.Accounts, *earmark)
+	}
+
+	response.Remaining = decimal.Max(goal.TargetAmount.Sub(response.Saved), decimal.Zero)
+	response.PercentComplete = percentUsed(response.Saved, goal.TargetAmount)
+	response.AverageMonthlyContribution = savedNow.Sub(savedBefore).
+		Div(decimal.NewFromInt(savingsPaceMonths)).Round(2)
+
+	if !response.Remaining.IsPositive() {
+		response.Status = "ACHIEVED"
+		return response, nil
+	}
+
+	if goal.TargetDate != nil {
+		needed := response.Remaining
+		if months := monthsUntil(asOf, *goal.TargetDate); months > 1 {
+			needed = needed.Div(decimal.NewFromInt(int64(months))).Round(2)
+		}
+		response.MonthlyContributionNeeded = &needed
+	}
+
+	if !response.AverageMonthlyContribution.IsPositive() {
+		response.Status = "NO_PROGRESS"
+		return response, nil
+	}
+
+	months := response.Remaining.Div(response.AverageMonthlyContribution).Ceil().IntPart()
+	projected := asOf.AddDate(0, int(months), 0)
+	response.ProjectedCompletionDate = &projected
+
+	response.Status = "ON_TRACK"
+	if goal.TargetDate != nil && projected.After(*goal.TargetDate) {
+		response.Status = "AT_RISK"
+	}
+
+	return response, nil
+}
+
+// monthsUntil returns the number of whole months from from to to (0 when to is in less than a month or past)
+func monthsUntil(from, to time.Time) int {
+	months := (to.Year()-from.Year())*12 + int(to.Month()-from.Month())
+	if to.Day() < from.Day() {
+		months--
+	}
+	if months < 0 {
+		return 0
+	}
+	return months
+}
+
+// findLinkableAccount loads an account that may be linked in full to a goal: one of the user's
+// active SAVINGS accounts
+func (s *savingsGoalService) findLinkableAccount(userID, accountID uint) (*models.Account, error) {
+	account, err := s.accountRepo.FindByID(accountID)
+	if err != nil {
+		return nil, err
+	}
+	if account.UserID != userID {
+		return nil, errors.New("account not found")
+	}
+	if account.AccountType != "SAVINGS" {
+		return nil, fmt.Errorf("only SAVINGS accounts can be linked to a goal, %q is %s", account.Name, account.AccountType)
+	}
+	if !account.IsActive {
+		return nil, fmt.Errorf("account %q is inactive", account.Name)
+	}
+
+	return account, nil
+}
+
+// validateLink checks that an account is not linked to another goal and has no earmarks.
+// It reads through repo, so callers holding the user's lock see the state they are about to write on.
+func validateLink(repo repositories.SavingsGoalRepository, account *models.Account) error {
+	if _, err := repo.FindAccountLink(account.ID); err == nil {
+		return ErrSavingsGoalAccountLinked
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	earmarked, err := repo.GetEarmarked(account.ID, nil)
+	if err != nil {
+		return err
+	}
+	if !earmarked.IsZero() {
+		return fmt.Errorf("account %q has %s earmarked for goals; release it before linking the account", account.Name, earmarked.String())
+	}
+
+	return nil
+}
+
+// findOwned loads a savings goal and checks it belongs to the user
+func (s *savingsGoalService) findOwned(userID, id uint) (*models.SavingsGoal, error) {
+	goal, err := s.savingsGoalRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if goal.UserID != userID {
+		return nil, errors.New("savings goal not found")
+	}
+
+	return goal, nil
+}
@@ -4,17 +4,32 @@ import (
 	"arabella-api/internal/app/dtos"
 	"arabella-api/internal/app/models"
 	"arabella-api/internal/app/repositories"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
 )
 
+// ErrManualReconciliation is returned when a client tries to flip is_reconciled by hand
+var ErrManualReconciliation = errors.New("is_reconciled cannot be set directly; clear the transaction in a reconciliation instead")
+
+// ErrTransactionNotEditable is returned when a transaction was reversed, replaced or reconciled while being edited
+var ErrTransactionNotEditable = repositories.ErrTransactionNotEditable
+
 // TransactionService handles transaction-related business logic
 // It coordinates with the AccountingEngine to ensure proper double-entry bookkeeping
 type TransactionService interface {
 	Create(req *dtos.CreateTransactionRequest, userID uint) (*models.Transaction, error)
+	CreateBatch(req *dtos.BatchCreateTransactionRequest, userID uint) (*dtos.BatchCreateTransactionResponse, error)
 	GetByID(id uint) (*dtos.TransactionResponse, error)
 	GetByUser(userID uint, filters dtos.TransactionFilters) (*dtos.TransactionListResponse, error)
-	Update(id uint, req *dtos.UpdateTransactionRequest) error
+	Update(userID, id uint, req *dtos.UpdateTransactionRequest) (*models.Transaction, error)
+	GetHistory(userID, id uint) ([]dtos.TransactionResponse, error)
 	Delete(id uint) error
+	Confirm(userID, id uint, req *dtos.ConfirmTransactionRequest) (*models.Transaction, error)
+	PostDuePending(asOf time.Time, userID *uint) (*dtos.PendingRunReport, error)
 }
 
 type transactionService struct {
@@ -35,10 +50,9 @@ func NewTransactionService(
 
 // Create creates a new transaction and processes it through the accounting engine
 func (s *transactionService) Create(req *dtos.CreateTransactionRequest, userID uint) (*models.Transaction, error) {
-	// Convert DTO to model
-	transaction, err := req.ToModel(userID)
+	transaction, err := newTransactionFromRequest(req, userID)
 	if err != nil {
-		return nil, fmt.Errorf("invalid transaction data: %w", err)
+		return nil, err
 	}
 
 	// Process through accounting engine (creates journal entries, updates balances)
@@ -50,6 +64,131 @@ func (s *transactionService) Create(req *dtos.CreateTransactionRequest, userID u
 	return s.transactionRepo.FindByID(transaction.ID)
 }
 
+// CreateBatch posts many transactions through the accounting engine in a single DB transaction and
+// reports the outcome of every item. In all-or-nothing mode a single invalid item rejects the batch
+// before anything is posted, and a posting failure rolls back the items already posted.
+func (s *transactionService) CreateBatch(req *dtos.BatchCreateTransactionRequest, userID uint) (*dtos.BatchCreateTransactionResponse, error) {
+	response := &dtos.BatchCreateTransactionResponse{
+		Mode:    "best_effort",
+		Total:   len(req.Transactions),
+		Results: make([]dtos.BatchTransactionResult, len(req.Transactions)),
+	}
+	if req.IsAllOrNothing() {
+		response.Mode = "all_or_nothing"
+	}
+
+	// Step 1: Convert and validate every item; only valid ones reach the engine
+	var transactions []*models.Transaction
+	var indexes []int
+	for i := range req.Transactions {
+		response.Results[i].Index = i
+
+		transaction, err := newTransactionFromRequest(&req.Transactions[i], userID)
+		if err == nil {
+			err = transaction.Validate()
+		}
+		if err != nil {
+			response.Results[i].Status = "FAILED"
+			response.Results[i].Error = err.Error()
+			response.Failed++
+			continue
+		}
+
+		transactions = append(transactions, transaction)
+		indexes = append(indexes, i)
+	}
+
+	if response.Failed > 0 && req.IsAllOrNothing() {
+		for _, i := range indexes {
+			response.Results[i].Status = "ROLLED_BACK"
+		}
+		return response, nil
+	}
+
+	// Step 2: Post them all in one DB transaction
+	errs, err := s.accountingEngine.ProcessTransactions(transactions, req.IsAllOrNothing())
+	if err != nil {
+		return nil, fmt.Errorf("failed to process batch: %w", err)
+	}
+
+	postingFailed := false
+	for j, i := range indexes {
+		if errs[j] != nil {
+			response.Results[i].Status = "FAILED"
+			response.Results[i].Error = fmt.Sprintf("failed to process transaction: %s", errs[j].Error())
+			response.Failed++
+			postingFailed = true
+		}
+	}
+
+	if postingFailed && req.IsAllOrNothing() {
+		for j, i := range indexes {
+			if errs[j] == nil {
+				response.Results[i].Status = "ROLLED_BACK"
+			}
+		}
+		return response, nil
+	}
+
+	response.Committed = true
+
+	// Step 3: Reload the posted transactions with their relationships
+	ids := make([]uint, 0, len(transactions))
+	for j, transaction := range transactions {
+		if errs[j] == nil {
+			ids = append(ids, transaction.ID)
+		}
+	}
+
+	posted, err := s.transactionRepo.FindByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]*models.Transaction, len(posted))
+	for _, transaction := range posted {
+		byID[transaction.ID] = transaction
+	}
+
+	for j, i := range indexes {
+		if errs[j] != nil {
+			continue
+		}
+
+		response.Results[i].Status = "CREATED"
+		response.Created++
+		if transaction, ok := byID[transactions[j].ID]; ok {
+			txResponse := dtos.FromModelToTransactionResponse(transaction)
+			response.Results[i].Transaction = &txResponse
+		}
+	}
+
+	return response, nil
+}
+
+// newTransactionFromRequest converts a create request to a model, rejecting the types
+// that are only posted by the engine itself
+func newTransactionFromRequest(req *dtos.CreateTransactionRequest, userID uint) (*models.Transaction, error) {
+	// Opening balances and adjustments are posted by the engine through the account endpoints
+	if req.Type == "OPENING_BALANCE" || req.Type == "ADJUSTMENT" {
+		return nil, fmt.Errorf("%s transactions are created through the account balance endpoints", req.Type)
+	}
+
+	// Convert DTO to model
+	transaction, err := req.ToModel(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transaction data: %w", err)
+	}
+
+	// Unconfirmed and future-dated transactions wait as PENDING (no balance change yet)
+	if req.Pending || transaction.TransactionDate.After(time.Now()) {
+		transaction.Status = "PENDING"
+		transaction.RequiresConfirmation = req.Pending
+	}
+
+	return transaction, nil
+}
+
 // GetByID retrieves a transaction by ID with all relationships loaded
 func (s *transactionService) GetByID(id uint) (*dtos.TransactionResponse, error) {
 	transaction, err := s.transactionRepo.FindByID(id)
@@ -94,13 +233,46 @@ func (s *transactionService) GetByUser(userID uint, filters dtos.TransactionFilt
 	}, nil
 }
 
-// Update updates a transaction
-// Note: In double-entry bookkeeping, we typically don't "update" transactions
-// Instead, we reverse the old one and create a new one
-func (s *transactionService) Update(id uint, req *dtos.UpdateTransactionRequest) error {
-	transaction, err := s.transactionRepo.FindByID(id)
+// Update updates a transaction and returns its current version.
+// Descriptive fields (description, notes) are updated in place.
+// Changes to amount, date, accounts or category follow double-entry practice:
+// the original is reversed and a corrected replacement is posted in the same DB transaction,
+// so the returned transaction is the new version (linked to the original).
+func (s *transactionService) Update(userID, id uint, req *dtos.UpdateTransactionRequest) (*models.Transaction, error) {
+	transaction, err := s.findOwned(userID, id)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if transaction.ReplacedByTransactionID != nil {
+		return nil, fmt.Errorf("transaction %d was replaced by transaction %d; edit the latest version instead", transaction.ID, *transaction.ReplacedByTransactionID)
+	}
+
+	if transaction.IsReversal() {
+		return nil, fmt.Errorf("transaction %d is the reversal of transaction %d and cannot be edited", transaction.ID, *transaction.ReversesTransactionID)
+	}
+
+	if transaction.IsPending() {
+		if req.IsReconciled != nil {
+			return nil, ErrManualReconciliation
+		}
+		return s.updatePending(transaction, req)
+	}
+
+	if !transaction.IsPosted() {
+		return nil, fmt.Errorf("transaction %d is %s and cannot be edited", transaction.ID, strings.ToLower(transaction.Status))
+	}
+
+	if transaction.IsLocked() {
+		return nil, fmt.Errorf("transaction %d is locked by reconciliation %d", transaction.ID, *transaction.ReconciliationID)
+	}
+
+	if req.IsReconciled != nil {
+		return nil, ErrManualReconciliation
+	}
+
+	if req.RequiresRepost() {
+		return s.repost(transaction, req)
 	}
 
 	// Apply updates
@@ -110,18 +282,230 @@ func (s *transactionService) Update(id uint, req *dtos.UpdateTransactionRequest)
 	if req.Notes != nil {
 		transaction.Notes = *req.Notes
 	}
-	if req.IsReconciled != nil {
-		transaction.IsReconciled = *req.IsReconciled
+	if err := s.transactionRepo.UpdateDetails(transaction.ID, transaction.Description, transaction.Notes); err != nil {
+		return nil, err
 	}
 
-	// Note: We don't allow updating amount or accounts
-	// If those need to change, user should delete and create a new transaction
+	return s.transactionRepo.FindByID(transaction.ID)
+}
+
+// repost builds the corrected replacement of a transaction and posts it through the accounting engine
+func (s *transactionService) repost(original *models.Transaction, req *dtos.UpdateTransactionRequest) (*models.Transaction, error) {
+	replacement := &models.Transaction{
+		UserID:          original.UserID,
+		Type:            original.Type,
+		Description:     original.Description,
+		Amount:          original.Amount,
+		ExchangeRate:    original.ExchangeRate,
+		AccountFromID:   original.AccountFromID,
+		AccountToID:     original.AccountToID,
+		CategoryID:      original.CategoryID,
+		TransactionDate: original.TransactionDate,
+		Notes:           original.Notes,
+		IsReconciled:    original.IsReconciled,
+	}
 
-	return s.transactionRepo.Update(transaction)
+	// Split legs are copied as new rows for the replacement
+	for _, leg := range original.Splits {
+		replacement.Splits = append(replacement.Splits, models.TransactionSplit{
+			CategoryID:  leg.CategoryID,
+			AccountID:   leg.AccountID,
+			Amount:      leg.Amount,
+			Description: leg.Description,
+		})
+	}
+
+	// Apply updates
+	if req.Description != nil {
+		replacement.Description = *req.Description
+	}
+	if req.Amount != nil {
+		replacement.Amount = *req.Amount
+	}
+	if req.TransactionDate != nil {
+		transactionDate, err := time.Parse(time.RFC3339, *req.TransactionDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid transaction_date: %w", err)
+		}
+		replacement.TransactionDate = transactionDate
+	}
+	if req.AccountFromID != nil {
+		replacement.AccountFromID = *req.AccountFromID
+	}
+	if req.AccountToID != nil {
+		replacement.AccountToID = req.AccountToID
+	}
+	if req.CategoryID != nil {
+		// A single category replaces the legs of a split transaction
+		replacement.CategoryID = req.CategoryID
+		replacement.Splits = nil
+	}
+	if req.Notes != nil {
+		replacement.Notes = *req.Notes
+	}
+
+	if replacement.IsSplit() && req.Amount != nil {
+		return nil, errors.New("the amount of a split transaction cannot be changed without its legs; delete it and create a new one")
+	}
+
+	if replacement.ExchangeRate.IsZero() {
+		replacement.ExchangeRate = decimal.NewFromInt(1)
+	}
+	replacement.AmountInUSD = replacement.CalculateAmountInUSD()
+
+	// Reverse the original and post the replacement atomically
+	if err := s.accountingEngine.ReplaceTransaction(original.ID, replacement); err != nil {
+		return nil, fmt.Errorf("failed to repost transaction: %w", err)
+	}
+
+	return s.transactionRepo.FindByID(replacement.ID)
+}
+
+// updatePending edits a pending transaction in place: it has no journal entries yet,
+// so there is nothing to reverse
+func (s *transactionService) updatePending(transaction *models.Transaction, req *dtos.UpdateTransactionRequest) (*models.Transaction, error) {
+	if transaction.IsSplit() && req.Amount != nil && req.CategoryID == nil {
+		return nil, errors.New("the amount of a split transaction cannot be changed without its legs; delete it and create a new one")
+	}
+
+	if req.Description != nil {
+		transaction.Description = *req.Description
+	}
+	if req.Amount != nil {
+		transaction.Amount = *req.Amount
+		transaction.AmountInUSD = transaction.CalculateAmountInUSD()
+	}
+	if req.TransactionDate != nil {
+		transactionDate, err := time.Parse(time.RFC3339, *req.TransactionDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid transaction_date: %w", err)
+		}
+		transaction.TransactionDate = transactionDate
+	}
+	if req.AccountFromID != nil {
+		transaction.AccountFromID = *req.AccountFromID
+	}
+	if req.AccountToID != nil {
+		transaction.AccountToID = req.AccountToID
+	}
+	if req.CategoryID != nil {
+		// A single category replaces the legs of a split transaction
+		transaction.CategoryID = req.CategoryID
+		transaction.Splits = nil
+	}
+	if req.Notes != nil {
+		transaction.Notes = *req.Notes
+	}
+
+	if err := s.accountingEngine.UpdatePendingTransaction(transaction); err != nil {
+		return nil, fmt.Errorf("failed to update pending transaction: %w", err)
+	}
+
+	return s.transactionRepo.FindByID(transaction.ID)
 }
 
-// Delete deletes a transaction by reversing it (proper accounting practice)
+// GetHistory returns every version of an edited transaction owned by the user, oldest first
+func (s *transactionService) GetHistory(userID, id uint) ([]dtos.TransactionResponse, error) {
+	transaction, err := s.findOwned(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Walk back to the first version
+	for transaction.ReplacesTransactionID != nil {
+		transaction, err = s.transactionRepo.FindByID(*transaction.ReplacesTransactionID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Walk forward through every replacement
+	history := []dtos.TransactionResponse{dtos.FromModelToTransactionResponse(transaction)}
+	for transaction.ReplacedByTransactionID != nil {
+		transaction, err = s.transactionRepo.FindByID(*transaction.ReplacedByTransactionID)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, dtos.FromModelToTransactionResponse(transaction))
+	}
+
+	return history, nil
+}
+
+// Delete deletes a transaction by reversing it (proper accounting practice).
+// A pending transaction is simply voided: it never reached the ledger.
 func (s *transactionService) Delete(id uint) error {
 	// Use the accounting engine to reverse the transaction
 	return s.accountingEngine.ReverseTransaction(id)
 }
+
+// Confirm posts a pending transaction of the user through the accounting engine, optionally re-dating it first
+func (s *transactionService) Confirm(userID, id uint, req *dtos.ConfirmTransactionRequest) (*models.Transaction, error) {
+	if _, err := s.findOwned(userID, id); err != nil {
+		return nil, err
+	}
+
+	var transactionDate *time.Time
+	if req.TransactionDate != nil {
+		date, err := time.Parse(time.RFC3339, *req.TransactionDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid transaction_date: %w", err)
+		}
+		transactionDate = &date
+	}
+
+	transaction, err := s.accountingEngine.PostPendingTransaction(id, transactionDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.transactionRepo.FindByID(transaction.ID)
+}
+
+// PostDuePending posts every pending transaction dated up to asOf that does not wait for a confirmation,
+// optionally limited to one user. A transaction that fails is reported and retried on the next run;
+// one confirmed, voided or posted concurrently in the meantime is counted as already posted.
+func (s *transactionService) PostDuePending(asOf time.Time, userID *uint) (*dtos.PendingRunReport, error) {
+	due, err := s.transactionRepo.FindDuePending(asOf, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dtos.PendingRunReport{
+		AsOf:     asOf,
+		Due:      len(due),
+		Failures: []dtos.PendingRunFailure{},
+	}
+
+	for _, transaction := range due {
+		if _, err := s.accountingEngine.PostPendingTransaction(transaction.ID, nil); err != nil {
+			if errors.Is(err, ErrTransactionNotPending) {
+				report.AlreadyPosted++
+				continue
+			}
+			report.Failures = append(report.Failures, dtos.PendingRunFailure{
+				TransactionID: transaction.ID,
+				UserID:        transaction.UserID,
+				Error:         err.Error(),
+			})
+			continue
+		}
+		report.Posted++
+	}
+
+	return report, nil
+}
+
+// findOwned loads a transaction and checks it belongs to the user
+func (s *transactionService) findOwned(userID, id uint) (*models.Transaction, error) {
+	transaction, err := s.transactionRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction.UserID != userID {
+		return nil, errors.New("transaction not found")
+	}
+
+	return transaction, nil
+}
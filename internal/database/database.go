@@ -75,6 +75,28 @@ func AutoMigrate(db *gorm.DB) error {
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
 
+	if err := RefreshConstraints(db); err != nil {
+		return fmt.Errorf("failed to refresh constraints: %w", err)
+	}
+
 	log.Println("✅ Database migrations completed successfully")
 	return nil
-}
\ No newline at end of file
+}
+
+// RefreshConstraints drops and recreates the CHECK constraints listed in RefreshableConstraints.
+// AutoMigrate only creates missing constraints, so an existing database would keep rejecting
+// newly allowed values (e.g. a new transaction type) without this step.
+func RefreshConstraints(db *gorm.DB) error {
+	for _, c := range RefreshableConstraints {
+		migrator := db.Migrator()
+		if migrator.HasConstraint(c.Model, c.Name) {
+			if err := migrator.DropConstraint(c.Model, c.Name); err != nil {
+				return fmt.Errorf("failed to drop constraint %s: %w", c.Name, err)
+			}
+		}
+		if err := migrator.CreateConstraint(c.Model, c.Name); err != nil {
+			return fmt.Errorf("failed to create constraint %s: %w", c.Name, err)
+		}
+	}
+	return nil
+}
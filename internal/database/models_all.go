@@ -12,6 +12,33 @@ var AllModels = []interface{}{
 	&models.Currency{},
 	&models.Category{},
 	&models.Transaction{},
+	&models.TransactionSplit{},
 	&models.JournalEntry{},
 	&models.Account{},
+	&models.Loan{},
+	&models.CreditCard{},
+	&models.AccountingPeriod{},
+	&models.AccountingPeriodEvent{},
+	&models.Reconciliation{},
+	&models.ReconciliationItem{},
+	&models.IdempotencyKey{},
+	&models.RecurringTransaction{},
+	&models.RecurringTransactionOverride{},
+	&models.RecurringOccurrence{},
+	&models.Budget{},
+	&models.SavingsGoal{},
+	&models.SavingsGoalAccount{},
+	&models.SavingsGoalContribution{},
+	&models.Envelope{},
+	&models.EnvelopeMove{},
+}
+
+// RefreshableConstraints contains the CHECK constraints whose allowed values change over time.
+// They are recreated after every migration so existing databases pick up the new definition.
+var RefreshableConstraints = []struct {
+	Model interface{}
+	Name  string
+}{
+	{Model: &models.Transaction{}, Name: "chk_transactions_type"},
+	{Model: &models.Transaction{}, Name: "chk_transactions_status"},
 }
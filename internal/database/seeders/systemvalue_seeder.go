@@ -22,6 +22,9 @@ func (s *SystemValueSeeder) Run(db *gorm.DB) error {
 		{CatalogType: "ACCOUNT_TYPE", Value: "SAVINGS", Label: "Ahorro", Description: strPtr("Cuenta de ahorros"), DisplayOrder: 4, IsActive: true},
 		{CatalogType: "ACCOUNT_TYPE", Value: "INVESTMENT", Label: "Inversión", Description: strPtr("Cuenta de inversión"), DisplayOrder: 5, IsActive: true},
 		{CatalogType: "ACCOUNT_TYPE", Value: "CATEGORY", Label: "Categoría", Description: strPtr("Cuenta nominal para categorización"), DisplayOrder: 6, IsActive: true},
+		{CatalogType: "ACCOUNT_TYPE", Value: "EQUITY", Label: "Patrimonio", Description: strPtr("Cuenta de capital para saldos iniciales y ajustes"), DisplayOrder: 7, IsActive: true},
+		{CatalogType: "ACCOUNT_TYPE", Value: "LOAN", Label: "Préstamo", Description: strPtr("Préstamo con cuotas fijas"), DisplayOrder: 8, IsActive: true},
+		{CatalogType: "ACCOUNT_TYPE", Value: "MORTGAGE", Label: "Hipoteca", Description: strPtr("Préstamo hipotecario con cuotas fijas"), DisplayOrder: 9, IsActive: true},
 
 		// ACCOUNT CLASSIFICATION
 		{CatalogType: "ACCOUNT_CLASSIFICATION", Value: "ASSET", Label: "Activo", Description: strPtr("Recursos que generan valor"), DisplayOrder: 1, IsActive: true},
@@ -35,6 +38,8 @@ func (s *SystemValueSeeder) Run(db *gorm.DB) error {
 		{CatalogType: "TRANSACTION_TYPE", Value: "EXPENSE", Label: "Gasto", Description: strPtr("Salida de dinero"), DisplayOrder: 2, IsActive: true},
 		{CatalogType: "TRANSACTION_TYPE", Value: "TRANSFER", Label: "Transferencia", Description: strPtr("Movimiento entre cuentas"), DisplayOrder: 3, IsActive: true},
 		{CatalogType: "TRANSACTION_TYPE", Value: "DEBT_PAYMENT", Label: "Pago de Deuda", Description: strPtr("Pago de tarjeta de crédito o préstamo"), DisplayOrder: 4, IsActive: true},
+		{CatalogType: "TRANSACTION_TYPE", Value: "OPENING_BALANCE", Label: "Saldo Inicial", Description: strPtr("Saldo de apertura de una cuenta contra patrimonio"), DisplayOrder: 5, IsActive: true},
+		{CatalogType: "TRANSACTION_TYPE", Value: "ADJUSTMENT", Label: "Ajuste de Saldo", Description: strPtr("Corrección del saldo de una cuenta contra patrimonio"), DisplayOrder: 6, IsActive: true},
 
 		// CATEGORY TYPES
 		{CatalogType: "CATEGORY_TYPE", Value: "INCOME", Label: "Ingreso", Description: strPtr("Categoría de ingreso"), DisplayOrder: 1, IsActive: true},
@@ -2,8 +2,10 @@ package config
 
 import (
 	"fmt"
+	"log"
 	"os"
 	"strings"
+	"time"
 )
 
 // Config contiene toda la configuración de la aplicación
@@ -27,6 +29,14 @@ type Config struct {
 	// JWT (para futuras implementaciones)
 	JWTSecret        string
 	JWTRefreshSecret string
+
+	// Idempotency-Key: tiempo durante el cual se conserva la respuesta original para reintentos
+	IdempotencyKeyTTL time.Duration
+	// Idempotency-Key: una reserva sin respuesta más antigua que esto se considera abandonada
+	IdempotencyKeyStaleAfter time.Duration
+
+	// Programador de tareas en segundo plano (transacciones recurrentes); 0 lo desactiva
+	SchedulerInterval time.Duration
 }
 
 // Load carga la configuración desde variables de entorno
@@ -51,6 +61,13 @@ func Load() *Config {
 		// JWT
 		JWTSecret:        getEnv("JWT_SECRET", "your-default-secret-change-in-production"),
 		JWTRefreshSecret: getEnv("JWT_REFRESH_SECRET", "your-refresh-secret-change-in-production"),
+
+		// Idempotencia
+		IdempotencyKeyTTL:        parseDuration(getEnv("IDEMPOTENCY_KEY_TTL", "24h"), 24*time.Hour),
+		IdempotencyKeyStaleAfter: parseDuration(getEnv("IDEMPOTENCY_KEY_STALE_AFTER", "5m"), 5*time.Minute),
+
+		// Programador
+		SchedulerInterval: parseSchedulerInterval(getEnv("SCHEDULER_INTERVAL", "15m"), 15*time.Minute),
 	}
 }
 
@@ -79,6 +96,31 @@ func parseCorsOrigins(origins string) []string {
 	return result
 }
 
+// parseDuration parsea una duración de Go (p. ej. "24h", "90m") o retorna el valor por defecto si es inválida
+func parseDuration(value string, defaultValue time.Duration) time.Duration {
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return defaultValue
+	}
+
+	return duration
+}
+
+// parseSchedulerInterval parsea el intervalo del programador. Solo un 0 explícito lo desactiva;
+// un valor inválido o negativo se avisa en el log y se usa el valor por defecto
+func parseSchedulerInterval(value string, defaultValue time.Duration) time.Duration {
+	duration, err := time.ParseDuration(value)
+	if err == nil && duration == 0 {
+		return 0
+	}
+	if err != nil || duration < 0 {
+		log.Printf("⚠️  WARNING: invalid SCHEDULER_INTERVAL %q, using %s", value, defaultValue)
+		return defaultValue
+	}
+
+	return duration
+}
+
 // IsDevelopment retorna true si el entorno es desarrollo
 func (c *Config) IsDevelopment() bool {
 	return c.Environment == "development"
@@ -0,0 +1,96 @@
+package scheduler
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// Job is a periodic background task. Run must be safe to execute concurrently with other
+// server instances running the same job (jobs rely on database constraints, not on this process).
+type Job struct {
+	Name string
+	Run  func() error
+}
+
+// Scheduler runs its jobs once at start and then on every tick, one after the other
+type Scheduler struct {
+	interval time.Duration
+	jobs     []Job
+	stop     chan struct{}
+	done     chan struct{}
+
+	mu      sync.Mutex
+	started bool
+	stopped bool
+}
+
+// New creates a scheduler that runs the jobs every interval
+func New(interval time.Duration, jobs ...Job) *Scheduler {
+	return &Scheduler{
+		interval: interval,
+		jobs:     jobs,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start runs the scheduler in the background until Stop is called
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started || s.stopped {
+		return
+	}
+	s.started = true
+
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		s.runJobs()
+		for {
+			select {
+			case <-ticker.C:
+				s.runJobs()
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+	log.Printf("⏱️  Scheduler started: %d job(s) every %s", len(s.jobs), s.interval)
+}
+
+// Stop stops the scheduler and waits for the jobs in progress to finish
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return
+	}
+	s.stopped = true
+
+	close(s.stop)
+	if s.started {
+		<-s.done
+	}
+}
+
+// runJobs runs every job once; a failing or panicking job does not stop the others
+func (s *Scheduler) runJobs() {
+	for _, job := range s.jobs {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("❌ Scheduled job %q panicked: %v", job.Name, r)
+				}
+			}()
+
+			if err := job.Run(); err != nil {
+				log.Printf("❌ Scheduled job %q failed: %v", job.Name, err)
+			}
+		}()
+	}
+}
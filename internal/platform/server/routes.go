@@ -14,6 +14,8 @@ import (
 func registerRoutes(
 	router *gin.Engine,
 	authMiddleware *middleware.AuthMiddleware,
+	adminMiddleware *middleware.AdminMiddleware,
+	idempotencyMiddleware *middleware.IdempotencyMiddleware,
 	healthHandler *handlers.HealthHandler,
 	authHandler *handlers.AuthHandler,
 	userHandler *handlers.UserHandler,
@@ -24,6 +26,15 @@ func registerRoutes(
 	systemValueHandler *handlers.SystemValueHandler,
 	journalEntryHandler *handlers.JournalEntryHandler,
 	dashboardHandler *handlers.DashboardHandler,
+	accountingPeriodHandler *handlers.AccountingPeriodHandler,
+	ledgerHandler *handlers.LedgerHandler,
+	reportHandler *handlers.ReportHandler,
+	reconciliationHandler *handlers.ReconciliationHandler,
+	recurringHandler *handlers.RecurringTransactionHandler,
+	budgetHandler *handlers.BudgetHandler,
+	savingsGoalHandler *handlers.SavingsGoalHandler,
+	envelopeHandler *handlers.EnvelopeHandler,
+	debtHandler *handlers.DebtHandler,
 ) {
 	// Swagger UI → /swagger/index.html  (swaggo por defecto)
 	// /docs      → redirect conveniente a /swagger/index.html
@@ -40,17 +51,25 @@ func registerRoutes(
 			"version":     "v1.0.0 - Phase 1",
 			"description": "Personal Financial Management System with Double-Entry Bookkeeping",
 			"endpoints": gin.H{
-				"docs":            "/swagger/index.html",
-				"health":          "/api/v1/health",
-				"auth":            "/api/v1/auth",
-				"dashboard":       "/api/v1/dashboard",
-				"users":           "/api/v1/users",
-				"accounts":        "/api/v1/accounts",
-				"transactions":    "/api/v1/transactions",
-				"categories":      "/api/v1/categories",
-				"currencies":      "/api/v1/currencies",
-				"system_values":   "/api/v1/system-values",
-				"journal_entries": "/api/v1/journal-entries",
+				"docs":                   "/swagger/index.html",
+				"health":                 "/api/v1/health",
+				"auth":                   "/api/v1/auth",
+				"dashboard":              "/api/v1/dashboard",
+				"users":                  "/api/v1/users",
+				"accounts":               "/api/v1/accounts",
+				"transactions":           "/api/v1/transactions",
+				"categories":             "/api/v1/categories",
+				"currencies":             "/api/v1/currencies",
+				"system_values":          "/api/v1/system-values",
+				"journal_entries":        "/api/v1/journal-entries",
+				"accounting_periods":     "/api/v1/accounting-periods",
+				"reports":                "/api/v1/reports",
+				"reconciliations":        "/api/v1/reconciliations",
+				"recurring_transactions": "/api/v1/recurring-transactions",
+				"budgets":                "/api/v1/budgets",
+				"savings_goals":          "/api/v1/savings-goals",
+				"envelopes":              "/api/v1/envelopes",
+				"debts":                  "/api/v1/debts",
 			},
 		})
 	})
@@ -105,6 +124,7 @@ func registerRoutes(
 			dashboard.GET("", dashboardHandler.GetDashboard)
 			dashboard.GET("/runway", dashboardHandler.GetRunway)
 			dashboard.GET("/monthly-stats", dashboardHandler.GetMonthlyStats)
+			dashboard.GET("/projection", dashboardHandler.GetProjection)
 		}
 
 		// User routes
@@ -121,9 +141,14 @@ func registerRoutes(
 		accounts := protected.Group("/accounts")
 		{
 			accounts.GET("", accountHandler.GetAccounts)
-			accounts.POST("", accountHandler.CreateAccount)
+			accounts.POST("", idempotencyMiddleware.Idempotent(), accountHandler.CreateAccount)
 			accounts.GET("/:id", accountHandler.GetAccountByID)
 			accounts.PUT("/:id", accountHandler.UpdateAccount)
+			accounts.POST("/:id/balance-adjustment", accountHandler.AdjustAccountBalance)
+			accounts.GET("/:id/ledger", journalEntryHandler.GetAccountLedger)
+			accounts.GET("/:id/amortization", accountHandler.GetAmortizationSchedule)
+			accounts.PUT("/:id/credit-card", accountHandler.SetCreditCardTerms)
+			accounts.GET("/:id/statement", accountHandler.GetAccountStatement)
 			accounts.DELETE("/:id", accountHandler.DeleteAccount)
 		}
 
@@ -131,17 +156,20 @@ func registerRoutes(
 		transactions := protected.Group("/transactions")
 		{
 			transactions.GET("", transactionHandler.GetTransactions)
-			transactions.POST("", transactionHandler.CreateTransaction)
+			transactions.POST("", idempotencyMiddleware.Idempotent(), transactionHandler.CreateTransaction)
+			transactions.POST("/batch", idempotencyMiddleware.Idempotent(), transactionHandler.CreateTransactionBatch)
 			transactions.GET("/:id", transactionHandler.GetTransactionByID)
+			transactions.GET("/:id/history", transactionHandler.GetTransactionHistory)
 			transactions.PUT("/:id", transactionHandler.UpdateTransaction)
 			transactions.DELETE("/:id", transactionHandler.DeleteTransaction)
+			transactions.POST("/:id/confirm", transactionHandler.ConfirmTransaction)
 		}
 
 		// Category routes
 		categories := protected.Group("/categories")
 		{
 			categories.GET("", categoryHandler.GetCategories)
-			categories.POST("", categoryHandler.CreateCategory)
+			categories.POST("", idempotencyMiddleware.Idempotent(), categoryHandler.CreateCategory)
 			categories.GET("/:id", categoryHandler.GetCategoryByID)
 			categories.PUT("/:id", categoryHandler.UpdateCategory)
 			categories.DELETE("/:id", categoryHandler.DeleteCategory)
@@ -154,5 +182,100 @@ func registerRoutes(
 			journalEntries.GET("/transaction/:id", journalEntryHandler.GetJournalEntriesByTransaction)
 			journalEntries.GET("/verify/:id", journalEntryHandler.VerifyTransactionBalance)
 		}
+
+		// Accounting period routes (close/reopen months; enforced by the Accounting Engine)
+		accountingPeriods := protected.Group("/accounting-periods")
+		{
+			accountingPeriods.GET("", accountingPeriodHandler.GetAccountingPeriods)
+			accountingPeriods.POST("/close", accountingPeriodHandler.CloseAccountingPeriod)
+			accountingPeriods.POST("/reopen", accountingPeriodHandler.ReopenAccountingPeriod)
+			accountingPeriods.GET("/:id/events", accountingPeriodHandler.GetAccountingPeriodEvents)
+		}
+
+		// Report routes (financial statements built from the journal)
+		reports := protected.Group("/reports")
+		{
+			reports.GET("/trial-balance", reportHandler.GetTrialBalance)
+			reports.GET("/income-statement", reportHandler.GetIncomeStatement)
+			reports.GET("/balance-sheet", reportHandler.GetBalanceSheet)
+			reports.GET("/cash-flow", reportHandler.GetCashFlow)
+		}
+
+		// Reconciliation routes (match accounts against bank/card statements)
+		reconciliations := protected.Group("/reconciliations")
+		{
+			reconciliations.GET("", reconciliationHandler.GetReconciliations)
+			reconciliations.POST("", reconciliationHandler.StartReconciliation)
+			reconciliations.GET("/:id", reconciliationHandler.GetReconciliationByID)
+			reconciliations.PUT("/:id/cleared", reconciliationHandler.SetClearedTransactions)
+			reconciliations.POST("/:id/complete", reconciliationHandler.CompleteReconciliation)
+			reconciliations.DELETE("/:id", reconciliationHandler.CancelReconciliation)
+		}
+
+		// Recurring transaction routes (templates materialized by the scheduler)
+		recurring := protected.Group("/recurring-transactions")
+		{
+			recurring.GET("", recurringHandler.GetRecurringTransactions)
+			recurring.POST("", recurringHandler.CreateRecurringTransaction)
+			recurring.GET("/:id", recurringHandler.GetRecurringTransactionByID)
+			recurring.PUT("/:id", recurringHandler.UpdateRecurringTransaction)
+			recurring.DELETE("/:id", recurringHandler.DeleteRecurringTransaction)
+			recurring.GET("/:id/occurrences", recurringHandler.GetRecurringOccurrences)
+			recurring.PUT("/:id/overrides", recurringHandler.SetRecurringOverride)
+			recurring.DELETE("/:id/overrides/:overrideId", recurringHandler.DeleteRecurringOverride)
+		}
+
+		// Budget routes (spending per category and period)
+		budgets := protected.Group("/budgets")
+		{
+			budgets.GET("", budgetHandler.GetBudgets)
+			budgets.POST("", budgetHandler.CreateBudget)
+			budgets.GET("/summary", budgetHandler.GetBudgetSummary)
+			budgets.GET("/:id", budgetHandler.GetBudgetByID)
+			budgets.GET("/:id/periods", budgetHandler.GetBudgetPeriods)
+			budgets.PUT("/:id", budgetHandler.UpdateBudget)
+			budgets.DELETE("/:id", budgetHandler.DeleteBudget)
+		}
+
+		// Savings goal routes (linked SAVINGS accounts and earmarked balances)
+		savingsGoals := protected.Group("/savings-goals")
+		{
+			savingsGoals.GET("", savingsGoalHandler.GetSavingsGoals)
+			savingsGoals.POST("", savingsGoalHandler.CreateSavingsGoal)
+			savingsGoals.GET("/summary", savingsGoalHandler.GetSavingsGoalSummary)
+			savingsGoals.GET("/:id", savingsGoalHandler.GetSavingsGoalByID)
+			savingsGoals.PUT("/:id", savingsGoalHandler.UpdateSavingsGoal)
+			savingsGoals.DELETE("/:id", savingsGoalHandler.DeleteSavingsGoal)
+			savingsGoals.POST("/:id/accounts", savingsGoalHandler.LinkSavingsGoalAccount)
+			savingsGoals.DELETE("/:id/accounts/:accountId", savingsGoalHandler.UnlinkSavingsGoalAccount)
+			savingsGoals.POST("/:id/contributions", savingsGoalHandler.CreateSavingsGoalContribution)
+		}
+
+		// Envelope routes (zero-based allocation of the liquid funds)
+		envelopes := protected.Group("/envelopes")
+		{
+			envelopes.GET("", envelopeHandler.GetEnvelopes)
+			envelopes.POST("", envelopeHandler.CreateEnvelope)
+			envelopes.GET("/summary", envelopeHandler.GetEnvelopeSummary)
+			envelopes.GET("/moves", envelopeHandler.GetEnvelopeMoves)
+			envelopes.POST("/moves", envelopeHandler.MoveEnvelopeFunds)
+			envelopes.GET("/:id", envelopeHandler.GetEnvelopeByID)
+			envelopes.DELETE("/:id", envelopeHandler.DeleteEnvelope)
+		}
+
+		// Debt routes (all liability accounts together)
+		debts := protected.Group("/debts")
+		{
+			debts.GET("/upcoming", debtHandler.GetUpcomingDebts)
+			debts.POST("/payoff-simulation", debtHandler.SimulateDebtPayoff)
+		}
+
+		// Admin routes (super administrators only)
+		admin := protected.Group("/admin")
+		admin.Use(adminMiddleware.RequireSuperAdmin())
+		{
+			admin.GET("/ledger/verify", ledgerHandler.VerifyLedger)
+			admin.POST("/ledger/rebuild", ledgerHandler.RebuildLedgerBalances)
+		}
 	}
 }
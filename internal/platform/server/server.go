@@ -10,6 +10,7 @@ import (
 	"arabella-api/internal/app/repositories"
 	"arabella-api/internal/app/services"
 	"arabella-api/internal/platform/config"
+	"arabella-api/internal/platform/scheduler"
 	"arabella-api/internal/shared/middleware"
 
 	"github.com/gin-gonic/gin"
@@ -21,6 +22,7 @@ type Server struct {
 	config     *config.Config
 	router     *gin.Engine
 	httpServer *http.Server
+	scheduler  *scheduler.Scheduler // nil when SCHEDULER_INTERVAL is 0
 }
 
 // New creates a new server instance with all dependencies injected
@@ -48,6 +50,13 @@ func New(cfg *config.Config, db *gorm.DB) *Server {
 	categoryRepo := repositories.NewCategoryRepository(db)
 	currencyRepo := repositories.NewCurrencyRepository(db)
 	systemValueRepo := repositories.NewSystemValueRepository(db)
+	accountingPeriodRepo := repositories.NewAccountingPeriodRepository(db)
+	reconciliationRepo := repositories.NewReconciliationRepository(db)
+	idempotencyKeyRepo := repositories.NewIdempotencyKeyRepository(db)
+	recurringRepo := repositories.NewRecurringTransactionRepository(db)
+	budgetRepo := repositories.NewBudgetRepository(db)
+	savingsGoalRepo := repositories.NewSavingsGoalRepository(db)
+	envelopeRepo := repositories.NewEnvelopeRepository(db)
 
 	// Create services (injecting repositories)
 	jwtService := services.NewJWTService(cfg.JWTSecret, cfg.JWTRefreshSecret)
@@ -55,15 +64,26 @@ func New(cfg *config.Config, db *gorm.DB) *Server {
 	authService := services.NewAuthService(userRepo, jwtService, db)
 	accountingEngine := services.NewAccountingEngineService(db, journalEntryRepo, accountRepo, transactionRepo)
 	transactionService := services.NewTransactionService(transactionRepo, accountingEngine)
-	accountService := services.NewAccountService(accountRepo, systemValueRepo)
-	dashboardService := services.NewDashboardService(accountRepo, transactionRepo)
+	accountService := services.NewAccountService(accountRepo, systemValueRepo, journalEntryRepo, accountingEngine)
+	budgetService := services.NewBudgetService(budgetRepo, categoryRepo)
+	savingsGoalService := services.NewSavingsGoalService(savingsGoalRepo, accountRepo, journalEntryRepo)
+	dashboardService := services.NewDashboardService(accountRepo, transactionRepo, accountingEngine, budgetService, savingsGoalService)
+	envelopeService := services.NewEnvelopeService(envelopeRepo, categoryRepo)
+	debtService := services.NewDebtService(accountRepo, journalEntryRepo)
 	categoryService := services.NewCategoryService(categoryRepo)
 	currencyService := services.NewCurrencyService(currencyRepo)
 	systemValueService := services.NewSystemValueService(systemValueRepo)
-	journalEntryService := services.NewJournalEntryService(journalEntryRepo)
+	journalEntryService := services.NewJournalEntryService(journalEntryRepo, accountRepo)
+	accountingPeriodService := services.NewAccountingPeriodService(accountingPeriodRepo)
+	ledgerService := services.NewLedgerService(db, journalEntryRepo, accountingEngine)
+	reportService := services.NewReportService(journalEntryRepo, accountRepo, categoryRepo, systemValueRepo)
+	reconciliationService := services.NewReconciliationService(reconciliationRepo, accountRepo)
+	recurringService := services.NewRecurringTransactionService(recurringRepo, accountRepo, categoryRepo, accountingEngine)
 
 	// Create middleware
 	authMiddleware := middleware.NewAuthMiddleware(jwtService)
+	adminMiddleware := middleware.NewAdminMiddleware(userRepo)
+	idempotencyMiddleware := middleware.NewIdempotencyMiddleware(idempotencyKeyRepo, cfg.IdempotencyKeyTTL, cfg.IdempotencyKeyStaleAfter)
 
 	// Create handlers (injecting services)
 	healthHandler := handlers.NewHealthHandler()
@@ -76,6 +96,15 @@ func New(cfg *config.Config, db *gorm.DB) *Server {
 	currencyHandler := handlers.NewCurrencyHandler(currencyService)
 	systemValueHandler := handlers.NewSystemValueHandler(systemValueService)
 	journalEntryHandler := handlers.NewJournalEntryHandler(journalEntryService)
+	accountingPeriodHandler := handlers.NewAccountingPeriodHandler(accountingPeriodService)
+	ledgerHandler := handlers.NewLedgerHandler(ledgerService)
+	reportHandler := handlers.NewReportHandler(reportService)
+	reconciliationHandler := handlers.NewReconciliationHandler(reconciliationService)
+	recurringHandler := handlers.NewRecurringTransactionHandler(recurringService)
+	budgetHandler := handlers.NewBudgetHandler(budgetService)
+	savingsGoalHandler := handlers.NewSavingsGoalHandler(savingsGoalService)
+	envelopeHandler := handlers.NewEnvelopeHandler(envelopeService)
+	debtHandler := handlers.NewDebtHandler(debtService)
 
 	// Create Gin router
 	router := gin.Default()
@@ -87,6 +116,8 @@ func New(cfg *config.Config, db *gorm.DB) *Server {
 	registerRoutes(
 		router,
 		authMiddleware,
+		adminMiddleware,
+		idempotencyMiddleware,
 		healthHandler,
 		authHandler,
 		userHandler,
@@ -97,6 +128,15 @@ func New(cfg *config.Config, db *gorm.DB) *Server {
 		systemValueHandler,
 		journalEntryHandler,
 		dashboardHandler,
+		accountingPeriodHandler,
+		ledgerHandler,
+		reportHandler,
+		reconciliationHandler,
+		recurringHandler,
+		budgetHandler,
+		savingsGoalHandler,
+		envelopeHandler,
+		debtHandler,
 	)
 
 	// Configure HTTP server
@@ -108,20 +148,62 @@ func New(cfg *config.Config, db *gorm.DB) *Server {
 		IdleTimeout:  60 * time.Second,
 	}
 
+	// Background jobs
+	var jobs *scheduler.Scheduler
+	if cfg.SchedulerInterval > 0 {
+		jobs = scheduler.New(cfg.SchedulerInterval,
+			scheduler.Job{
+				Name: "recurring transactions",
+				Run: func() error {
+					report, err := recurringService.RunDue(time.Now(), nil)
+					if err != nil {
+						return err
+					}
+					if report.Posted > 0 || report.Skipped > 0 || len(report.Failures) > 0 {
+						log.Printf("🔁 Recurring transactions: %d posted, %d skipped, %d failed",
+							report.Posted, report.Skipped, len(report.Failures))
+					}
+					return nil
+				},
+			},
+			scheduler.Job{
+				Name: "pending transactions",
+				Run: func() error {
+					report, err := transactionService.PostDuePending(time.Now(), nil)
+					if err != nil {
+						return err
+					}
+					if report.Posted > 0 || len(report.Failures) > 0 {
+						log.Printf("⏳ Pending transactions: %d posted, %d failed",
+							report.Posted, len(report.Failures))
+					}
+					return nil
+				},
+			},
+		)
+	}
+
 	return &Server{
 		config:     cfg,
 		router:     router,
 		httpServer: httpServer,
+		scheduler:  jobs,
 	}
 }
 
-// Start starts the HTTP server
+// Start starts the background scheduler and the HTTP server
 func (s *Server) Start() error {
+	if s.scheduler != nil {
+		s.scheduler.Start()
+	}
 	return s.httpServer.ListenAndServe()
 }
 
-// Shutdown gracefully shuts down the server
+// Shutdown gracefully shuts down the server, letting running background jobs finish
 func (s *Server) Shutdown(ctx context.Context) error {
+	if s.scheduler != nil {
+		s.scheduler.Stop()
+	}
 	return s.httpServer.Shutdown(ctx)
 }
 
@@ -142,7 +224,7 @@ func corsMiddleware(allowedOrigins []string) gin.HandlerFunc {
 		if allowed {
 			c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
 			c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-			c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Company-ID")
+			c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Company-ID, Idempotency-Key")
 			c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE, PATCH")
 		}
 
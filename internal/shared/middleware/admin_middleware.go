@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+
+	"arabella-api/internal/app/repositories"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminMiddleware restricts routes to super administrators
+// It must run after AuthMiddleware.RequireAuth, which sets "user_id" in the context
+type AdminMiddleware struct {
+	userRepo repositories.UserRepository
+}
+
+// NewAdminMiddleware creates a new admin middleware
+func NewAdminMiddleware(userRepo repositories.UserRepository) *AdminMiddleware {
+	return &AdminMiddleware{
+		userRepo: userRepo,
+	}
+}
+
+// RequireSuperAdmin middleware that only lets active users flagged IsSuperAdmin through.
+// The flag is read from the database on every request, so revoking it takes effect immediately.
+func (m *AdminMiddleware) RequireSuperAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, exists := c.Get("user_id")
+		userID, ok := value.(uint)
+		if !exists || !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			c.Abort()
+			return
+		}
+
+		user, err := m.userRepo.FindByID(userID)
+		if err != nil || !user.IsActive || !user.IsSuperAdmin {
+			c.JSON(http.StatusForbidden, gin.H{"error": "super administrator privileges required"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
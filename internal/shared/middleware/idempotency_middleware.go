@@ -0,0 +1,165 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"arabella-api/internal/app/models"
+	"arabella-api/internal/app/repositories"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IdempotencyKeyHeader is the request header clients set to make a create request safe to retry
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader is set on responses replayed from a previous request with the same key
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// IdempotencyMiddleware makes create endpoints safe to retry.
+// It must run after AuthMiddleware.RequireAuth: keys are scoped to the user in "user_id".
+type IdempotencyMiddleware struct {
+	idempotencyKeyRepo repositories.IdempotencyKeyRepository
+	retention          time.Duration
+	staleAfter         time.Duration
+}
+
+// NewIdempotencyMiddleware creates a new idempotency middleware that keeps responses for the retention window.
+// A reservation still without a response after staleAfter is considered abandoned and can be claimed again.
+func NewIdempotencyMiddleware(idempotencyKeyRepo repositories.IdempotencyKeyRepository, retention, staleAfter time.Duration) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{
+		idempotencyKeyRepo: idempotencyKeyRepo,
+		retention:          retention,
+		staleAfter:         staleAfter,
+	}
+}
+
+// Idempotent middleware that honours the Idempotency-Key header (requests without it run normally).
+// The first request with a key runs and its response is stored; a repeat within the retention window
+// replays that response without running the handler again. Reusing the key for a different request,
+// or while the original is still running, is rejected with 409. Server errors (5xx) are not stored,
+// so the client can retry them with the same key; neither is a response that could not be saved.
+// A reservation left behind by a request that died is freed once it is older than staleAfter.
+func (m *IdempotencyMiddleware) Idempotent() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := strings.TrimSpace(c.GetHeader(IdempotencyKeyHeader))
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		if len(key) > 255 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key cannot exceed 255 characters"})
+			c.Abort()
+			return
+		}
+
+		value, exists := c.Get("user_id")
+		userID, ok := value.(uint)
+		if !exists || !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read request body", "details": err.Error()})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		record := &models.IdempotencyKey{
+			UserID:      userID,
+			Key:         key,
+			Method:      c.Request.Method,
+			Path:        c.FullPath(),
+			RequestHash: requestHash(c.Request.Method, c.FullPath(), body),
+			ExpiresAt:   time.Now().Add(m.retention),
+		}
+
+		existing, err := m.idempotencyKeyRepo.Reserve(record, time.Now().Add(-m.staleAfter))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to check Idempotency-Key", "details": err.Error()})
+			c.Abort()
+			return
+		}
+
+		if existing != nil {
+			switch {
+			case existing.RequestHash != record.RequestHash:
+				c.JSON(http.StatusConflict, gin.H{"error": "Idempotency-Key was already used for a different request"})
+			case !existing.IsCompleted():
+				c.JSON(http.StatusConflict, gin.H{"error": "a request with this Idempotency-Key is still being processed"})
+			default:
+				c.Header(IdempotentReplayedHeader, "true")
+				c.Data(existing.StatusCode, existing.ContentType, []byte(existing.ResponseBody))
+			}
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		// A panicking handler produced no response worth replaying: free the key for a retry
+		defer func() {
+			if r := recover(); r != nil {
+				_ = m.idempotencyKeyRepo.Release(record)
+				panic(r)
+			}
+		}()
+
+		c.Next()
+
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			if err := m.idempotencyKeyRepo.Release(record); err != nil {
+				log.Printf("⚠️  Failed to release Idempotency-Key %q of user %d: %v", key, userID, err)
+			}
+			return
+		}
+
+		record.StatusCode = status
+		record.ContentType = recorder.Header().Get("Content-Type")
+		record.ResponseBody = recorder.body.String()
+		if err := m.idempotencyKeyRepo.Complete(record); err != nil {
+			log.Printf("⚠️  Failed to store response for Idempotency-Key %q of user %d: %v", key, userID, err)
+			// Without a stored response the reservation would block every retry until it goes stale
+			if err := m.idempotencyKeyRepo.Release(record); err != nil {
+				log.Printf("⚠️  Failed to release Idempotency-Key %q of user %d: %v", key, userID, err)
+			}
+		}
+	}
+}
+
+// requestHash fingerprints a request so a reused key can be told apart from a genuine retry
+func requestHash(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder copies the response body while it is written to the client
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}